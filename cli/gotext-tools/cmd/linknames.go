@@ -4,10 +4,15 @@ import (
 	_ "unsafe"
 
 	_ "github.com/Tom5521/gotext-tools/v2/cli/msgocat/cmd"
+	_ "github.com/Tom5521/gotext-tools/v2/cli/msgoconv/cmd"
+	_ "github.com/Tom5521/gotext-tools/v2/cli/msgodiff/cmd"
 	_ "github.com/Tom5521/gotext-tools/v2/cli/msgofmt/cmd"
 	_ "github.com/Tom5521/gotext-tools/v2/cli/msgomerge/cmd"
+	_ "github.com/Tom5521/gotext-tools/v2/cli/msgopretranslate/cmd"
 	_ "github.com/Tom5521/gotext-tools/v2/cli/msgounfmt/cmd"
+	_ "github.com/Tom5521/gotext-tools/v2/cli/po2xliff/cmd"
 	_ "github.com/Tom5521/gotext-tools/v2/cli/xgotext/cmd"
+	_ "github.com/Tom5521/gotext-tools/v2/cli/xliff2po/cmd"
 	"github.com/spf13/cobra"
 )
 
@@ -16,11 +21,21 @@ import (
 //go:linkname xgotext github.com/Tom5521/gotext-tools/v2/cli/xgotext/cmd.root
 //go:linkname msgounfmt github.com/Tom5521/gotext-tools/v2/cli/msgounfmt/cmd.root
 //go:linkname msgocat github.com/Tom5521/gotext-tools/v2/cli/msgocat/cmd.root
+//go:linkname msgoconv github.com/Tom5521/gotext-tools/v2/cli/msgoconv/cmd.root
+//go:linkname msgodiff github.com/Tom5521/gotext-tools/v2/cli/msgodiff/cmd.root
+//go:linkname msgopretranslate github.com/Tom5521/gotext-tools/v2/cli/msgopretranslate/cmd.root
+//go:linkname po2xliff github.com/Tom5521/gotext-tools/v2/cli/po2xliff/cmd.root
+//go:linkname xliff2po github.com/Tom5521/gotext-tools/v2/cli/xliff2po/cmd.root
 
 var (
-	msgofmt   *cobra.Command
-	msgomerge *cobra.Command
-	msgounfmt *cobra.Command
-	xgotext   *cobra.Command
-	msgocat   *cobra.Command
+	msgofmt          *cobra.Command
+	msgomerge        *cobra.Command
+	msgounfmt        *cobra.Command
+	xgotext          *cobra.Command
+	msgocat          *cobra.Command
+	msgoconv         *cobra.Command
+	msgodiff         *cobra.Command
+	msgopretranslate *cobra.Command
+	po2xliff         *cobra.Command
+	xliff2po         *cobra.Command
 )
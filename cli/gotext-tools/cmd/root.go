@@ -14,7 +14,8 @@ var root = &cobra.Command{
 
 func init() {
 	root.AddCommand(
-		msgofmt, msgomerge, xgotext, msgounfmt, msgocat,
+		msgofmt, msgomerge, xgotext, msgounfmt, msgocat, msgoconv, msgodiff, msgopretranslate,
+		po2xliff, xliff2po,
 
 		docs, docTree)
 }
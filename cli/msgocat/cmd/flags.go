@@ -28,6 +28,7 @@ var (
 	noLocation  bool
 	addLocation string
 	noWrap      bool
+	width       int
 	sortOutput  bool
 	sortByFile  bool
 )
@@ -83,6 +84,7 @@ message, don't merge several translations`)
 	)
 	flags.BoolVar(&noWrap, "no-wrap", false, `do not break long message lines, longer than
 the output page width, into several lines`)
+	flags.IntVarP(&width, "width", "w", compile.DefaultWidth, `set output page width`)
 	flags.BoolVarP(&sortOutput, "sort-output", "s", false, `generate sorted output`)
 	flags.BoolVarP(&sortByFile, "sort-by-file", "F", false, `sort output by file location`)
 
@@ -111,8 +113,12 @@ func initCfg(cmd *cobra.Command, args []string) error {
 		mergeCfg.LessThan = 2
 	}
 
+	if noWrap {
+		width = -1
+	}
 	compilerCfg.ApplyOptions(
-		compile.PoWithWordWrap(!noWrap),
+		compile.PoWithWordWrap(true),
+		compile.PoWithWidth(width),
 		compile.PoWithNoLocation(noLocation),
 	)
 
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po/compile"
+	"github.com/spf13/cobra"
+)
+
+var (
+	toCode     string
+	output     string
+	noWrap     bool
+	width      int
+	sortOutput bool
+)
+
+func init() {
+	flags := root.Flags()
+
+	flags.StringVarP(&toCode, "to-code", "t", "UTF-8", `encoding for output`)
+	flags.StringVarP(&output, "output-file", "o", "-", `write output to specified file
+The results are written to standard output if no output file is specified
+or if it is -.`)
+	flags.BoolVar(&noWrap, "no-wrap", false, `do not break long message lines, longer than
+the output page width, into several lines`)
+	flags.IntVarP(&width, "width", "w", compile.DefaultWidth, `set output page width`)
+	flags.BoolVarP(&sortOutput, "sort-output", "s", false, `generate sorted output`)
+}
+
+var compilerCfg = compile.DefaultPoConfig()
+
+func initCfg(cmd *cobra.Command, args []string) error {
+	if !po.IsCharsetSupported(toCode) {
+		return fmt.Errorf("the %q charset is not supported", toCode)
+	}
+
+	if noWrap {
+		width = -1
+	}
+	compilerCfg.ApplyOptions(
+		compile.PoWithWordWrap(true),
+		compile.PoWithWidth(width),
+		compile.PoWithCharset(toCode),
+	)
+
+	return nil
+}
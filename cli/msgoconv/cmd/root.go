@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po/compile"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po/parse"
+	"github.com/spf13/cobra"
+)
+
+var use = "msgoconv"
+
+var root = &cobra.Command{
+	Aliases: []string{"msgconv", "conv"},
+	Use:     use,
+	Short:   `Converts a translation catalog to a different character encoding.`,
+	Long: `Usage: msgoconv [OPTION] [INPUTFILE]
+
+Converts a translation catalog to a different character encoding.
+The charset of the input is taken from the Content-Type field of its header.
+If input file is -, standard input is read.
+
+Mandatory arguments to long options are mandatory for short options too.`,
+	Example: fmt.Sprintf(`%s -t UTF-8 legacy-ru.po -o ru.po
+%s --to-code=ISO-8859-1 es.po -o es-latin1.po
+%s -t EUC-JP - < ja.po`,
+		use, use, use),
+	Args:    cobra.ExactArgs(1),
+	PreRunE: initCfg,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var file *po.File
+		if args[0] == "-" {
+			file, err = parse.PoFromReader(os.Stdin, "stdin")
+		} else {
+			file, err = parse.Po(args[0])
+		}
+		if err != nil {
+			return fmt.Errorf("error parsing PO file (%s): %w", args[0], err)
+		}
+
+		if errs := file.CheckCharset(toCode); len(errs) > 0 {
+			for _, err := range errs {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			return fmt.Errorf("%d entries can't be converted to %s", len(errs), toCode)
+		}
+
+		if sortOutput {
+			file.Entries = file.Sort()
+		}
+
+		var out io.Writer = os.Stdout
+		if output != "-" {
+			var f *os.File
+			f, err = os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+			if err != nil {
+				return fmt.Errorf("error opening output file %s: %w", output, err)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		return compile.PoToWriter(file, out, compile.PoWithConfig(compilerCfg))
+	},
+}
+
+func Execute() {
+	err := root.Execute()
+	if err != nil {
+		os.Exit(1)
+	}
+}
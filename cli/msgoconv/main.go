@@ -0,0 +1,7 @@
+package main
+
+import "github.com/Tom5521/gotext-tools/v2/cli/msgoconv/cmd"
+
+func main() {
+	cmd.Execute()
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	formatHuman   = "human"
+	formatUnified = "unified"
+	formatJSON    = "json"
+)
+
+var (
+	output          string
+	format          string
+	ignoreLocations bool
+)
+
+func init() {
+	flags := root.Flags()
+
+	flags.StringVarP(&output, "output-file", "o", "-", `write output to specified file
+The results are written to standard output if no output file is specified
+or if it is -.`)
+	flags.StringVarP(&format, "format", "f", formatHuman,
+		`output format, one of "human", "unified" or "json"`)
+	flags.BoolVarP(&ignoreLocations, "ignore-locations", "L", false,
+		`don't report messages whose only change are their '#: filename:line' lines`)
+}
+
+func initCfg(cmd *cobra.Command, args []string) error {
+	switch format {
+	case formatHuman, formatUnified, formatJSON:
+		return nil
+	}
+	return fmt.Errorf("invalid output format %q", format)
+}
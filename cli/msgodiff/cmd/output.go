@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po/compile"
+	"github.com/rogpeppe/go-internal/diff"
+)
+
+func label(e po.Entry) string {
+	if e.IsHeader() {
+		return "header"
+	}
+	if e.HasContext() {
+		return fmt.Sprintf("%q (context %q)", e.ID, e.Context)
+	}
+	return fmt.Sprintf("%q", e.ID)
+}
+
+func writeHuman(w io.Writer, d po.Diff) error {
+	for _, ed := range d {
+		switch ed.Changes {
+		case po.DiffAdded:
+			fmt.Fprintf(w, "+ %s\n", label(ed.Entry()))
+			continue
+		case po.DiffRemoved:
+			fmt.Fprintf(w, "- %s\n", label(ed.Entry()))
+			continue
+		}
+
+		fmt.Fprintf(w, "~ %s (%s)\n", label(ed.Entry()), ed.Changes)
+		if ed.Changes.Has(po.DiffTranslation) {
+			writeTranslationChanges(w, *ed.Old, *ed.New)
+		}
+	}
+
+	changed := len(d) - d.Count(po.DiffAdded|po.DiffRemoved)
+	_, err := fmt.Fprintf(w, "%d added, %d removed, %d changed (%d only in locations).\n",
+		d.Count(po.DiffAdded), d.Count(po.DiffRemoved), changed, len(d)-len(d.IgnoreLocations()))
+	return err
+}
+
+func writeTranslationChanges(w io.Writer, old, new po.Entry) {
+	if old.Str != new.Str {
+		fmt.Fprintf(w, "    msgstr: %q -> %q\n", old.Str, new.Str)
+	}
+
+	n := len(old.Plurals)
+	if len(new.Plurals) > n {
+		n = len(new.Plurals)
+	}
+	for i := 0; i < n; i++ {
+		var oldStr, newStr string
+		if i < len(old.Plurals) {
+			oldStr = old.Plurals[i].Str
+		}
+		if i < len(new.Plurals) {
+			newStr = new.Plurals[i].Str
+		}
+		if oldStr != newStr {
+			fmt.Fprintf(w, "    msgstr[%d]: %q -> %q\n", i, oldStr, newStr)
+		}
+	}
+}
+
+func entryText(e *po.Entry) []byte {
+	if e == nil {
+		return nil
+	}
+	return []byte(strings.TrimRight(compile.PoToString(po.Entries{*e}), "\n") + "\n")
+}
+
+func writeUnified(w io.Writer, oldName, newName string, d po.Diff) error {
+	if len(d) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", oldName, newName)
+	for _, ed := range d {
+		fmt.Fprintf(w, "@@ %s @@\n", label(ed.Entry()))
+
+		lines := strings.SplitAfter(
+			string(diff.Diff(oldName, entryText(ed.Old), newName, entryText(ed.New))),
+			"\n",
+		)
+		// Skip the "diff", "---" and "+++" lines of the file header.
+		for _, line := range lines[3:] {
+			if strings.HasPrefix(line, "@@ -") {
+				continue
+			}
+			if _, err := io.WriteString(w, line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func writeJSON(w io.Writer, d po.Diff) error {
+	if d == nil {
+		d = po.Diff{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(d)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po/parse"
+	"github.com/spf13/cobra"
+)
+
+var use = "msgodiff"
+
+var root = &cobra.Command{
+	Aliases: []string{"msgdiff", "diff"},
+	Use:     use,
+	Short:   `Compares two Uniforum style .po files message by message.`,
+	Long: `Usage: msgodiff [OPTION] old.po new.po
+
+Compares two Uniforum style .po files message by message.
+Messages are paired by their msgctxt and msgid, and reported as added,
+removed or changed, with the kind of each change: translation, plural,
+flags, comments, locations or obsolete.
+If an input file is -, standard input is read.
+
+Mandatory arguments to long options are mandatory for short options too.`,
+	Example: fmt.Sprintf(`%s old/es.po es.po
+%s --ignore-locations --format=unified old/es.po es.po
+%s -f json old/es.po es.po -o changes.json`,
+		use, use, use),
+	Args:    cobra.ExactArgs(2),
+	PreRunE: initCfg,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		oldFile, err := parseFile(args[0])
+		if err != nil {
+			return err
+		}
+		newFile, err := parseFile(args[1])
+		if err != nil {
+			return err
+		}
+
+		diff := po.DiffFiles(oldFile, newFile)
+		if ignoreLocations {
+			diff = diff.IgnoreLocations()
+		}
+
+		var out io.Writer = os.Stdout
+		if output != "-" {
+			var f *os.File
+			f, err = os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+			if err != nil {
+				return fmt.Errorf("error opening output file %s: %w", output, err)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		switch format {
+		case formatUnified:
+			return writeUnified(out, args[0], args[1], diff)
+		case formatJSON:
+			return writeJSON(out, diff)
+		default:
+			return writeHuman(out, diff)
+		}
+	},
+}
+
+func parseFile(path string) (*po.File, error) {
+	var file *po.File
+	var err error
+	if path == "-" {
+		file, err = parse.PoFromReader(os.Stdin, "stdin")
+	} else {
+		file, err = parse.Po(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing PO file (%s): %w", path, err)
+	}
+
+	return file, nil
+}
+
+func Execute() {
+	err := root.Execute()
+	if err != nil {
+		os.Exit(1)
+	}
+}
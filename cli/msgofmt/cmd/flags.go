@@ -3,6 +3,7 @@ package cmd
 import (
 	"log"
 
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
 	"github.com/Tom5521/gotext-tools/v2/pkg/po/compile"
 )
 
@@ -13,6 +14,12 @@ var (
 	force       bool
 	noHashTable bool
 	verbose     bool
+	checkFormat bool
+	check       bool
+	checkHeader bool
+	checkDomain bool
+	accelerator string
+	statistics  bool
 )
 
 func init() {
@@ -29,6 +36,22 @@ If output file is -, output is written to standard output.`)
 	flags.BoolVarP(&force, "force", "f", false, "Overwrites generated files if they already exist")
 	flags.BoolVar(&noHashTable, "no-hash", false, "binary file will not include the hash table")
 	flags.BoolVarP(&verbose, "verbose", "v", false, "")
+	flags.BoolVar(&checkFormat, "check-format", true,
+		`check that the translations use the same format directives as the
+original strings (c-format, go-format, python-format, python-brace-format),
+use --check-format=false to compile anyway`)
+	flags.BoolVarP(&check, "check", "c", false,
+		`perform all the checks implied by --check-format, --check-header,
+--check-domain, and check the plural forms, the leading and trailing
+newlines and the characters of the msgids`)
+	flags.BoolVar(&checkHeader, "check-header", false, "verify presence and contents of the header entry")
+	flags.BoolVar(&checkDomain, "check-domain", false,
+		"check that the domain of the output file is suitable as file name")
+	flags.StringVar(&accelerator, "check-accelerators", "",
+		"check that the translations have exactly one keyboard accelerator mark `CHAR`\n"+
+			"when their msgid has one, CHAR defaults to '&' when omitted")
+	flags.Lookup("check-accelerators").NoOptDefVal = string(po.DefaultAcceleratorMark)
+	flags.BoolVar(&statistics, "statistics", false, "print statistics about translations")
 }
 
 var compilerCfg = compile.DefaultMoConfig()
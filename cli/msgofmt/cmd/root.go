@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/Tom5521/gotext-tools/v2/pkg/po"
 	"github.com/Tom5521/gotext-tools/v2/pkg/po/compile"
@@ -33,8 +34,12 @@ If input file is -, standard input is read.`,
 %s -D domains/es -f
 %s -D inside-this-directory es.po -o es.mo`,
 		use, use, use, use),
-	PreRun: func(cmd *cobra.Command, args []string) {
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if utf8.RuneCountInString(accelerator) > 1 {
+			return fmt.Errorf("the accelerator mark %q must be a single character", accelerator)
+		}
 		initCfg()
+		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		if directory != "" {
@@ -70,14 +75,15 @@ If input file is -, standard input is read.`,
 						if err != nil {
 							return
 						}
-						if errs := poFile.Validate(); len(errs) > 0 {
-							err = errs[0]
+						err = validate(poFile.Entries, newMo)
+						if err != nil {
 							return
 						}
 						err = compile.MoToFile(poFile, newMo)
 						if err != nil {
 							return
 						}
+						printStatistics(de.Name()+": ", poFile.Entries)
 					}
 					return
 				}
@@ -100,15 +106,65 @@ If input file is -, standard input is read.`,
 			allEntries = append(allEntries, poFile.Entries...)
 		}
 
-		if errs := allEntries.Validate(); len(errs) > 0 {
-			err = errs[0]
+		err = validate(allEntries, output)
+		if err != nil {
+			return
+		}
+
+		err = compile.MoToFile(allEntries, output, compile.MoWithConfig(compilerCfg))
+		if err != nil {
 			return
 		}
+		printStatistics("", allEntries)
 
-		return compile.MoToFile(allEntries, output, compile.MoWithConfig(compilerCfg))
+		return
 	},
 }
 
+func printStatistics(prefix string, entries po.Entries) {
+	if !statistics {
+		return
+	}
+	fmt.Fprintln(os.Stderr, prefix+entries.Stats().String())
+}
+
+func validate(entries po.Entries, outputFile string) error {
+	if errs := entries.Validate(); len(errs) > 0 {
+		return errs[0]
+	}
+
+	var checks po.Check
+	if check {
+		checks = po.CheckAll
+	} else if checkHeader {
+		checks = po.CheckHeader
+	}
+
+	var errs []error
+	if (check || checkDomain) && outputFile != "-" {
+		domain := strings.TrimSuffix(filepath.Base(outputFile), filepath.Ext(outputFile))
+		if err := po.ValidateDomain(domain); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, entries.Check(checks)...)
+	if checkFormat || check {
+		errs = append(errs, entries.CheckFormat()...)
+	}
+	if accelerator != "" {
+		errs = append(errs, entries.CheckAccelerators([]rune(accelerator)[0])...)
+	}
+
+	for _, err := range errs {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("found %d errors", len(errs))
+	}
+
+	return nil
+}
+
 func Execute() {
 	err := root.Execute()
 	if err != nil {
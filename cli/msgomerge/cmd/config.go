@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"log"
 	"os"
 
@@ -16,13 +17,40 @@ var (
 	compilerCfg compile.PoConfig
 )
 
-func initConfig(cmd *cobra.Command, args []string) {
+var matchers = map[string]po.Matcher{
+	"fstrcmp":   po.FstrcmpMatcher{},
+	"token-set": po.TokenSetMatcher{},
+	"exact":     po.ExactMatcher{},
+}
+
+var headerPolicies = map[string]po.HeaderPolicy{
+	"update":  po.HeaderUpdate,
+	"keep":    po.HeaderKeep,
+	"replace": po.HeaderReplace,
+}
+
+func initConfig(cmd *cobra.Command, args []string) error {
+	policy, ok := headerPolicies[headerPolicy]
+	if !ok {
+		return fmt.Errorf("invalid header policy %q", headerPolicy)
+	}
+	matcher, ok := matchers[fuzzyMatcher]
+	if !ok {
+		return fmt.Errorf("invalid fuzzy matcher %q", fuzzyMatcher)
+	}
+	if fuzzyThreshold < 1 || fuzzyThreshold > 100 {
+		return fmt.Errorf("the fuzzy threshold must be between 1 and 100, got %d", fuzzyThreshold)
+	}
+
+	if noWrap {
+		width = -1
+	}
 	compilerCfg = compile.PoConfig{
 		NoLocation:  noLocation,
 		AddLocation: compile.PoLocationMode(addLocation),
-		WordWrap:    !noWrap,
+		WordWrap:    true,
+		Width:       width,
 		ForcePo:     forcePo,
-		OmitHeader:  true,
 		Verbose:     verbose,
 		Logger:      log.Default(),
 	}
@@ -39,6 +67,19 @@ func initConfig(cmd *cobra.Command, args []string) {
 
 	mergeCfg = po.MergeConfig{
 		FuzzyMatch: !noFuzzyMatching,
+		Previous:   previous,
 		Sort:       true,
+		Matcher:    matcher,
+		Threshold:  fuzzyThreshold,
+
+		HeaderPolicy:   policy,
+		Language:       lang,
+		StampRevision:  stampRevision,
+		LastTranslator: lastTranslator,
+	}
+	if verbose || reportPath != "" {
+		mergeCfg.Report = &po.MergeReport{}
 	}
+
+	return nil
 }
@@ -1,13 +1,18 @@
 package cmd
 
+import (
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po/compile"
+)
+
 var (
 	directory  string
 	update     bool
 	outputPath string
+	previous   bool
 	// TODO: Finish this.
 	// backup           string
 	// suffix           string
-	// previous        	bool
 	color           string
 	noFuzzyMatching bool
 	lang            string
@@ -16,21 +21,32 @@ var (
 	addLocation     string
 	compendium      []string
 	noWrap          bool
+	width           int
 	verbose         bool
+	fuzzyThreshold  int
+	fuzzyMatcher    string
+	reportPath      string
+	headerPolicy    string
+	stampRevision   bool
+	lastTranslator  string
 )
 
 func init() {
 	flags := root.Flags()
 
-	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&verbose, "verbose", false, "print a summary of the merge and the messages that need a review")
+	flags.StringVar(&reportPath, "report", "", `write the outcome of each message and the totals
+of the merge to the specified file in JSON format`)
 	flags.BoolVar(&noWrap, "no-wrap", false, `do not break long message lines, longer than
 the output page width, into several lines`)
+	flags.IntVarP(&width, "width", "w", compile.DefaultWidth, `set output page width`)
 	flags.StringSliceVarP(
 		&compendium,
 		"compendium",
 		"C",
 		nil,
-		`additional library of message translations,
+		`additional library of message translations (PO, MO or TMX files),
+used as a translation memory for the new messages;
 may be specified more than once`,
 	)
 	flags.StringVarP(
@@ -46,9 +62,21 @@ do nothing if def.po already up to date`)
 The results are written to standard output if no output file is specified
 or if it is -.`)
 	flags.BoolVarP(&noFuzzyMatching, "no-fuzzy-matching", "N", false, `do not use fuzzy matching`)
-	// TODO: Finish this someday.
-	// flags.BoolVar(&previous, "previous", false, "keep previous msgids of translated messages")
-	flags.StringVar(&lang, "lang", "en", "set 'Language' field in the header entry")
+	flags.IntVar(&fuzzyThreshold, "fuzzy-threshold", po.DefaultFuzzyThreshold,
+		`minimum similarity, from 1 to 100, of the fuzzy matches`)
+	flags.StringVar(&fuzzyMatcher, "fuzzy-matcher", "fstrcmp", `algorithm used to measure the similarity of the messages.
+It may be 'fstrcmp' (like GNU msgmerge), 'token-set' or 'exact'`)
+	flags.BoolVar(&previous, "previous", false, "keep previous msgids of translated messages")
+	flags.StringVar(&lang, "lang", "", `set 'Language' field in the header entry if it's missing,
+the 'Plural-Forms' field is also filled from it`)
+	flags.StringVar(&headerPolicy, "header-policy", "update", `how the header of ref.pot is merged into def.po.
+POLICY may be 'update' (take Project-Id-Version, Report-Msgid-Bugs-To
+and POT-Creation-Date from ref.pot and keep the rest of def.po),
+'keep' (keep the header of def.po) or 'replace' (use the header of ref.pot)`)
+	flags.BoolVar(&stampRevision, "stamp-revision", false,
+		"update the 'PO-Revision-Date' field if the messages changed")
+	flags.StringVar(&lastTranslator, "last-translator", "",
+		"set the 'Last-Translator' field when the revision is stamped")
 	flags.BoolVar(&forcePo, "force-po", false, "write PO file even if empty")
 	flags.BoolVar(&noLocation, "no-location", false, "suppress '#: filename:line' lines")
 	flags.StringVarP(
@@ -1,14 +1,17 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/Tom5521/gotext-tools/v2/pkg/po"
 	"github.com/Tom5521/gotext-tools/v2/pkg/po/compile"
 	"github.com/Tom5521/gotext-tools/v2/pkg/po/parse"
+	"github.com/Tom5521/gotext-tools/v2/pkg/tm"
 
 	"github.com/spf13/cobra"
 )
@@ -41,7 +44,7 @@ Where an exact match cannot be found, fuzzy matching is used to produce better r
 
 		return nil
 	},
-	PreRun: initConfig,
+	PreRunE: initConfig,
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		defPath, refPath := args[0], args[1]
 
@@ -84,17 +87,19 @@ Where an exact match cannot be found, fuzzy matching is used to produce better r
 			return err
 		}
 
-		// Read compendiums.
-		for _, comp := range compendium {
-			var c *po.File
-			c, err = parse.Po(comp)
-			if err != nil {
+		// The compendiums are a translation memory for the new messages,
+		// the ones without a Language are in the language of def.po.
+		if len(compendium) > 0 {
+			header := def.Header()
+			target := strings.TrimSpace(header.Load("Language"))
+			if target == "" {
+				target = mergeCfg.Language
+			}
+			memory := tm.New(tm.WithMatcher(mergeCfg.Matcher), tm.WithTargetLang(target))
+			if err = memory.AddFiles(compendium...); err != nil {
 				return err
 			}
-			ref.Entries = append(ref.Entries, c.Entries...)
-		}
-		if len(compendium) > 0 {
-			ref.Entries = ref.Solve()
+			mergeCfg.Memory = memory
 		}
 
 		if update {
@@ -113,11 +118,39 @@ Where an exact match cannot be found, fuzzy matching is used to produce better r
 			Name:    outputPath,
 			Entries: po.MergeWithConfig(mergeCfg, def.Entries, ref.Entries),
 		}
+		if !previous {
+			// Like msgmerge, previous messages are only written on demand.
+			for i := range out.Entries {
+				out.Entries[i].Previous = po.PreviousMessage{}
+			}
+		}
+		if err = writeReport(mergeCfg.Report); err != nil {
+			return err
+		}
 
 		return compile.PoToWriter(out, outWriter, compile.PoWithConfig(compilerCfg))
 	},
 }
 
+// writeReport prints the report of the merge if it was requested.
+func writeReport(report *po.MergeReport) error {
+	if report == nil {
+		return nil
+	}
+	if verbose {
+		fmt.Fprintln(os.Stderr, report)
+	}
+	if reportPath == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(reportPath, append(data, '\n'), 0o600)
+}
+
 func Execute() {
 	err := root.Execute()
 	if err != nil {
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompendiumWithoutLanguage(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	ref := write("ref.pot", `msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+
+msgid "Open the file"
+msgstr ""
+`)
+	// Concatenated catalogs, like the ones of msgcat, have no Language.
+	comp := write("comp.po", `msgid "Open the file"
+msgstr "Abrir el archivo"
+`)
+
+	tests := []struct {
+		name string
+		def  string
+		args []string
+	}{
+		{"DefLanguage", "msgid \"\"\nmsgstr \"\"\n\"Language: es\\n\"\n", nil},
+		{"LangFlag", "msgid \"Old\"\nmsgstr \"Viejo\"\n", []string{"--lang", "es"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			def := write(test.name+".po", test.def)
+			out := filepath.Join(dir, test.name+".out.po")
+
+			root.SetArgs(append([]string{def, ref, "-C", comp, "-o", out}, test.args...))
+			if err := root.Execute(); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := os.ReadFile(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), `msgstr "Abrir el archivo"`) {
+				t.Errorf("the compendium wasn't used:\n%s", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po/compile"
+	"github.com/Tom5521/gotext-tools/v2/pkg/tm"
+	"github.com/spf13/cobra"
+)
+
+var (
+	memories   []string
+	output     string
+	lang       string
+	threshold  int
+	exact      bool
+	previous   bool
+	sourceLang string
+	exportTMX  string
+	verbose    bool
+	reportPath string
+	noWrap     bool
+	width      int
+)
+
+func init() {
+	flags := root.Flags()
+
+	flags.StringSliceVarP(&memories, "memory", "m", nil, `translation memory (PO, MO or TMX file),
+may be specified more than once`)
+	flags.StringVarP(&output, "output-file", "o", "-", `write output to specified file
+The results are written to standard output if no output file is specified
+or if it is -.`)
+	flags.StringVarP(&lang, "lang", "l", "", `language of the translations,
+the 'Language' field of the header is used if not specified;
+also the language of the PO and MO memories without that field`)
+	flags.IntVarP(&threshold, "threshold", "t", po.DefaultFuzzyThreshold,
+		`minimum similarity, from 1 to 100, of the translated messages`)
+	flags.BoolVar(&exact, "exact", false, "only use the translations of the same messages (same as --threshold=100)")
+	flags.BoolVar(&previous, "previous", false, "keep the messages the fuzzy translations come from")
+	flags.StringVar(&sourceLang, "source-lang", tm.DefaultSourceLang,
+		"language of the source messages of the PO and MO memories")
+	flags.BoolVar(&noWrap, "no-wrap", false, `do not break long message lines, longer than
+the output page width, into several lines`)
+	flags.IntVarP(&width, "width", "w", compile.DefaultWidth, "set output page width")
+	flags.StringVar(&exportTMX, "export-tmx", "", "write the translation memory to the specified TMX file")
+	flags.BoolVar(&verbose, "verbose", false, "print a summary of the translated messages")
+	flags.StringVar(&reportPath, "report", "", `write the outcome of each message and the totals
+to the specified file in JSON format`)
+}
+
+func initCfg(cmd *cobra.Command, args []string) error {
+	if len(memories) == 0 {
+		return errors.New("no translation memory specified, use --memory")
+	}
+	if exact {
+		threshold = 100
+	}
+	if threshold < 1 || threshold > 100 {
+		return fmt.Errorf("the threshold must be between 1 and 100, got %d", threshold)
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po/compile"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po/parse"
+	"github.com/Tom5521/gotext-tools/v2/pkg/tm"
+	"github.com/spf13/cobra"
+)
+
+var use = "msgopretranslate"
+
+var root = &cobra.Command{
+	Aliases: []string{"pretranslate"},
+	Use:     use,
+	Short:   "Fills the untranslated messages of a .po file from translation memories.",
+	Long: `Usage: msgopretranslate [OPTION] [INPUTFILE]
+
+Fills the untranslated messages of a Uniforum style .po file with the
+translations of the same or the most similar messages of the translation
+memories, which may be PO, MO or TMX files.
+The translations of different messages are marked as fuzzy.
+If the input file is -, standard input is read. The input file may be
+omitted if the translation memory is only exported with --export-tmx.
+
+Mandatory arguments to long options are mandatory for short options too.`,
+	Example: fmt.Sprintf(`%s -m old/es.po -m glossary.tmx es.po -o es.po
+%s --exact --lang=pt_BR -m pt_BR.mo pt_BR.po
+%s -m es.po -m fr.po --export-tmx memory.tmx`,
+		use, use, use),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && exportTMX == "" {
+			return errors.New("no input file specified")
+		}
+		return cobra.MaximumNArgs(1)(cmd, args)
+	},
+	PreRunE: initCfg,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		memory := tm.New(tm.WithSourceLang(sourceLang), tm.WithTargetLang(lang))
+		if err = memory.AddFiles(memories...); err != nil {
+			return err
+		}
+		if exportTMX != "" {
+			if err = memory.WriteTMXFile(exportTMX); err != nil {
+				return err
+			}
+		}
+		if len(args) == 0 {
+			return nil
+		}
+
+		var file *po.File
+		if args[0] == "-" {
+			file, err = parse.PoFromReader(os.Stdin, "stdin")
+		} else {
+			file, err = parse.Po(args[0])
+		}
+		if err != nil {
+			return fmt.Errorf("error parsing PO file (%s): %w", args[0], err)
+		}
+
+		report := memory.Pretranslate(file.Entries, tm.PretranslateConfig{
+			Language:  lang,
+			Threshold: threshold,
+			Previous:  previous,
+		})
+		if err = writeReport(report); err != nil {
+			return err
+		}
+
+		var out io.Writer = os.Stdout
+		if output != "-" {
+			var f *os.File
+			f, err = os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+			if err != nil {
+				return fmt.Errorf("error opening output file %s: %w", output, err)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		if noWrap {
+			width = -1
+		}
+		return compile.PoToWriter(file, out,
+			compile.PoWithWordWrap(true),
+			compile.PoWithWidth(width),
+		)
+	},
+}
+
+// writeReport prints the report of the translated messages if it was requested.
+func writeReport(report po.MergeReport) error {
+	if verbose {
+		fmt.Fprintln(os.Stderr, report)
+	}
+	if reportPath == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(reportPath, append(data, '\n'), 0o600)
+}
+
+func Execute() {
+	err := root.Execute()
+	if err != nil {
+		os.Exit(1)
+	}
+}
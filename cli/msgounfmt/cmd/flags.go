@@ -13,6 +13,7 @@ var (
 	color        string
 	forcePo      bool
 	noWrap       bool
+	width        int
 	sortOutput   bool
 	ignoreErrors bool
 )
@@ -29,6 +30,7 @@ WHEN may be 'always', 'never' or 'auto'.`)
 	flag.BoolVarP(&sortOutput, "sort-output", "s", false, `generate sorted output`)
 	flag.BoolVar(&noWrap, "no-wrap", false, `do not break long message lines, longer than
 the output page width, into several lines`)
+	flag.IntVarP(&width, "width", "w", compile.DefaultWidth, `set output page width`)
 	flag.BoolVarP(&forcePo, "force-po", "f", false, `write PO file even if empty`)
 	flag.BoolVar(
 		&ignoreErrors,
@@ -41,7 +43,11 @@ the output page width, into several lines`)
 var compilerCfg = compile.DefaultPoConfig()
 
 func initCfg(cmd *cobra.Command, args []string) error {
-	compilerCfg.WordWrap = !noWrap
+	if noWrap {
+		width = -1
+	}
+	compilerCfg.WordWrap = true
+	compilerCfg.Width = width
 	compilerCfg.ForcePo = forcePo
 
 	switch color {
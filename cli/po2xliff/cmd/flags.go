@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po/xliff"
+	"github.com/spf13/cobra"
+)
+
+var (
+	output     string
+	version    string
+	sourceLang string
+	targetLang string
+	original   string
+)
+
+func init() {
+	flags := root.Flags()
+
+	flags.StringVarP(&output, "output-file", "o", "-", `write output to specified file
+The results are written to standard output if no output file is specified
+or if it is -.`)
+	flags.StringVar(&version, "xliff-version", string(xliff.Version12), `version of the XLIFF document, '1.2' or '2.0'`)
+	flags.StringVar(&sourceLang, "source-lang", xliff.DefaultSourceLang, "language of the msgids")
+	flags.StringVar(&targetLang, "target-lang", "", `language of the translations,
+the 'Language' field of the header is used if not specified`)
+	flags.StringVar(&original, "original", "", `name of the translated file written in the document,
+the name of the input file is used if not specified`)
+}
+
+func initCfg(cmd *cobra.Command, args []string) error {
+	switch xliff.Version(version) {
+	case xliff.Version12, xliff.Version20:
+		return nil
+	}
+	return fmt.Errorf("invalid XLIFF version %q", version)
+}
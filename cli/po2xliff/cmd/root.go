@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po/parse"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po/xliff"
+	"github.com/spf13/cobra"
+)
+
+var use = "po2xliff"
+
+var root = &cobra.Command{
+	Use:   use,
+	Short: "Converts a Uniforum style .po file to a XLIFF document.",
+	Long: `Usage: po2xliff [OPTION] INPUTFILE
+
+Converts a Uniforum style .po file to a XLIFF 1.2 or 2.0 document.
+Fuzzy translations need a review in the document, comments are notes and
+file positions are context groups. The msgctxt, flags and previous msgids
+are kept too, so that xliff2po converts the document back without loss.
+If the input file is -, standard input is read.
+
+Mandatory arguments to long options are mandatory for short options too.`,
+	Example: fmt.Sprintf(`%s es.po -o es.xlf
+%s --xliff-version=2.0 es.po -o es.xlf
+%s --source-lang=en-US --target-lang=es-ES messages.pot -o es.xlf`,
+		use, use, use),
+	Args:    cobra.ExactArgs(1),
+	PreRunE: initCfg,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var file *po.File
+		if args[0] == "-" {
+			file, err = parse.PoFromReader(os.Stdin, "stdin")
+		} else {
+			file, err = parse.Po(args[0])
+		}
+		if err != nil {
+			return fmt.Errorf("error parsing PO file (%s): %w", args[0], err)
+		}
+
+		var out io.Writer = os.Stdout
+		if output != "-" {
+			var f *os.File
+			f, err = os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+			if err != nil {
+				return fmt.Errorf("error opening output file %s: %w", output, err)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		return xliff.ToWriter(file, out,
+			xliff.WithVersion(xliff.Version(version)),
+			xliff.WithSourceLang(sourceLang),
+			xliff.WithTargetLang(targetLang),
+			xliff.WithOriginal(original),
+		)
+	},
+}
+
+func Execute() {
+	err := root.Execute()
+	if err != nil {
+		os.Exit(1)
+	}
+}
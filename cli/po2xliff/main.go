@@ -0,0 +1,7 @@
+package main
+
+import "github.com/Tom5521/gotext-tools/v2/cli/po2xliff/cmd"
+
+func main() {
+	cmd.Execute()
+}
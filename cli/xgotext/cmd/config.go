@@ -7,6 +7,7 @@ import (
 	"github.com/Tom5521/gotext-tools/v2/pkg/po"
 	"github.com/Tom5521/gotext-tools/v2/pkg/po/compile"
 	poparse "github.com/Tom5521/gotext-tools/v2/pkg/po/parse"
+	"github.com/spf13/cobra"
 	"golang.org/x/term"
 )
 
@@ -17,19 +18,22 @@ var (
 	HeadersCfg  = po.DefaultTemplateHeaderConfig()
 )
 
-func initConfig() {
-	HeadersCfg.Nplurals = nplurals
+func initConfig(cmd *cobra.Command) {
 	HeadersCfg.ProjectIDVersion = packageVersion
 	HeadersCfg.ReportMsgidBugsTo = msgidBugsAddress
 	HeadersCfg.Language = lang
+	if !HeadersCfg.SetPluralFormsFromLanguage() || cmd.Flags().Changed("nplurals") {
+		HeadersCfg.Nplurals = nplurals
+	}
 
 	GoParserCfg = goparse.Config{
-		CleanDuplicates: true,
-		Exclude:         exclude,
-		ExtractAll:      extractAll,
-		HeaderConfig:    &HeadersCfg,
-		Logger:          logger,
-		Verbose:         verbose,
+		CleanDuplicates:   true,
+		StampCreationDate: true,
+		Exclude:           exclude,
+		ExtractAll:        extractAll,
+		HeaderConfig:      &HeadersCfg,
+		Logger:            logger,
+		Verbose:           verbose,
 	}
 	CompilerCfg = compile.PoConfig{
 		Logger:          logger,
@@ -47,6 +51,7 @@ func initConfig() {
 		HeaderComments:  true,
 		HeaderFields:    true,
 		WordWrap:        wordWrap,
+		Width:           width,
 	}
 
 	switch color {
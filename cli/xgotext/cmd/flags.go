@@ -1,5 +1,7 @@
 package cmd
 
+import "github.com/Tom5521/gotext-tools/v2/pkg/po/compile"
+
 var (
 	// CLI.
 
@@ -36,6 +38,7 @@ var (
 	msgstrPrefix    string
 	msgstrSuffix    string
 	wordWrap        bool
+	width           int
 
 	// Other.
 	defaultDomain string
@@ -109,7 +112,8 @@ be in the public domain.`,
 		&nplurals,
 		"nplurals",
 		2,
-		"Specify the number of plurals forms of the language in question",
+		`Specify the number of plurals forms of the language in question.
+By default it's taken from the built-in locale database using --lang.`,
 	)
 	flag.StringVarP(&filesFrom, "files-from", "f", "", "get list of input files from FILE")
 	flag.StringVarP(
@@ -201,6 +205,7 @@ This option has an effect only if the ‘--package-name’ option is also used.`
 		`Use string (or "" if not specified) as suffix for msgstr values.`,
 	)
 	flag.BoolVar(&wordWrap, "word-wrap", false, "Applies word wrapping to strings.")
+	flag.IntVarP(&width, "width", "w", compile.DefaultWidth, "Set output page width.")
 	flag.StringVar(&color, "color", "auto", `use colors and other text attributes if WHEN. 
 WHEN may be 'always', 'never', 'auto'`)
 }
@@ -27,7 +27,7 @@ Similarly for optional arguments.`,
 		use, use, use,
 	),
 	PreRun: func(cmd *cobra.Command, args []string) {
-		initConfig()
+		initConfig(cmd)
 	},
 	RunE: func(cmd *cobra.Command, inputfiles []string) (err error) {
 		parser, err := processInput(inputfiles)
@@ -0,0 +1,22 @@
+package cmd
+
+import "github.com/Tom5521/gotext-tools/v2/pkg/po/compile"
+
+var (
+	output  string
+	noWrap  bool
+	width   int
+	forcePo bool
+)
+
+func init() {
+	flags := root.Flags()
+
+	flags.StringVarP(&output, "output-file", "o", "-", `write output to specified file
+The results are written to standard output if no output file is specified
+or if it is -.`)
+	flags.BoolVar(&noWrap, "no-wrap", false, `do not break long message lines, longer than
+the output page width, into several lines`)
+	flags.IntVarP(&width, "width", "w", compile.DefaultWidth, "set output page width")
+	flags.BoolVar(&forcePo, "force-po", false, "write PO file even if empty")
+}
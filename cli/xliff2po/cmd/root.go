@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po/compile"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po/xliff"
+	"github.com/spf13/cobra"
+)
+
+var use = "xliff2po"
+
+var root = &cobra.Command{
+	Use:   use,
+	Short: "Converts a XLIFF document to a Uniforum style .po file.",
+	Long: `Usage: xliff2po [OPTION] INPUTFILE
+
+Converts a XLIFF 1.2 or 2.0 document to a Uniforum style .po file.
+Translations that need a review are marked as fuzzy, and the notes of the
+developer are extracted comments while any other note is a translator
+comment. The documents written by po2xliff are converted back without loss.
+If the input file is -, standard input is read.
+
+Mandatory arguments to long options are mandatory for short options too.`,
+	Example: fmt.Sprintf(`%s es.xlf -o es.po
+%s --no-wrap es.xlf -o es.po`,
+		use, use),
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var file *po.File
+		if args[0] == "-" {
+			file, err = xliff.ParseReader(os.Stdin, "stdin")
+		} else {
+			file, err = xliff.Parse(args[0])
+		}
+		if err != nil {
+			return err
+		}
+
+		var out io.Writer = os.Stdout
+		if output != "-" {
+			var f *os.File
+			f, err = os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+			if err != nil {
+				return fmt.Errorf("error opening output file %s: %w", output, err)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		if noWrap {
+			width = -1
+		}
+		return compile.PoToWriter(file, out,
+			compile.PoWithWordWrap(true),
+			compile.PoWithWidth(width),
+			compile.PoWithForcePo(forcePo),
+		)
+	},
+}
+
+func Execute() {
+	err := root.Execute()
+	if err != nil {
+		os.Exit(1)
+	}
+}
@@ -10,6 +10,7 @@ require (
 	github.com/sanity-io/litter v1.5.8
 	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e
 	golang.org/x/sys v0.30.0
+	golang.org/x/text v0.22.0
 )
 
 require github.com/stretchr/testify v1.8.4 // indirect
@@ -17,3 +17,9 @@ func SortFunc[S ~[]E, E any](x S, cmp func(a, b E) int) {
 		return cmp(x[i], x[j]) < 0
 	})
 }
+
+func SortStableFunc[S ~[]E, E any](x S, cmp func(a, b E) int) {
+	sort.SliceStable(x, func(i, j int) bool {
+		return cmp(x[i], x[j]) < 0
+	})
+}
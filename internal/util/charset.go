@@ -1,5 +1,18 @@
 package util
 
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
 var SupportedCharsets = map[string]bool{
 	"ASCII":       true,
 	"ISO-8859-1":  true,
@@ -46,3 +59,157 @@ var SupportedCharsets = map[string]bool{
 	"GEORGIAN-PS": true,
 	"UTF-8":       true,
 }
+
+var charsetAliases = map[string]string{
+	"US-ASCII":       "ASCII",
+	"ANSI_X3.4-1968": "ASCII",
+	"UTF8":           "UTF-8",
+	"LATIN1":         "ISO-8859-1",
+	"SJIS":           "SHIFT_JIS",
+	"SHIFT-JIS":      "SHIFT_JIS",
+	"EUCJP":          "EUC-JP",
+	"EUCKR":          "EUC-KR",
+	"BIG-5":          "BIG5",
+	"TIS620":         "TIS-620",
+}
+
+// NormalizeCharset returns the canonical name used in [SupportedCharsets]
+// for charset, resolving case differences and common aliases
+// like "utf8", "windows-1251" or "iso8859-1".
+func NormalizeCharset(charset string) string {
+	name := strings.ToUpper(strings.TrimSpace(charset))
+	if alias, ok := charsetAliases[name]; ok {
+		return alias
+	}
+	if n := strings.TrimPrefix(name, "WINDOWS-"); n != name {
+		return "CP" + n
+	}
+	if n := strings.TrimPrefix(name, "ISO8859-"); n != name {
+		return "ISO-8859-" + n
+	}
+	if n := strings.TrimPrefix(name, "ISO_8859-"); n != name {
+		return "ISO-8859-" + n
+	}
+
+	return name
+}
+
+var charsetEncodings = map[string]encoding.Encoding{
+	"ISO-8859-1":  charmap.ISO8859_1,
+	"ISO-8859-2":  charmap.ISO8859_2,
+	"ISO-8859-3":  charmap.ISO8859_3,
+	"ISO-8859-4":  charmap.ISO8859_4,
+	"ISO-8859-5":  charmap.ISO8859_5,
+	"ISO-8859-6":  charmap.ISO8859_6,
+	"ISO-8859-7":  charmap.ISO8859_7,
+	"ISO-8859-8":  charmap.ISO8859_8,
+	"ISO-8859-9":  charmap.ISO8859_9,
+	"ISO-8859-13": charmap.ISO8859_13,
+	"ISO-8859-14": charmap.ISO8859_14,
+	"ISO-8859-15": charmap.ISO8859_15,
+	"KOI8-R":      charmap.KOI8R,
+	"KOI8-U":      charmap.KOI8U,
+	"CP850":       charmap.CodePage850,
+	"CP866":       charmap.CodePage866,
+	"CP874":       charmap.Windows874,
+	"TIS-620":     charmap.Windows874,
+	"CP932":       japanese.ShiftJIS,
+	"SHIFT_JIS":   japanese.ShiftJIS,
+	"EUC-JP":      japanese.EUCJP,
+	"CP949":       korean.EUCKR,
+	"EUC-KR":      korean.EUCKR,
+	"CP950":       traditionalchinese.Big5,
+	"BIG5":        traditionalchinese.Big5,
+	"BIG5-HKSCS":  traditionalchinese.Big5,
+	"CP1250":      charmap.Windows1250,
+	"CP1251":      charmap.Windows1251,
+	"CP1252":      charmap.Windows1252,
+	"CP1253":      charmap.Windows1253,
+	"CP1254":      charmap.Windows1254,
+	"CP1255":      charmap.Windows1255,
+	"CP1256":      charmap.Windows1256,
+	"CP1257":      charmap.Windows1257,
+	"GB2312":      simplifiedchinese.GBK,
+	"GBK":         simplifiedchinese.GBK,
+	"GB18030":     simplifiedchinese.GB18030,
+	"ASCII":       encoding.Nop,
+	"UTF-8":       encoding.Nop,
+}
+
+// CharsetEncoding returns the encoding used to convert text from and to charset.
+// It reports false if the charset is unknown or can't be converted.
+func CharsetEncoding(charset string) (encoding.Encoding, bool) {
+	enc, ok := charsetEncodings[NormalizeCharset(charset)]
+	return enc, ok
+}
+
+// IsUTF8Charset reports whether text in charset is already valid UTF-8.
+func IsUTF8Charset(charset string) bool {
+	switch NormalizeCharset(charset) {
+	case "UTF-8", "ASCII":
+		return true
+	}
+	return false
+}
+
+// DecodeCharset converts data from charset to UTF-8.
+func DecodeCharset(data []byte, charset string) ([]byte, error) {
+	enc, ok := CharsetEncoding(charset)
+	if !ok {
+		return nil, fmt.Errorf("unsupported charset %q", charset)
+	}
+	if IsUTF8Charset(charset) {
+		return data, nil
+	}
+
+	return enc.NewDecoder().Bytes(data)
+}
+
+// EncodeCharset converts the UTF-8 string s to charset.
+func EncodeCharset(s, charset string) (string, error) {
+	enc, ok := CharsetEncoding(charset)
+	if !ok {
+		return "", fmt.Errorf("unsupported charset %q", charset)
+	}
+	if r, _, found := FirstUnencodableRune(s, charset); found {
+		return "", fmt.Errorf("character %q can't be represented in %s", r, NormalizeCharset(charset))
+	}
+	if IsUTF8Charset(charset) {
+		return s, nil
+	}
+
+	return enc.NewEncoder().String(s)
+}
+
+// FirstUnencodableRune returns the first rune of s that can't be represented
+// in charset and its byte offset. The charset must be supported by [CharsetEncoding].
+func FirstUnencodableRune(s, charset string) (rune, int, bool) {
+	charset = NormalizeCharset(charset)
+	switch charset {
+	case "UTF-8":
+		return 0, 0, false
+	case "ASCII":
+		for i, r := range s {
+			if r >= utf8.RuneSelf {
+				return r, i, true
+			}
+		}
+		return 0, 0, false
+	}
+
+	enc, ok := CharsetEncoding(charset)
+	if !ok {
+		return 0, 0, false
+	}
+	encoder := enc.NewEncoder()
+	if _, err := encoder.String(s); err == nil {
+		return 0, 0, false
+	}
+	for i, r := range s {
+		if _, err := encoder.String(string(r)); err != nil {
+			return r, i, true
+		}
+	}
+
+	return 0, 0, false
+}
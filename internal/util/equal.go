@@ -11,7 +11,14 @@ func FuzzyEqual(x, y string) bool {
 	return fuzzy.Ratio(x, y) >= 80
 }
 
-type visitedPairs map[[2]uintptr]struct{}
+// visitedKey identifies a comparison already in progress. The type is needed
+// because a struct and its first field share the same address.
+type visitedKey struct {
+	addr1, addr2 uintptr
+	typ          reflect.Type
+}
+
+type visitedPairs map[visitedKey]struct{}
 
 func Equal[X, Y any](x X, y Y) bool {
 	return equal(reflect.ValueOf(x), reflect.ValueOf(y), make(visitedPairs))
@@ -38,7 +45,7 @@ func equal(v1, v2 reflect.Value, visited visitedPairs) bool {
 	}
 	if v1.CanAddr() && v2.CanAddr() {
 		addr1, addr2 := v1.UnsafeAddr(), v2.UnsafeAddr()
-		pair := [2]uintptr{addr1, addr2}
+		pair := visitedKey{addr1, addr2, v1.Type()}
 		if _, found := visited[pair]; found {
 			return true
 		}
@@ -19,3 +19,16 @@ func TestEqual(t *testing.T) {
 	// Stack overflow.
 	util.Equal(a, b)
 }
+
+func TestEqualFirstField(t *testing.T) {
+	type pair struct {
+		Line int
+		File string
+	}
+
+	x := []pair{{1, "a.go"}}
+	y := []pair{{12, "a.go"}}
+	if util.Equal(x, y) {
+		t.Error("slices with different first fields must not be equal")
+	}
+}
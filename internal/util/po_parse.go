@@ -1,27 +1,31 @@
 package util
 
 import (
+	"regexp"
+	"sync"
+
 	"github.com/alecthomas/participle/v2"
 	"github.com/alecthomas/participle/v2/lexer"
 )
 
 type (
-	poFile struct {
+	PoFile struct {
 		Tokens  []lexer.Token
-		Entries []entry `@@*`
+		Entries []PoEntry `@@*`
 	}
 
-	entry struct {
+	PoEntry struct {
+		Pos    lexer.Position
 		Tokens []lexer.Token
 
 		Context     []string        `(Msgctxt @String+)?`
 		ID          []string        `Msgid @String+`
 		Str         []string        `(Msgstr @String+`
 		MsgidPlural []string        `| (MsgidPlural @String+`
-		Plurals     []pluralEntries `@@*))`
+		Plurals     []PoPluralEntry `@@*))`
 	}
 
-	pluralEntries struct {
+	PoPluralEntry struct {
 		ID  int      `Msgstr LB @Integer RB`
 		Str []string `@String+`
 	}
@@ -36,9 +40,30 @@ func SearchSymbol(t lexer.TokenType) string {
 	return ""
 }
 
+// DefaultObsoletePrefix is the character that follows "#" in the lines of obsolete entries.
+const DefaultObsoletePrefix = '~'
+
 var (
 	PoSymbols = PoLexer.Symbols()
-	PoRules   = []lexer.SimpleRule{
+	PoRules   = NewPoRules(DefaultObsoletePrefix)
+	PoLexer   = lexer.MustSimple(PoRules)
+	PoParser  = NewPoParser(DefaultObsoletePrefix)
+
+	poParsers   = map[rune]*participle.Parser[PoFile]{}
+	poParsersMu sync.Mutex
+)
+
+// NewPoRules returns the lexer rules of PO files whose obsolete entries
+// are marked with "#" followed by obsoletePrefix.
+//
+// The prefix of obsolete lines is lexed as an Obsolete token, so the
+// keywords and strings after it are parsed like the ones of any entry,
+// and the previous message of obsolete entries ("#~|") as an
+// ObsoletePrevious token. The rules keep the same names and order for
+// any prefix, so [PoSymbols] can be used with all of them.
+func NewPoRules(obsoletePrefix rune) []lexer.SimpleRule {
+	prefix := "#" + regexp.QuoteMeta(string(obsoletePrefix))
+	return []lexer.SimpleRule{
 		{Name: "WS", Pattern: `\s+`},
 		{Name: "Integer", Pattern: `\d+`},
 		{Name: "LB", Pattern: `\[`},
@@ -48,15 +73,34 @@ var (
 		{Name: "MsgidPlural", Pattern: "msgid_plural"},
 		{Name: "Msgid", Pattern: "msgid"},
 		{Name: "Msgstr", Pattern: "msgstr"},
+		{Name: "ObsoletePrevious", Pattern: prefix + `\|[^\n]*`},
+		{Name: "Obsolete", Pattern: prefix},
 		{Name: "Comment", Pattern: "#[^\n]*"},
 	}
-	PoLexer  = lexer.MustSimple(PoRules)
-	PoParser = participle.MustBuild[poFile](
-		participle.Lexer(PoLexer),
+}
+
+// NewPoParser returns the parser of PO files whose obsolete entries are
+// marked with "#" followed by obsoletePrefix. The parsers are built once
+// for each prefix.
+func NewPoParser(obsoletePrefix rune) *participle.Parser[PoFile] {
+	poParsersMu.Lock()
+	defer poParsersMu.Unlock()
+
+	if p, ok := poParsers[obsoletePrefix]; ok {
+		return p
+	}
+
+	p := participle.MustBuild[PoFile](
+		participle.Lexer(lexer.MustSimple(NewPoRules(obsoletePrefix))),
 		participle.Unquote("String"),
 		participle.Elide(
 			"WS",
 			"Comment",
+			"Obsolete",
+			"ObsoletePrevious",
 		),
 	)
-)
+	poParsers[obsoletePrefix] = p
+
+	return p
+}
@@ -0,0 +1,25 @@
+package util
+
+import "unicode/utf8"
+
+// InvalidXMLChar returns the first character of str that XML 1.0 can't
+// represent, like most control characters, and utf8.RuneError for invalid
+// UTF-8. encoding/xml writes them as replacement characters.
+func InvalidXMLChar(str string) (rune, bool) {
+	for i := 0; i < len(str); {
+		r, size := utf8.DecodeRuneInString(str[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			return r, true
+		case r == '\t' || r == '\n' || r == '\r',
+			r >= 0x20 && r <= 0xD7FF,
+			r >= 0xE000 && r <= 0xFFFD,
+			r >= 0x10000:
+		default:
+			return r, true
+		}
+		i += size
+	}
+
+	return 0, false
+}
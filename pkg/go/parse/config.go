@@ -12,14 +12,17 @@ type Config struct {
 	// and is saved when using the asd method [Config.ApplyOptions]
 	lastCfg any
 
-	Exclude         []string
-	ExtractAll      bool
-	NoHeader        bool
-	HeaderConfig    *po.HeaderConfig
-	CustomHeader    *po.Header
-	Logger          *log.Logger
-	Verbose         bool
-	CleanDuplicates bool
+	Exclude      []string
+	ExtractAll   bool
+	NoHeader     bool
+	HeaderConfig *po.HeaderConfig
+	CustomHeader *po.Header
+	// StampCreationDate sets the POT-Creation-Date of the header to the
+	// time of the extraction, honoring SOURCE_DATE_EPOCH (see [po.HeaderNow]).
+	StampCreationDate bool
+	Logger            *log.Logger
+	Verbose           bool
+	CleanDuplicates   bool
 }
 
 // Restores the configuration state prior to the last
@@ -93,3 +96,7 @@ func WithCustomHeader(h *po.Header) Option {
 func WithHeaderConfig(h *po.HeaderConfig) Option {
 	return func(c *Config) { c.HeaderConfig = h }
 }
+
+func WithStampCreationDate(s bool) Option {
+	return func(c *Config) { c.StampCreationDate = s }
+}
@@ -10,6 +10,7 @@ import (
 
 	krfs "github.com/kr/fs"
 
+	"github.com/Tom5521/gotext-tools/v2/internal/slices"
 	"github.com/Tom5521/gotext-tools/v2/internal/util"
 	"github.com/Tom5521/gotext-tools/v2/pkg/po"
 )
@@ -192,8 +193,15 @@ func (p *Parser) Parse() (file *po.File) {
 
 		if p.Config.CustomHeader != nil {
 			header = *p.Config.CustomHeader
+			if p.Config.StampCreationDate {
+				header.Fields = slices.Clone(header.Fields)
+				header.SetPOTCreationTime(po.HeaderNow())
+			}
 		} else {
 			headerConfig.XGenerator = "xgotext"
+			if p.Config.StampCreationDate {
+				headerConfig.SetPOTCreationTime(po.HeaderNow())
+			}
 			header = headerConfig.ToHeader()
 		}
 
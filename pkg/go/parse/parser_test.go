@@ -193,3 +193,28 @@ func main(){
 		return
 	}
 }
+
+func TestStampCreationDate(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	const input = `package main
+import "github.com/leonelquinteros/gotext"
+
+func main(){
+	gotext.Get("Hello World!")
+}`
+
+	parser, err := parse.NewParserFromString(input, "test.go", parse.WithStampCreationDate(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := parser.Parse()
+	if err = parser.Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	header := file.Header()
+	if got := header.Load("POT-Creation-Date"); got != "2023-11-14 22:13+0000" {
+		t.Errorf("unexpected creation date %q", got)
+	}
+}
@@ -0,0 +1,101 @@
+package po
+
+import (
+	"fmt"
+	"mime"
+
+	"github.com/Tom5521/gotext-tools/v2/internal/util"
+)
+
+// Charset returns the charset declared in the Content-Type field of the header,
+// normalized to the names used by gettext ("UTF-8", "ISO-8859-1", "CP1251").
+// It returns an empty string if there is no charset or if it is the
+// "CHARSET" placeholder of templates.
+func (h Header) Charset() string {
+	_, params, err := mime.ParseMediaType(h.Load("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	charset := util.NormalizeCharset(params["charset"])
+	if charset == "CHARSET" {
+		return ""
+	}
+
+	return charset
+}
+
+// SetCharset replaces the charset of the Content-Type field of the header.
+func (h *Header) SetCharset(charset string) {
+	mediatype, params, err := mime.ParseMediaType(h.Load("Content-Type"))
+	if err != nil {
+		mediatype = "text/plain"
+		params = make(map[string]string)
+	}
+	params["charset"] = charset
+	h.Set("Content-Type", mime.FormatMediaType(mediatype, params))
+}
+
+// IsCharsetSupported reports whether texts can be converted from and to charset.
+func IsCharsetSupported(charset string) bool {
+	_, ok := util.CharsetEncoding(charset)
+	return ok
+}
+
+// SetCharset replaces the charset declared in the header of the entries,
+// keeping the rest of the header untouched. The header is added if it doesn't exist.
+func (e *Entries) SetCharset(charset string) {
+	header := e.Header()
+	header.SetCharset(charset)
+	e.SetHeaderField("Content-Type", header.Load("Content-Type"))
+}
+
+// CheckCharset reports the entries with characters that can't be represented
+// in charset, with an [UnrepresentableCharError] for each string of the entry.
+// If the charset isn't supported, a single [UnsupportedCharsetError] is returned.
+func (e Entries) CheckCharset(charset string) []error {
+	if !IsCharsetSupported(charset) {
+		return []error{&UnsupportedCharsetError{Charset: charset}}
+	}
+	charset = util.NormalizeCharset(charset)
+
+	var errs []error
+	for index, entry := range e {
+		for _, err := range entry.checkCharset(charset) {
+			errs = append(errs,
+				&InvalidEntryAtIndexError{
+					Index: index,
+					Reason: &InvalidEntryError{
+						ID:     entry.UnifiedID(),
+						Reason: err,
+					},
+				},
+			)
+		}
+	}
+
+	return errs
+}
+
+func (e Entry) checkCharset(charset string) []error {
+	var errs []error
+	check := func(field, s string) {
+		if r, offset, found := util.FirstUnencodableRune(s, charset); found {
+			errs = append(errs, &UnrepresentableCharError{
+				Charset: charset,
+				Field:   field,
+				Char:    r,
+				Offset:  offset,
+			})
+		}
+	}
+
+	check("msgctxt", e.Context)
+	check("msgid", e.ID)
+	check("msgid_plural", e.Plural)
+	check("msgstr", e.Str)
+	for _, pe := range e.Plurals {
+		check(fmt.Sprintf("msgstr[%d]", pe.ID), pe.Str)
+	}
+
+	return errs
+}
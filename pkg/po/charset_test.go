@@ -0,0 +1,89 @@
+package po_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+)
+
+func TestHeaderCharset(t *testing.T) {
+	tests := []struct {
+		contentType string
+		expected    string
+	}{
+		{"text/plain; charset=UTF-8", "UTF-8"},
+		{"text/plain; charset=utf8", "UTF-8"},
+		{"text/plain; charset=windows-1251", "CP1251"},
+		{"text/plain; charset=iso8859-1", "ISO-8859-1"},
+		{"text/plain; charset=CHARSET", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		var h po.Header
+		h.Set("Content-Type", test.contentType)
+		if got := h.Charset(); got != test.expected {
+			t.Errorf("%q: expected %q, got %q", test.contentType, test.expected, got)
+		}
+	}
+}
+
+func TestEntriesSetCharset(t *testing.T) {
+	entries := po.Entries{
+		{Str: "Project-Id-Version: test\nContent-Type: text/plain; charset=UTF-8\nLanguage: ru\n"},
+		{ID: "File", Str: "Файл"},
+	}
+
+	entries.SetCharset("CP1251")
+	expected := "Project-Id-Version: test\nContent-Type: text/plain; charset=CP1251\nLanguage: ru\n"
+	if entries[0].Str != expected {
+		t.Errorf("expected %q, got %q", expected, entries[0].Str)
+	}
+
+	entries = po.Entries{{ID: "File", Str: "Файл"}}
+	entries.SetCharset("KOI8-R")
+	if len(entries) != 2 || entries.Header().Charset() != "KOI8-R" {
+		t.Errorf("expected a header with the KOI8-R charset, got %#v", entries)
+	}
+}
+
+func TestCheckCharset(t *testing.T) {
+	entries := po.Entries{
+		{ID: "File", Str: "Файл"},
+		{ID: "Café", Str: "Кафе"},
+		{
+			ID:     "%d item",
+			Plural: "%d items",
+			Plurals: po.PluralEntries{
+				{ID: 0, Str: "%d élément"},
+				{ID: 1, Str: "%d éléments"},
+			},
+		},
+	}
+
+	if errs := entries.CheckCharset("UTF-8"); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if errs := entries.CheckCharset("CP1251"); len(errs) != 3 {
+		t.Errorf("expected 3 errors, got %v", errs)
+	}
+
+	errs := entries.CheckCharset("ISO-8859-1")
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	var cerr *po.UnrepresentableCharError
+	if !errors.As(errs[1], &cerr) {
+		t.Fatalf("expected an UnrepresentableCharError, got %v", errs[1])
+	}
+	if cerr.Field != "msgstr" || cerr.Char != 'К' || cerr.Offset != 0 {
+		t.Errorf("unexpected error: %#v", cerr)
+	}
+
+	errs = entries.CheckCharset("X-UNKNOWN")
+	var uerr *po.UnsupportedCharsetError
+	if len(errs) != 1 || !errors.As(errs[0], &uerr) {
+		t.Errorf("expected an UnsupportedCharsetError, got %v", errs)
+	}
+}
@@ -0,0 +1,320 @@
+package po
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/Tom5521/gotext-tools/v2/internal/slices"
+	"github.com/Tom5521/gotext-tools/v2/internal/util"
+)
+
+// Check selects the checks run by [Entries.Check], like the -c
+// option of GNU msgfmt. The checks can be combined with "|".
+type Check uint
+
+const (
+	// CheckPlurals checks that the translations of plural entries match the
+	// nplurals of the header, and that their msgid_plural isn't empty.
+	CheckPlurals Check = 1 << iota
+	// CheckNewlines checks that the translations begin and end with a
+	// newline only if their source string does.
+	CheckNewlines
+	// CheckIDChars checks that the msgctxt, msgid and msgid_plural don't contain
+	// the '\x00' and '\x04' characters, which are separators in MO files.
+	CheckIDChars
+	// CheckHeader checks that the header exists and that its required fields
+	// are present, have been filled in and are valid.
+	CheckHeader
+
+	CheckAll = CheckPlurals | CheckNewlines | CheckIDChars | CheckHeader
+)
+
+// headerCheckedFields are the fields checked by [CheckHeader], with the
+// initial value they have in templates. Like in GNU msgfmt, a missing
+// Language field isn't reported.
+var headerCheckedFields = []struct {
+	key, initial string
+	optional     bool
+}{
+	{"Project-Id-Version", "PACKAGE VERSION", false},
+	{"PO-Revision-Date", "YEAR-MO-DA HO:MI+ZONE", false},
+	{"Last-Translator", "FULL NAME <EMAIL@ADDRESS>", false},
+	{"Language-Team", "LANGUAGE <LL@li.org>", false},
+	{"MIME-Version", "", false},
+	{"Content-Type", "text/plain; charset=CHARSET", false},
+	{"Content-Transfer-Encoding", "ENCODING", false},
+	{"Language", "", true},
+}
+
+// Check runs the selected checks on the entry. The plural forms are
+// checked against nplurals, see [Header.Nplurals].
+// The header entry is only checked with [Entries.Check].
+func (e Entry) Check(checks Check, nplurals int) []error {
+	var errs []error
+	if checks&CheckIDChars != 0 {
+		errs = append(errs, e.checkIDChars()...)
+	}
+	if checks&CheckPlurals != 0 {
+		errs = append(errs, e.checkPlurals(nplurals)...)
+	}
+	if checks&CheckNewlines != 0 {
+		errs = append(errs, e.checkNewlines()...)
+	}
+
+	return errs
+}
+
+func (e Entry) checkIDChars() []error {
+	var errs []error
+	check := func(field, s string) {
+		if i := strings.IndexAny(s, "\x00\x04"); i != -1 {
+			errs = append(errs, &InvalidIDCharError{Field: field, Char: rune(s[i]), Offset: i})
+		}
+	}
+
+	check("msgctxt", e.Context)
+	check("msgid", e.ID)
+	check("msgid_plural", e.Plural)
+
+	return errs
+}
+
+func (e Entry) checkPlurals(nplurals int) []error {
+	if len(e.Plurals) == 0 {
+		return nil
+	}
+
+	var errs []error
+	if e.Plural == "" {
+		errs = append(errs, ErrEmptyPluralID)
+	}
+	// Untranslated entries get their plural forms when they're translated.
+	if !slices.ContainsFunc(e.Plurals, func(pe PluralEntry) bool { return pe.Str != "" }) {
+		return errs
+	}
+	if len(e.Plurals) != nplurals {
+		errs = append(errs, &PluralCountError{Expected: nplurals, Got: len(e.Plurals)})
+	}
+
+	present := make(map[int]bool, len(e.Plurals))
+	for _, pe := range e.Plurals {
+		present[pe.ID] = true
+		if pe.ID < 0 || pe.ID >= nplurals {
+			errs = append(errs, &PluralIndexError{Index: pe.ID, Nplurals: nplurals})
+		}
+	}
+	for i := 0; i < nplurals; i++ {
+		if !present[i] {
+			errs = append(errs, &PluralIndexError{Index: i, Nplurals: nplurals, Missing: true})
+		}
+	}
+
+	return errs
+}
+
+func (e Entry) checkNewlines() []error {
+	var errs []error
+	check := func(source, field, a, b string) {
+		if b == "" {
+			return
+		}
+		if strings.HasPrefix(a, "\n") != strings.HasPrefix(b, "\n") {
+			errs = append(errs, &NewlineError{Source: source, Field: field})
+		}
+		if strings.HasSuffix(a, "\n") != strings.HasSuffix(b, "\n") {
+			errs = append(errs, &NewlineError{Source: source, Field: field, Trailing: true})
+		}
+	}
+
+	if !e.IsPlural() {
+		check("msgid", "msgstr", e.ID, e.Str)
+		return errs
+	}
+
+	check("msgid", "msgid_plural", e.ID, e.Plural)
+	for _, pe := range e.Plurals {
+		check("msgid", fmt.Sprintf("msgstr[%d]", pe.ID), e.ID, pe.Str)
+	}
+
+	return errs
+}
+
+// checkHeader checks the required fields of the header.
+func (h Header) checkHeader() []error {
+	var errs []error
+	for _, field := range headerCheckedFields {
+		i := slices.IndexFunc(h.Fields, func(f HeaderField) bool {
+			return f.Key == field.key
+		})
+		if i == -1 {
+			if !field.optional {
+				errs = append(errs, &HeaderFieldError{Field: field.key, Reason: HeaderFieldMissing})
+			}
+			continue
+		}
+		if strings.TrimSpace(h.Fields[i].Value) == field.initial {
+			errs = append(errs, &HeaderFieldError{Field: field.key, Reason: HeaderFieldDefault})
+		}
+	}
+
+	invalid := func(key string, err error) {
+		errs = append(errs, &HeaderFieldError{Field: key, Reason: HeaderFieldInvalid, Err: err})
+	}
+	if date := h.Load("PO-Revision-Date"); date != "" && date != "YEAR-MO-DA HO:MI+ZONE" {
+		if _, err := ParseHeaderDate(date); err != nil {
+			invalid("PO-Revision-Date", err)
+		}
+	}
+	if charset := h.Charset(); charset != "" && !util.SupportedCharsets[charset] {
+		invalid("Content-Type", &UnsupportedCharsetError{Charset: charset})
+	}
+	if encoding := h.Load("Content-Transfer-Encoding"); encoding != "" && encoding != "ENCODING" &&
+		!strings.EqualFold(encoding, "8bit") {
+		invalid("Content-Transfer-Encoding", fmt.Errorf("%q must be 8bit", encoding))
+	}
+	if h.Load("Plural-Forms") != "" {
+		if _, err := h.PluralFunc(); err != nil {
+			invalid("Plural-Forms", err)
+		}
+	}
+
+	return errs
+}
+
+// Check runs the selected checks on all the non fuzzy and non obsolete
+// entries, and on the header if [CheckHeader] is selected.
+// See [Entry.Check].
+//
+// The errors of the entries are reported as [InvalidEntryAtIndexError].
+func (e Entries) Check(checks Check) []error {
+	var errs []error
+
+	headerIndex := e.Index("", "")
+	header := e.Header()
+	if checks&CheckHeader != 0 {
+		if headerIndex == -1 {
+			errs = append(errs, ErrMissingHeader)
+		} else {
+			for _, err := range header.checkHeader() {
+				errs = append(errs, &InvalidEntryAtIndexError{Index: headerIndex, Reason: err})
+			}
+		}
+	}
+
+	nplurals := int(header.Nplurals())
+	for index, entry := range e {
+		if index == headerIndex || entry.IsFuzzy() || entry.Obsolete {
+			continue
+		}
+		for _, err := range entry.Check(checks, nplurals) {
+			errs = append(errs,
+				&InvalidEntryAtIndexError{
+					Index: index,
+					Reason: &InvalidEntryError{
+						ID:     entry.UnifiedID(),
+						Reason: err,
+					},
+				},
+			)
+		}
+	}
+
+	return errs
+}
+
+// ValidateDomain checks that a domain name is suitable as the name of
+// a file, like GNU msgfmt does with the --check-domain option.
+func ValidateDomain(domain string) error {
+	if domain == "" {
+		return &InvalidDomainError{}
+	}
+	for _, r := range domain {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+			r == '-' || r == '_' || r == '.' {
+			continue
+		}
+		return &InvalidDomainError{Domain: domain, Char: r}
+	}
+
+	return nil
+}
+
+// DefaultAcceleratorMark is the keyboard accelerator mark checked by default.
+const DefaultAcceleratorMark = '&'
+
+// countAccelerators counts the keyboard accelerator marks of s, that is,
+// the occurrences of mark followed by a letter or a digit. A doubled mark
+// is a literal one.
+func countAccelerators(s string, mark rune) int {
+	var count int
+	runes := []rune(s)
+	for i := 0; i < len(runes)-1; i++ {
+		if runes[i] != mark {
+			continue
+		}
+		if runes[i+1] == mark {
+			i++
+			continue
+		}
+		if unicode.IsLetter(runes[i+1]) || unicode.IsDigit(runes[i+1]) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// CheckAccelerators checks that the translations of the entry have exactly
+// one keyboard accelerator mark if its msgid has one, like "&" in "&Open"
+// or "_" in "_Save". For plural entries every form is checked.
+// Empty translations are not checked.
+func (e Entry) CheckAccelerators(mark rune) []error {
+	if countAccelerators(e.ID, mark) != 1 {
+		return nil
+	}
+
+	var errs []error
+	check := func(field, translation string) {
+		if translation == "" {
+			return
+		}
+		if count := countAccelerators(translation, mark); count != 1 {
+			errs = append(errs, &AcceleratorError{Mark: mark, Field: field, Count: count})
+		}
+	}
+
+	if !e.IsPlural() {
+		check("msgstr", e.Str)
+		return errs
+	}
+	for _, pe := range e.Plurals {
+		check(fmt.Sprintf("msgstr[%d]", pe.ID), pe.Str)
+	}
+
+	return errs
+}
+
+// CheckAccelerators checks the keyboard accelerators of all the non fuzzy
+// and non obsolete entries. See [Entry.CheckAccelerators].
+func (e Entries) CheckAccelerators(mark rune) []error {
+	var errs []error
+	for index, entry := range e {
+		if entry.IsHeader() || entry.IsFuzzy() || entry.Obsolete {
+			continue
+		}
+		for _, err := range entry.CheckAccelerators(mark) {
+			errs = append(errs,
+				&InvalidEntryAtIndexError{
+					Index: index,
+					Reason: &InvalidEntryError{
+						ID:     entry.UnifiedID(),
+						Reason: err,
+					},
+				},
+			)
+		}
+	}
+
+	return errs
+}
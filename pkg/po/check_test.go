@@ -0,0 +1,196 @@
+package po_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+)
+
+func TestEntryCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		entry  po.Entry
+		checks po.Check
+		check  func(err error) bool
+	}{
+		{
+			"PluralCount",
+			po.Entry{ID: "file", Plural: "files", Plurals: po.PluralEntries{{ID: 0, Str: "archivo"}}},
+			po.CheckPlurals,
+			func(err error) bool {
+				var e *po.PluralCountError
+				return errors.As(err, &e) && e.Expected == 2 && e.Got == 1
+			},
+		},
+		{
+			"PluralOutOfRange",
+			po.Entry{ID: "file", Plural: "files", Plurals: po.PluralEntries{
+				{ID: 0, Str: "archivo"},
+				{ID: 2, Str: "archivos"},
+			}},
+			po.CheckPlurals,
+			func(err error) bool {
+				var e *po.PluralIndexError
+				return errors.As(err, &e) && e.Index == 2 && !e.Missing
+			},
+		},
+		{
+			"EmptyPluralID",
+			po.Entry{ID: "file", Plurals: po.PluralEntries{{ID: 0}, {ID: 1}}},
+			po.CheckPlurals,
+			func(err error) bool { return errors.Is(err, po.ErrEmptyPluralID) },
+		},
+		{
+			"LeadingNewline",
+			po.Entry{ID: "\nHello", Str: "Hola"},
+			po.CheckNewlines,
+			func(err error) bool {
+				var e *po.NewlineError
+				return errors.As(err, &e) && e.Field == "msgstr" && !e.Trailing
+			},
+		},
+		{
+			"TrailingNewline",
+			po.Entry{ID: "file", Plural: "files\n", Plurals: po.PluralEntries{
+				{ID: 0, Str: "archivo"},
+				{ID: 1, Str: "archivos"},
+			}},
+			po.CheckNewlines,
+			func(err error) bool {
+				var e *po.NewlineError
+				return errors.As(err, &e) && e.Field == "msgid_plural" && e.Trailing
+			},
+		},
+		{
+			"IDChars",
+			po.Entry{ID: "a\x04b", Str: "c"},
+			po.CheckIDChars,
+			func(err error) bool {
+				var e *po.InvalidIDCharError
+				return errors.As(err, &e) && e.Field == "msgid" && e.Char == '\x04' && e.Offset == 1
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := test.entry.Check(test.checks, 2)
+			if len(errs) == 0 {
+				t.Fatal("expected an error")
+			}
+			if !test.check(errs[0]) {
+				t.Errorf("unexpected error: %v", errs[0])
+			}
+			if errs = test.entry.Check(po.CheckAll&^test.checks, 2); len(errs) > 0 {
+				t.Errorf("unselected check reported %v", errs)
+			}
+		})
+	}
+
+	valid := po.Entry{ID: "%d file\n", Plural: "%d files\n", Plurals: po.PluralEntries{
+		{ID: 0, Str: "%d archivo\n"},
+		{ID: 1, Str: "%d archivos\n"},
+	}}
+	if errs := valid.Check(po.CheckAll, 2); len(errs) > 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestEntriesCheckHeader(t *testing.T) {
+	entries := po.Entries{
+		{Str: "Project-Id-Version: PACKAGE VERSION\n" +
+			"PO-Revision-Date: 2025-13-01\n" +
+			"Last-Translator: Jane Doe <jane@example.com>\n" +
+			"Language-Team: Spanish\n" +
+			"Content-Type: text/plain; charset=UTF-8\n" +
+			"Content-Transfer-Encoding: 8bit\n"},
+		{ID: "Hello\n", Str: "Hola"},
+	}
+
+	errs := entries.Check(po.CheckHeader)
+	expected := map[string]po.HeaderFieldErrorReason{
+		"Project-Id-Version": po.HeaderFieldDefault,
+		"PO-Revision-Date":   po.HeaderFieldInvalid,
+		"MIME-Version":       po.HeaderFieldMissing,
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %v", len(expected), errs)
+	}
+	for _, err := range errs {
+		var e *po.HeaderFieldError
+		if !errors.As(err, &e) {
+			t.Errorf("unexpected error type: %v", err)
+			continue
+		}
+		if reason, ok := expected[e.Field]; !ok || reason != e.Reason {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	if errs = entries[1:].Check(po.CheckHeader); len(errs) != 1 || !errors.Is(errs[0], po.ErrMissingHeader) {
+		t.Errorf("expected a missing header error, got %v", errs)
+	}
+}
+
+func TestValidateDomain(t *testing.T) {
+	for _, domain := range []string{"messages", "my-app_1.0"} {
+		if err := po.ValidateDomain(domain); err != nil {
+			t.Errorf("%q: unexpected error: %v", domain, err)
+		}
+	}
+	for _, domain := range []string{"", "my app", "dir/messages"} {
+		var e *po.InvalidDomainError
+		if err := po.ValidateDomain(domain); !errors.As(err, &e) {
+			t.Errorf("%q: expected an InvalidDomainError, got %v", domain, err)
+		}
+	}
+}
+
+func TestCheckAccelerators(t *testing.T) {
+	tests := []struct {
+		entry    po.Entry
+		mark     rune
+		expected []int // Counts of the reported errors.
+	}{
+		{po.Entry{ID: "&Open", Str: "&Abrir"}, '&', nil},
+		{po.Entry{ID: "&Open", Str: "Abrir"}, '&', []int{0}},
+		{po.Entry{ID: "&Open", Str: "&Abrir &archivo"}, '&', []int{2}},
+		{po.Entry{ID: "&Open", Str: "A&&brir"}, '&', []int{0}},
+		{po.Entry{ID: "Save & Close", Str: "Guardar"}, '&', nil},
+		{po.Entry{ID: "&Open", Str: ""}, '&', nil},
+		{po.Entry{ID: "_Save", Str: "Guardar"}, '_', []int{0}},
+		{po.Entry{ID: "_Save", Str: "Guardar"}, '&', nil},
+		{
+			po.Entry{ID: "%d _file", Plural: "%d _files", Plurals: po.PluralEntries{
+				{ID: 0, Str: "%d _archivo"},
+				{ID: 1, Str: "%d archivos"},
+			}},
+			'_', []int{0},
+		},
+	}
+
+	for _, test := range tests {
+		errs := test.entry.CheckAccelerators(test.mark)
+		if len(errs) != len(test.expected) {
+			t.Errorf("%q: expected %d errors, got %v", test.entry.Str, len(test.expected), errs)
+			continue
+		}
+		for i, err := range errs {
+			var e *po.AcceleratorError
+			if !errors.As(err, &e) || e.Count != test.expected[i] || e.Mark != test.mark {
+				t.Errorf("%q: unexpected error %v", test.entry.Str, err)
+			}
+		}
+	}
+
+	entries := po.Entries{
+		{ID: "&Open", Str: "Abrir"},
+		{ID: "&Close", Str: "Cerrar", Flags: []string{"fuzzy"}},
+	}
+	errs := entries.CheckAccelerators(po.DefaultAcceleratorMark)
+	var e *po.InvalidEntryAtIndexError
+	if len(errs) != 1 || !errors.As(errs[0], &e) || e.Index != 0 {
+		t.Errorf("expected an error in the first entry, got %v", errs)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/Tom5521/gotext-tools/v2/internal/slices"
 	"github.com/Tom5521/gotext-tools/v2/internal/util"
 	"github.com/Tom5521/gotext-tools/v2/pkg/po"
 )
@@ -63,6 +64,27 @@ func (mc *MoCompiler) writeTo(writer io.Writer) error {
 		mc.info("sorting entries...")
 		entries = entries.SortFunc(po.CompareEntryByID)
 	}
+	mc.info("checking charset...")
+	declared := entries.Header().Charset()
+	charset := declared
+	if mc.Config.Charset != "" {
+		charset = util.NormalizeCharset(mc.Config.Charset)
+	}
+	if charset == "" {
+		charset = "UTF-8"
+	}
+	if errs := entries.CheckCharset(charset); len(errs) > 0 {
+		if err := mc.error("%w", errs[0]); err != nil {
+			return err
+		}
+		charset = "UTF-8"
+	}
+	// The header must declare the charset the strings are actually encoded in.
+	if entries.HasHeader() && (mc.Config.Charset != "" || declared != "" && declared != charset) {
+		entries = slices.Clone(entries)
+		entries.SetCharset(charset)
+	}
+
 	mc.info("creating header...")
 	var hashTabSize u32
 	if mc.Config.HashTable {
@@ -94,8 +116,14 @@ func (mc *MoCompiler) writeTo(writer io.Writer) error {
 	)
 
 	for index, entry := range entries {
-		msgid := entry.FullUnifiedID()
-		msgstr := entry.UnifiedStr()
+		msgid, err := util.EncodeCharset(entry.FullUnifiedID(), charset)
+		if err != nil {
+			return mc.error("error encoding msgid: %w", err)
+		}
+		msgstr, err := util.EncodeCharset(entry.UnifiedStr(), charset)
+		if err != nil {
+			return mc.error("error encoding msgstr: %w", err)
+		}
 
 		idsOffsets[index] = u32(idsBuf.Len())
 		idsLens[index] = u32(len(msgid))
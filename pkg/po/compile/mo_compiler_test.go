@@ -115,3 +115,52 @@ func TestMoWithMsgunfmt(t *testing.T) {
 		})
 	}
 }
+
+func TestMoCompilerCharset(t *testing.T) {
+	input := po.Entries{
+		{Str: "Content-Type: text/plain; charset=UTF-8\n"},
+		{ID: "File", Str: "ファイル"},
+		{Context: "メニュー", ID: "Open", Str: "開く"},
+	}
+
+	data := compile.MoToBytes(input, compile.MoWithCharset("EUC-JP"))
+	if bytes.Contains(data, []byte("ファイル")) {
+		t.Error("output was not encoded")
+	}
+
+	parser := parse.NewMoFromBytes(data, "test.mo")
+	parsed := parser.Parse()
+	if len(parser.Errors()) > 0 {
+		t.Fatal(parser.Errors()[0])
+	}
+	if parsed.Header().Charset() != "EUC-JP" {
+		t.Errorf("unexpected header charset %q", parsed.Header().Charset())
+	}
+	if !util.Equal(parsed.Entries[1:], input[1:]) {
+		t.Error("compiled and parsed differ")
+		t.Log(util.NamedDiff("parsed", "expected", parsed.Entries[1:], input[1:]))
+	}
+}
+
+func TestMoCompilerCharsetFallback(t *testing.T) {
+	input := po.Entries{
+		{Str: "Content-Type: text/plain; charset=UTF-8\n"},
+		{ID: "File", Str: "ファイル"},
+	}
+
+	// The strings can't be encoded in ISO-8859-1, so they are written in
+	// UTF-8 and the header must say so.
+	data := compile.MoToBytes(input, compile.MoWithCharset("ISO-8859-1"), compile.MoWithIgnoreErrors(true))
+	parser := parse.NewMoFromBytes(data, "test.mo")
+	parsed := parser.Parse()
+	if len(parser.Errors()) > 0 {
+		t.Fatal(parser.Errors()[0])
+	}
+	if charset := parsed.Header().Charset(); charset != "UTF-8" {
+		t.Errorf("the header declares %q instead of the UTF-8 of the strings", charset)
+	}
+	if !util.Equal(parsed.Entries[1:], input[1:]) {
+		t.Error("compiled and parsed differ")
+		t.Log(util.NamedDiff("parsed", "expected", parsed.Entries[1:], input[1:]))
+	}
+}
@@ -29,6 +29,9 @@ type MoConfig struct {
 	Endianness   Endianness
 	// If true, compiles the hash table.
 	HashTable bool
+	// Charset of the strings of the output, the one declared in the header
+	// is used if empty. If set, the charset of the header is replaced.
+	Charset string
 
 	// NOTE: This reaaaaalyyy need to be exposed?
 
@@ -103,6 +106,13 @@ func MoWithSortEntries(s bool) MoOption {
 	}
 }
 
+// MoWithCharset sets the charset of the output strings.
+func MoWithCharset(charset string) MoOption {
+	return func(c *MoConfig) {
+		c.Charset = charset
+	}
+}
+
 // MoWithConfig replaces the entire configuration.
 func MoWithConfig(n MoConfig) MoOption {
 	return func(c *MoConfig) {
@@ -9,7 +9,9 @@ import (
 	"strings"
 
 	"github.com/Tom5521/gotext-tools/v2/internal/slices"
+	"github.com/Tom5521/gotext-tools/v2/internal/util"
 	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+	"golang.org/x/text/transform"
 )
 
 var _ po.Compiler = (*PoCompiler)(nil)
@@ -93,6 +95,23 @@ func (c *PoCompiler) ToBytesWithOptions(opts ...PoOption) []byte {
 // ToWriter writes compiled PO content to an io.Writer.
 // Handles header writing, duplicate cleaning, and optional syntax highlighting.
 func (c PoCompiler) ToWriter(outputWriter io.Writer) error {
+	charset, err := c.prepareCharset()
+	if err != nil {
+		if err = c.error("%w", err); err != nil {
+			return err
+		}
+		charset = "UTF-8"
+	}
+	c.setCharset(charset)
+	c.stampRevision()
+
+	var encoder io.WriteCloser
+	if !util.IsUTF8Charset(charset) {
+		enc, _ := util.CharsetEncoding(charset)
+		encoder = transform.NewWriter(outputWriter, enc.NewEncoder())
+		outputWriter = encoder
+	}
+
 	entries := c.File.Entries
 
 	var highlightBackup *bytes.Buffer
@@ -115,14 +134,18 @@ func (c PoCompiler) ToWriter(outputWriter io.Writer) error {
 		Config: c.Config,
 	}
 
-	if c.Config.ManageHeader && !c.Config.OmitHeader {
-		err := c.writeHeader(writer, &entries, &eb)
-		if err != nil {
-			return c.error("error writing header: %w", err)
+	if c.Config.PreserveSyntax && c.File.Syntax != nil {
+		err = c.compileEntriesWithSyntax(writer, &eb, entries)
+	} else {
+		if c.Config.ManageHeader && !c.Config.OmitHeader {
+			err = c.writeHeader(writer, &entries, &eb)
+			if err != nil {
+				return c.error("error writing header: %w", err)
+			}
 		}
-	}
 
-	err := c.compileEntries(writer, &eb, entries)
+		err = c.compileEntries(writer, &eb, entries)
+	}
 	if err != nil {
 		return c.error("error compiling entries: %w", err)
 	}
@@ -138,10 +161,89 @@ func (c PoCompiler) ToWriter(outputWriter io.Writer) error {
 	if err != nil {
 		return c.error("error flushing buffer: %w", err)
 	}
+	if encoder != nil {
+		if err = encoder.Close(); err != nil {
+			return c.error("error encoding to %s: %w", charset, err)
+		}
+	}
 
 	return nil
 }
 
+// prepareCharset returns the charset of the output, the configured one or
+// else the one of the header, and checks that all the entries can be
+// represented in it.
+func (c PoCompiler) prepareCharset() (string, error) {
+	charset := c.File.Header().Charset()
+	if c.Config.ManageHeader && c.Config.HeaderConfig != nil {
+		// The "CHARSET" placeholder of templates is written as UTF-8.
+		if hc := util.NormalizeCharset(c.Config.HeaderConfig.Charset); hc != "" && hc != "CHARSET" {
+			charset = hc
+		}
+	}
+	if c.Config.Charset != "" {
+		charset = util.NormalizeCharset(c.Config.Charset)
+	}
+
+	if charset == "" || util.IsUTF8Charset(charset) && charset != "ASCII" {
+		return "UTF-8", nil
+	}
+	if errs := c.File.Entries.CheckCharset(charset); len(errs) > 0 {
+		return "", errs[0]
+	}
+
+	return charset, nil
+}
+
+// setCharset makes the header declare the charset the output is encoded in,
+// which is UTF-8 if the entries couldn't be represented in the requested one.
+func (c *PoCompiler) setCharset(charset string) {
+	declared := c.File.Header().Charset()
+	if c.File.HasHeader() && (c.Config.Charset != "" || declared != "" && declared != charset) {
+		entries := slices.Clone(c.File.Entries)
+		entries.SetCharset(charset)
+		c.File = &po.File{Name: c.File.Name, Entries: entries, Syntax: c.File.Syntax}
+	}
+
+	if c.Config.HeaderConfig != nil {
+		hc := util.NormalizeCharset(c.Config.HeaderConfig.Charset)
+		if c.Config.Charset != "" || hc != "" && hc != "CHARSET" && hc != charset {
+			cfg := *c.Config.HeaderConfig
+			cfg.Charset = charset
+			c.Config.HeaderConfig = &cfg
+		}
+	}
+}
+
+// stampRevision updates the revision of the header if StampRevision is
+// enabled and the entries changed.
+func (c *PoCompiler) stampRevision() {
+	if !c.Config.StampRevision || !c.File.HasHeader() || !c.changed() {
+		return
+	}
+
+	entries := slices.Clone(c.File.Entries)
+	entries.StampRevision(po.HeaderNow(), c.Config.LastTranslator)
+	c.File = &po.File{Name: c.File.Name, Entries: entries, Syntax: c.File.Syntax}
+}
+
+// changed reports whether the entries differ from the ones parsed from the
+// original syntax of the file. Without it, the entries are considered changed.
+func (c PoCompiler) changed() bool {
+	syntax := c.File.Syntax
+	if syntax == nil || len(syntax.Blocks) != len(c.File.Entries) {
+		return true
+	}
+	for _, e := range c.File.Entries {
+		block, found := syntax.Lookup(e)
+		if !found || !sameEntry(block.Entry, e) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // ToFile writes compiled output to the specified file path.
 // By default fails if file exists (unless ForcePo is enabled).
 func (c PoCompiler) ToFile(f string) error {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/Tom5521/gotext-tools/v2/internal/color"
 	"github.com/Tom5521/gotext-tools/v2/internal/slices"
@@ -90,6 +91,11 @@ func (eb *entryBuilder) BuildEntry() []byte {
 		prefix += " "
 
 		for _, line := range strings.Split(entry, "\n") {
+			// The previous message of obsolete entries is written as "#~|".
+			if eb.Obsolete && strings.HasPrefix(line, "#|") {
+				buf.WriteString(strings.TrimSuffix(prefix, " ") + line[1:] + "\n")
+				continue
+			}
 			if strings.HasPrefix(line, "#") || line == "" {
 				buf.WriteString(line + "\n")
 				continue
@@ -117,7 +123,20 @@ func (eb *entryBuilder) BuildHeader(header po.Header) []byte {
 		fmt.Fprint(&buf, headerEntry)
 	}
 
-	if eb.Config.HeaderFields {
+	if eb.Config.HeaderFields && eb.Config.WordWrap {
+		var fields strings.Builder
+		for _, field := range header.Fields {
+			fmt.Fprintf(&fields, "%s: %s\n", field.Key, field.Value)
+		}
+		// The empty first line is already written by the header format.
+		lines := wrapLines(fields.String(), len("msgstr"), 0, eb.wrapWidth())
+		if lines[0] == "" {
+			lines = lines[1:]
+		}
+		for _, line := range lines {
+			fmt.Fprintf(&buf, "\"%s\"\n", line)
+		}
+	} else if eb.Config.HeaderFields {
 		for i, field := range header.Fields {
 			fmt.Fprintf(&buf, headerFieldFormat, field.Key, field.Value)
 
@@ -129,9 +148,6 @@ func (eb *entryBuilder) BuildHeader(header po.Header) []byte {
 
 	fmt.Fprintln(&buf)
 
-	buf.WriteString(eb.msgid())
-	buf.WriteString(eb.msgstr())
-
 	return buf.Bytes()
 }
 
@@ -139,14 +155,14 @@ func (eb *entryBuilder) msgid() string {
 	var b strings.Builder
 	if eb.HasContext() {
 		b.WriteString(eb.keyword("msgctxt"))
-		b.WriteString(eb.string(eb.Context, "msgid"))
+		b.WriteString(eb.string("msgctxt", eb.Context, "msgid"))
 	}
 	b.WriteString(eb.keyword("msgid"))
-	b.WriteString(eb.string(eb.ID, "msgid"))
+	b.WriteString(eb.string("msgid", eb.ID, "msgid"))
 
 	if eb.IsPlural() {
 		b.WriteString(eb.keyword("msgid_plural"))
-		b.WriteString(eb.string(eb.Plural, "msgid"))
+		b.WriteString(eb.string("msgid_plural", eb.Plural, "msgid"))
 	}
 
 	return b.String()
@@ -157,17 +173,19 @@ func (eb *entryBuilder) msgstr() string {
 	const format = "msgstr[%d]"
 	if eb.IsPlural() {
 		if len(eb.Plurals) == 0 {
-			id := eb.string(eb.ID, "msgstr")
 			for i := 0; i < 2; i++ {
-				fmt.Fprint(&msgstr, eb.keyword(fmt.Sprintf(format, i)))
-				fmt.Fprint(&msgstr, id)
+				keyword := fmt.Sprintf(format, i)
+				fmt.Fprint(&msgstr, eb.keyword(keyword))
+				fmt.Fprint(&msgstr, eb.string(keyword, eb.ID, "msgstr"))
 			}
 			return msgstr.String()
 		}
 		for _, pe := range eb.Plurals {
-			fmt.Fprint(&msgstr, eb.keyword(fmt.Sprintf(format, pe.ID)))
+			keyword := fmt.Sprintf(format, pe.ID)
+			fmt.Fprint(&msgstr, eb.keyword(keyword))
 			fmt.Fprint(&msgstr,
 				eb.string(
+					keyword,
 					eb.Config.MsgstrPrefix+pe.Str+eb.Config.MsgstrSuffix,
 					"msgstr",
 				),
@@ -179,6 +197,7 @@ func (eb *entryBuilder) msgstr() string {
 
 	fmt.Fprint(&msgstr, eb.keyword("msgstr"))
 	fmt.Fprint(&msgstr, eb.string(
+		"msgstr",
 		eb.Config.MsgstrPrefix+eb.Str+eb.Config.MsgstrSuffix,
 		"msgstr",
 	))
@@ -217,73 +236,121 @@ func (eb *entryBuilder) referenceComment() string {
 	if eb.Config.NoLocation || eb.Config.AddLocation == PoLocationModeNever {
 		return ""
 	}
+
+	withLine := eb.Config.AddLocation != PoLocationModeFile
+	seen := make(map[string]bool, len(eb.Locations))
+
 	var b strings.Builder
+	var lineLen int
+	for _, l := range eb.Locations {
+		ref := l.Reference(withLine)
+		// Without lines, each file is referenced only once.
+		if !withLine {
+			if seen[ref] {
+				continue
+			}
+			seen[ref] = true
+		}
 
-	var writeRef func(id int)
-	switch eb.Config.AddLocation {
-	case PoLocationModeFull:
-		writeRef = func(id int) {
-			l := eb.Locations[id]
-			fmt.Fprintf(&b, "%s:%d\n", l.File, l.Line)
+		refLen := utf8.RuneCountInString(ref)
+		// References are packed in as few lines as possible like GNU gettext does.
+		if lineLen > 0 && lineLen+1+refLen > eb.pageWidth() {
+			b.WriteString("\n")
+			lineLen = 0
 		}
-	case PoLocationModeFile:
-		writeRef = func(id int) {
-			l := eb.Locations[id]
-			fmt.Fprintf(&b, "%s\n", l.File)
+		if lineLen == 0 {
+			b.WriteString("#:")
+			lineLen = 2
 		}
+		b.WriteString(" " + ref)
+		lineLen += 1 + refLen
 	}
-
-	for i := range eb.Locations {
-		fmt.Fprint(&b, "#: ")
-		writeRef(i)
+	if lineLen > 0 {
+		b.WriteString("\n")
 	}
 
 	return b.String()
 }
 
 func (eb *entryBuilder) flagComment() string {
-	var comments string
-	for _, f := range eb.Flags {
-		comment := fmt.Sprintf("#, %s\n", f)
-		comments += comment
+	flags := po.CanonicalFlags(eb.Flags)
+	if len(flags) == 0 {
+		return ""
 	}
 
-	return comments
+	return fmt.Sprintf("#, %s\n", strings.Join(flags, ", "))
 }
 
 func (eb *entryBuilder) previousComment() string {
 	var b strings.Builder
-	for _, p := range eb.Previous {
-		fmt.Fprintf(&b, "#| %s\n", p)
+	width := -1
+	if eb.Config.WordWrap {
+		width = eb.wrapWidth()
 	}
+	previousString := func(keyword, str string) {
+		if str == "" {
+			return
+		}
+		lines := wrapLines(str, utf8.RuneCountInString(keyword), len("#| "), width)
+		fmt.Fprintf(&b, "#| %s \"%s\"\n", keyword, lines[0])
+		for _, line := range lines[1:] {
+			fmt.Fprintf(&b, "#| \"%s\"\n", line)
+		}
+	}
+
+	previousString("msgctxt", eb.Previous.Context)
+	previousString("msgid", eb.Previous.ID)
+	previousString("msgid_plural", eb.Previous.Plural)
+
 	return b.String()
 }
 
-func (eb *entryBuilder) string(str string, styles ...string) string {
-	var builder strings.Builder
+// pageWidth returns the width of the output page.
+func (eb *entryBuilder) pageWidth() int {
+	if eb.Config.Width > 0 {
+		return eb.Config.Width
+	}
+	return DefaultWidth
+}
+
+// wrapWidth returns the width the strings of the entry are wrapped at,
+// or -1 if long lines must not be wrapped.
+func (eb *entryBuilder) wrapWidth() int {
+	if eb.Config.Width < 0 || eb.IsNoWrap() {
+		return -1
+	}
+	return eb.pageWidth()
+}
+
+// prefixWidth returns the width of the prefix written before the
+// keywords and strings of the entry, like "#~ " for obsolete entries.
+func (eb *entryBuilder) prefixWidth() int {
+	switch {
+	case eb.Obsolete && eb.Config.UseCustomObsoletePrefix:
+		return 2 + runeWidth(eb.Config.CustomObsoletePrefixRune)
+	case eb.Obsolete:
+		return len("#~ ")
+	case eb.IsFuzzy() && eb.Config.CommentFuzzy:
+		return len("# ")
+	}
+	return 0
+}
+
+// string writes str as the value of keyword. With WordWrap it is split in
+// several lines like GNU gettext does (see [PoConfig.WordWrap]).
+func (eb *entryBuilder) string(keyword, str string, styles ...string) string {
+	lines := []string{escapePOString(str)}
 	if eb.Config.WordWrap {
-		lines := strings.Split(str, "\n")
-		for i, line := range lines {
-			if i != len(lines)-1 {
-				line += "\n"
-			}
-			fmt.Fprint(&builder, `"`)
-			fmt.Fprint(&builder, eb.text(escapePOString(line),
-				slices.Delete(styles, 0, 1)...))
-			builder.WriteString(eb.applyStyle(`"`, "string") + "\n")
-		}
-		return eb.applyStyle(
-			builder.String(),
-			append(styles, "string")...,
-		)
+		lines = wrapLines(str, utf8.RuneCountInString(keyword), eb.prefixWidth(), eb.wrapWidth())
 	}
 
-	fmt.Fprint(&builder, `"`)
-	fmt.Fprint(&builder, eb.text(escapePOString(str),
-		slices.Delete(styles, 0, 1)...,
-	))
-	builder.WriteString(eb.applyStyle(`"`, "string") + "\n")
-	// fmt.Fprintf(&builder, "\"%s\"\n", escapePOString(str))
+	var builder strings.Builder
+	textStyles := slices.Delete(styles, 0, 1)
+	for _, line := range lines {
+		fmt.Fprint(&builder, `"`)
+		fmt.Fprint(&builder, eb.text(line, textStyles...))
+		builder.WriteString(eb.applyStyle(`"`, "string") + "\n")
+	}
 
 	return eb.applyStyle(
 		builder.String(),
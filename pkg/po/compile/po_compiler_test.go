@@ -2,6 +2,10 @@ package compile_test
 
 import (
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/Tom5521/gotext-tools/v2/internal/util"
@@ -26,7 +30,7 @@ func TestPoCompiler(t *testing.T) {
 			Flags:             []string{"fuzzy"},
 			Comments:          []string{"Plural forms for items"},
 			ExtractedComments: []string{"Shopping cart module"},
-			Previous:          []string{},
+			Previous:          po.PreviousMessage{ID: "%d item\nold"},
 			// Obsolete:          true,
 			ID:      "%d item\nlol\nline2",
 			Context: "shopping_cart",
@@ -71,3 +75,387 @@ func TestPoCompiler(t *testing.T) {
 		})
 	}
 }
+
+func TestPoCompilerPreserveSyntax(t *testing.T) {
+	const input = `# Spanish translation.
+msgid ""
+msgstr ""
+"Project-Id-Version: test\n"
+"Last-Translator: Nobody\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+
+#, fuzzy
+#: main.go:10
+# translator comment after the flags
+msgid "Hello"
+msgstr "Hola"
+
+
+msgid ""
+"A long message that was wrapped "
+"by hand"
+msgstr ""
+"Un mensaje largo que fue partido "
+"a mano"
+
+#~ msgid "Old"
+#~ msgstr "Viejo"
+# trailing comment
+`
+
+	file, err := parse.PoFromString(input, "es.po", parse.PoWithKeepSyntax(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := compile.PoToString(file, compile.PoWithPreserveSyntax(true))
+	if output != input {
+		t.Errorf("unmodified file was rewritten")
+		t.Log(util.NamedDiff("output", "input", output, input))
+	}
+
+	file.Set("Hello", "", po.Entry{ID: "Hello", Str: "Hola mundo"})
+	file.Entries = append(file.Entries, po.Entry{ID: "New", Str: "Nuevo"})
+
+	expected := strings.Replace(input, `#, fuzzy
+#: main.go:10
+# translator comment after the flags
+msgid "Hello"
+msgstr "Hola"
+`, `msgid "Hello"
+msgstr "Hola mundo"
+`, 1)
+	expected = strings.Replace(expected, `#~ msgstr "Viejo"
+`, `#~ msgstr "Viejo"
+
+msgid "New"
+msgstr "Nuevo"
+`, 1)
+
+	output = compile.PoToString(file, compile.PoWithPreserveSyntax(true))
+	if output != expected {
+		t.Errorf("unexpected output")
+		t.Log(util.NamedDiff("output", "expected", output, expected))
+	}
+}
+
+func TestPoCompilerPreserveSyntaxCRLF(t *testing.T) {
+	input := strings.ReplaceAll(`# Spanish translation.
+msgid ""
+msgstr ""
+"Language: es\n"
+
+# translator comment
+#. extracted comment
+#: main.go:10
+#, fuzzy
+msgid "Hello"
+msgstr "Hola"
+
+#~ msgid "Old"
+#~ msgstr "Viejo"
+`, "\n", "\r\n")
+
+	file, err := parse.PoFromString(input, "es.po", parse.PoWithKeepSyntax(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := compile.PoToString(file, compile.PoWithPreserveSyntax(true))
+	if output != input {
+		t.Errorf("unmodified file was rewritten")
+		t.Log(util.NamedDiff("output", "input", output, input))
+	}
+}
+
+func TestPoCompilerCharsetFallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		options []compile.PoOption
+	}{
+		{"Config", "Content-Type: text/plain; charset=UTF-8\n", []compile.PoOption{compile.PoWithCharset("ISO-8859-1")}},
+		{"Header", "Content-Type: text/plain; charset=ISO-8859-1\n", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			input := po.Entries{{Str: test.header}, {ID: "File", Str: "ファイル"}}
+
+			// The strings can't be encoded in ISO-8859-1, so they are written
+			// in UTF-8 and the header must say so.
+			options := append([]compile.PoOption{compile.PoWithIgnoreErrors(true)}, test.options...)
+			output := compile.PoToString(&po.File{Entries: input}, options...)
+			parsed, err := parse.PoFromString(output, "test.po")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if charset := parsed.Header().Charset(); charset != "UTF-8" {
+				t.Errorf("the header declares %q instead of the UTF-8 of the strings", charset)
+			}
+			if !util.Equal(parsed.Entries[1:], input[1:]) {
+				t.Error("compiled and parsed differ")
+				t.Log(util.NamedDiff("parsed", "expected", parsed.Entries[1:], input[1:]))
+			}
+		})
+	}
+}
+
+func TestPoCompilerTemplateHeader(t *testing.T) {
+	cfg := po.DefaultTemplateHeaderConfig()
+	file := &po.File{Entries: po.Entries{cfg.ToHeader().ToEntry(), {ID: "Hello"}}}
+
+	c := compile.NewPo(file,
+		compile.PoWithManageHeader(true),
+		compile.PoWithHeaderFields(true),
+		func(pc *compile.PoConfig) { pc.HeaderConfig = &cfg },
+	)
+
+	var b strings.Builder
+	if err := c.ToWriter(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "charset=CHARSET") {
+		t.Errorf("the charset placeholder was not kept:\n%s", b.String())
+	}
+}
+
+func TestPoCompilerStampRevision(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	const input = `msgid ""
+msgstr ""
+"Project-Id-Version: test\n"
+"PO-Revision-Date: 2020-01-01 00:00+0000\n"
+"Last-Translator: Nobody\n"
+
+msgid "Hello"
+msgstr "Hola"
+`
+
+	file, err := parse.PoFromString(input, "es.po", parse.PoWithKeepSyntax(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	crlfInput := strings.ReplaceAll("# Spanish translation.\n"+input, "\n", "\r\n")
+	crlf, err := parse.PoFromString(crlfInput, "es.po", parse.PoWithKeepSyntax(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := []compile.PoOption{
+		compile.PoWithPreserveSyntax(true),
+		compile.PoWithWordWrap(true),
+		compile.PoWithStampRevision(true),
+		compile.PoWithLastTranslator("Jane Doe <jane@example.com>"),
+	}
+	output := compile.PoToString(file, options...)
+	if output != input {
+		t.Errorf("revision stamped without changes")
+		t.Log(util.NamedDiff("output", "input", output, input))
+	}
+	if output = compile.PoToString(crlf, options...); output != crlfInput {
+		t.Errorf("revision of a CRLF file stamped without changes")
+		t.Log(util.NamedDiff("output", "input", output, crlfInput))
+	}
+
+	file.Set("Hello", "", po.Entry{ID: "Hello", Str: "Hola mundo"})
+	output = compile.PoToString(file, options...)
+	for _, field := range []string{
+		`"PO-Revision-Date: 2023-11-14 22:13+0000\n"`,
+		`"Last-Translator: Jane Doe <jane@example.com>\n"`,
+	} {
+		if !strings.Contains(output, field) {
+			t.Errorf("field %s not found in output:\n%s", field, output)
+		}
+	}
+	if header := file.Header(); header.Load("Last-Translator") != "Nobody" {
+		t.Error("the compiled file was modified")
+	}
+}
+
+func TestPoCompilerWordWrap(t *testing.T) {
+	const lorem = "Lorem ipsum dolor sit amet, consectetur adipiscing elit, " +
+		"sed do eiusmod tempor incididunt ut labore et dolore magna aliqua."
+
+	tests := []struct {
+		name     string
+		entry    po.Entry
+		options  []compile.PoOption
+		expected string
+	}{
+		{
+			"Short",
+			po.Entry{ID: "Hello", Str: "Hola\n"},
+			nil,
+			`msgid "Hello"
+msgstr "Hola\n"
+`,
+		},
+		{
+			"Long",
+			po.Entry{ID: lorem},
+			nil,
+			`msgid ""
+"Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod "
+"tempor incididunt ut labore et dolore magna aliqua."
+msgstr ""
+`,
+		},
+		{
+			"Newlines",
+			po.Entry{ID: "Usage: %s [OPTION]...\nPrint things.\n", Str: "a\n\nb"},
+			nil,
+			`msgid ""
+"Usage: %s [OPTION]...\n"
+"Print things.\n"
+msgstr ""
+"a\n"
+"\n"
+"b"
+`,
+		},
+		{
+			"PluralForms",
+			po.Entry{
+				ID: "x",
+				Str: "Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : " +
+					"n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n",
+			},
+			nil,
+			`msgid "x"
+msgstr ""
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && "
+"n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+`,
+		},
+		{
+			"Width",
+			po.Entry{ID: lorem},
+			[]compile.PoOption{compile.PoWithWidth(40)},
+			`msgid ""
+"Lorem ipsum dolor sit amet, "
+"consectetur adipiscing elit, sed do "
+"eiusmod tempor incididunt ut labore "
+"et dolore magna aliqua."
+msgstr ""
+`,
+		},
+		{
+			"NoWrapFlag",
+			po.Entry{ID: lorem, Flags: []string{po.FlagNoWrap}},
+			nil,
+			`#, no-wrap
+msgid "` + lorem + `"
+msgstr ""
+`,
+		},
+		{
+			"NegativeWidth",
+			po.Entry{ID: lorem, Str: "a\nb"},
+			[]compile.PoOption{compile.PoWithWidth(-1)},
+			`msgid "` + lorem + `"
+msgstr ""
+"a\n"
+"b"
+`,
+		},
+		{
+			"Obsolete",
+			po.Entry{ID: lorem, Obsolete: true},
+			nil,
+			`#~ msgid ""
+#~ "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod "
+#~ "tempor incididunt ut labore et dolore magna aliqua."
+#~ msgstr ""
+`,
+		},
+		{
+			"Escapes",
+			po.Entry{ID: strings.Repeat("x", 66) + ` "quoted" text`},
+			nil,
+			`msgid ""
+"xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx "
+"\"quoted\" text"
+msgstr ""
+`,
+		},
+		{
+			"Wide",
+			po.Entry{ID: "cjk", Str: strings.Repeat("日本語", 14)},
+			nil,
+			`msgid "cjk"
+msgstr ""
+"日本語日本語日本語日本語日本語日本語日本語日本語日本語日本語日本語日本語日本"
+"語日本語"
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := append([]compile.PoOption{compile.PoWithWordWrap(true)}, test.options...)
+			output := compile.PoToString(&po.File{Entries: po.Entries{test.entry}}, options...)
+			output = strings.TrimRight(output, "\n") + "\n"
+			if output != test.expected {
+				t.Errorf("unexpected output")
+				t.Log(util.NamedDiff("output", "expected", output, test.expected))
+			}
+
+			parsed, err := parse.PoFromString(output, "test.po")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsed.Entries[0].ID != test.entry.ID || parsed.Entries[0].Str != test.entry.Str {
+				t.Errorf("the wrapped strings changed: %q", parsed.Entries[0].ID)
+			}
+		})
+	}
+}
+
+func TestPoWrapWithMsgcat(t *testing.T) {
+	msgcatPath, err := exec.LookPath("msgcat")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	// Strings of n columns, breakable at their spaces, whose msgid line
+	// fits exactly in the default width or exceeds it by one column.
+	words := func(n int) string {
+		return strings.Repeat("x", n%5) + strings.Repeat(" word", n/5)
+	}
+	const msgidColumns = compile.DefaultWidth - len(`msgid ""`)
+	fits, exceeds := words(msgidColumns), words(msgidColumns+1)
+
+	file := &po.File{Entries: po.Entries{
+		{Str: "Content-Type: text/plain; charset=UTF-8\n"},
+		{ID: "url", Str: "See https://www.gnu.org/software/gettext/manual/html_node/PO-Files.html " +
+			"for the details of the format, or https://example.com/a?b=c&d=e#f."},
+		{ID: "cjk", Str: strings.Repeat("日本語", 14)},
+		{ID: "cjk sentence", Str: "これは日本語の文です。翻訳ファイルの行は、ページの幅で折り返されます。" +
+			"全角の文字は二つの列を占めます。"},
+		{ID: "escapes", Str: "Tab\there, \"quoted\" text, a back\\slash and a bell\a, " +
+			strings.Repeat("then more text ", 4) + "\nand a new line."},
+		{ID: fits, Str: exceeds},
+		{ID: exceeds, Str: fits},
+		{ID: "obsolete " + exceeds, Obsolete: true},
+	}}
+
+	for _, width := range []int{compile.DefaultWidth, 40} {
+		t.Run(fmt.Sprint(width), func(t *testing.T) {
+			input := compile.PoToString(file, compile.PoWithWordWrap(true), compile.PoWithWidth(width))
+			path := filepath.Join(t.TempDir(), "input.po")
+			if err := os.WriteFile(path, []byte(input), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			output, err := exec.Command(msgcatPath, fmt.Sprintf("--width=%d", width), path).Output()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(output) != input {
+				t.Error("the strings were wrapped unlike msgcat")
+				t.Log(util.NamedDiff("msgcat", "compiler", string(output), input))
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/Tom5521/gotext-tools/v2/internal/slices"
+	"github.com/Tom5521/gotext-tools/v2/internal/util"
 	"github.com/Tom5521/gotext-tools/v2/pkg/po"
 )
 
@@ -39,6 +40,65 @@ func (c PoCompiler) compileEntries(writer io.Writer, eb *entryBuilder, entries p
 	return nil
 }
 
+// compileEntriesWithSyntax writes the entries that are equal to the ones
+// parsed from the original file with their original text, and renders
+// the rest. Modified entries keep the blank lines that preceded them.
+func (c PoCompiler) compileEntriesWithSyntax(writer io.Writer, eb *entryBuilder, entries po.Entries) error {
+	c.info("writing entries preserving their syntax...")
+
+	syntax := c.File.Syntax
+	for i, e := range entries {
+		var text string
+		block, found := syntax.Lookup(e)
+		switch {
+		case found && sameEntry(block.Entry, e):
+			text = block.Leading + block.Text
+		case found:
+			eb.Entry = e
+			text = block.Leading + strings.TrimSuffix(string(eb.BuildEntry()), "\n")
+		default:
+			eb.Entry = e
+			text = strings.TrimSuffix(string(eb.BuildEntry()), "\n")
+			if i > 0 {
+				text = "\n" + text
+			}
+		}
+
+		if _, err := io.WriteString(writer, text); err != nil {
+			return c.error("error writing entry: %w", err)
+		}
+	}
+
+	if _, err := io.WriteString(writer, syntax.Trailer); err != nil {
+		return c.error("error writing entry: %w", err)
+	}
+
+	return nil
+}
+
+// sameEntry reports whether the entries are equal, ignoring the carriage
+// returns that the comments of the files with CRLF line endings may keep.
+func sameEntry(a, b po.Entry) bool {
+	return util.Equal(trimCR(a), trimCR(b))
+}
+
+func trimCR(e po.Entry) po.Entry {
+	trim := func(lines []string) []string {
+		if lines == nil {
+			return nil
+		}
+		trimmed := make([]string, len(lines))
+		for i, line := range lines {
+			trimmed[i] = strings.TrimSuffix(line, "\r")
+		}
+		return trimmed
+	}
+	e.Comments = trim(e.Comments)
+	e.ExtractedComments = trim(e.ExtractedComments)
+
+	return e
+}
+
 func escapePOString(s string) string {
 	var buf strings.Builder
 	for _, r := range s {
@@ -53,6 +113,14 @@ func escapePOString(s string) string {
 			buf.WriteString(`\t`)
 		case '\r':
 			buf.WriteString(`\r`)
+		case '\a':
+			buf.WriteString(`\a`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\v':
+			buf.WriteString(`\v`)
 		default:
 			if strconv.IsPrint(r) {
 				buf.WriteRune(r)
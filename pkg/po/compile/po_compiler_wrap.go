@@ -0,0 +1,359 @@
+package compile
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+// DefaultWidth is the page width used by GNU gettext to wrap the strings
+// and pack the references of the entries.
+const DefaultWidth = 79
+
+// wrapLines splits a string into the escaped lines it is written in, like
+// the wrap function of GNU gettext does: a line ends after each newline and,
+// when a line doesn't fit in width, at its last line break opportunity.
+//
+// The first line follows a keyword of keywordWidth columns, and every line
+// starts with a prefix of prefixWidth columns, like "#~ " or "#| ". If the
+// string spans several lines, the first one is left empty.
+// A width of zero or less disables the wrapping of long lines.
+func wrapLines(str string, keywordWidth, prefixWidth, width int) []string {
+	// Columns available for the text of a line, besides its prefix and quotes.
+	available := math.MaxInt32
+	if width > 0 {
+		available = width - prefixWidth - 2
+	}
+
+	portions := strings.SplitAfter(str, "\n")
+	if len(portions) > 1 && portions[len(portions)-1] == "" {
+		portions = portions[:len(portions)-1]
+	}
+
+	var lines []string
+	first := true
+	for i, portion := range portions {
+		text, noBreak := escapeWrapText(portion)
+
+		startColumn := 0
+		if first {
+			startColumn = keywordWidth + 1
+		}
+		breaks := widthLineBreaks(text, noBreak, available, startColumn)
+		if first && len(text) > 0 &&
+			(i < len(portions)-1 || startColumn > available || containsTrue(breaks)) {
+			lines = append(lines, "")
+			breaks = widthLineBreaks(text, noBreak, available, 0)
+		}
+		first = false
+
+		start := 0
+		for j, b := range breaks {
+			if b {
+				lines = append(lines, string(text[start:j]))
+				start = j
+			}
+		}
+		lines = append(lines, string(text[start:]))
+	}
+
+	return lines
+}
+
+func containsTrue(s []bool) bool {
+	for _, b := range s {
+		if b {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeWrapText escapes a portion of a string, returning its runes and
+// the positions where a line can't be broken: inside escape sequences
+// and before the newline that ends the portion.
+func escapeWrapText(portion string) ([]rune, []bool) {
+	var text []rune
+	var noBreak []bool
+	for _, r := range portion {
+		for i, er := range escapePOString(string(r)) {
+			text = append(text, er)
+			noBreak = append(noBreak, i > 0)
+		}
+	}
+
+	if strings.HasSuffix(portion, "\n") {
+		noBreak[len(noBreak)-2] = true
+	}
+
+	return text, noBreak
+}
+
+// widthLineBreaks chooses the line break opportunities of text where the
+// lines are broken so they fit in width, the first one starting at
+// startColumn. Opportunities are taken as late as possible, as
+// u8_width_linebreaks of libunistring does.
+func widthLineBreaks(text []rune, noBreak []bool, width, startColumn int) []bool {
+	possible := lineBreakOpportunities(text)
+	breaks := make([]bool, len(text))
+
+	last := -1
+	lastColumn := startColumn
+	pieceWidth := 0
+	for i, r := range text {
+		if possible[i] && !noBreak[i] {
+			// A piece of text that can't be broken ends here.
+			if last != -1 && lastColumn+pieceWidth > width {
+				breaks[last] = true
+				lastColumn = 0
+			}
+			last = i
+			lastColumn += pieceWidth
+			pieceWidth = 0
+		}
+		pieceWidth += runeWidth(r)
+	}
+	if last != -1 && lastColumn+pieceWidth > width {
+		breaks[last] = true
+	}
+
+	return breaks
+}
+
+// runeWidth returns the number of columns used by r in a terminal.
+func runeWidth(r rune) int {
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf, unicode.Cc) {
+		return 0
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// lineBreakClass is a line breaking class of the Unicode line breaking
+// algorithm (UAX #14). Only the classes that matter for the texts of
+// catalogs are distinguished; any other character is alphabetic.
+type lineBreakClass uint8
+
+const (
+	lbAL lineBreakClass = iota // Alphabetic.
+	lbSP                       // Space.
+	lbBA                       // Break after, like tab or the vertical line.
+	lbB2                       // Break before and after, the em dash.
+	lbHY                       // Hyphen-minus.
+	lbOP                       // Opening punctuation.
+	lbCL                       // Closing punctuation.
+	lbCP                       // Closing parenthesis and bracket.
+	lbQU                       // Quotation marks.
+	lbGL                       // Non-breaking glue, like the no-break space.
+	lbNS                       // Non-starters, like small kana.
+	lbEX                       // Exclamation and interrogation.
+	lbSY                       // Solidus.
+	lbIS                       // Infix numeric separators, like the comma and the full stop.
+	lbPR                       // Prefix numeric, like currency symbols and the backslash.
+	lbPO                       // Postfix numeric, like the percent sign.
+	lbNU                       // Numeric.
+	lbID                       // Ideographic.
+	lbIN                       // Inseparable, like the ellipsis.
+	lbCM                       // Combining marks.
+	lbWJ                       // Word joiner.
+	lbZW                       // Zero width space.
+)
+
+var asciiLineBreakClasses = map[rune]lineBreakClass{
+	' ': lbSP, '\t': lbBA, '|': lbBA,
+	'-': lbHY,
+	'(': lbOP, '[': lbOP, '{': lbOP,
+	'}': lbCL,
+	')': lbCP, ']': lbCP,
+	'"': lbQU, '\'': lbQU,
+	'!': lbEX, '?': lbEX,
+	'/': lbSY,
+	',': lbIS, '.': lbIS, ':': lbIS, ';': lbIS,
+	'$': lbPR, '+': lbPR, '\\': lbPR,
+	'%': lbPO,
+}
+
+var unicodeLineBreakClasses = map[rune]lineBreakClass{
+	'\u00a0': lbGL, '\u2007': lbGL, '\u202f': lbGL,
+	'\u2060': lbWJ, '\ufeff': lbWJ,
+	'\u200b': lbZW,
+	'\u200c': lbCM, '\u200d': lbCM,
+	'\u00ad': lbBA, '‐': lbBA, '–': lbBA, '\u3000': lbBA,
+	'—': lbB2,
+	'¡': lbOP, '¿': lbOP, '‚': lbOP, '„': lbOP,
+	'«': lbQU, '»': lbQU, '‘': lbQU, '’': lbQU, '‛': lbQU,
+	'“': lbQU, '”': lbQU, '‟': lbQU, '‹': lbQU, '›': lbQU,
+	'․': lbIN, '‥': lbIN, '…': lbIN,
+	'£': lbPR, '¥': lbPR, '€': lbPR,
+	'°': lbPO, '‰': lbPO, '′': lbPO, '″': lbPO,
+	// CJK punctuation.
+	'、': lbCL, '。': lbCL, '，': lbCL, '．': lbCL,
+	'〉': lbCL, '》': lbCL, '」': lbCL, '』': lbCL, '】': lbCL,
+	'〕': lbCL, '〗': lbCL, '〙': lbCL, '〛': lbCL, '〞': lbCL, '〟': lbCL,
+	'）': lbCP, '］': lbCP, '｝': lbCL,
+	'〈': lbOP, '《': lbOP, '「': lbOP, '『': lbOP, '【': lbOP,
+	'〔': lbOP, '〖': lbOP, '〘': lbOP, '〚': lbOP, '〝': lbOP,
+	'（': lbOP, '［': lbOP, '｛': lbOP,
+	'！': lbEX, '？': lbEX,
+	'：': lbNS, '；': lbNS,
+	'々': lbNS, '〜': lbNS, '〻': lbNS, '゠': lbNS, '・': lbNS,
+	'ー': lbNS, '゛': lbNS, '゜': lbNS, 'ゝ': lbNS, 'ゞ': lbNS,
+	'ヽ': lbNS, 'ヾ': lbNS,
+}
+
+// smallKana are the small hiragana and katakana, which can't start a line.
+const smallKana = "ぁぃぅぇぉっゃゅょゎゕゖァィゥェォッャュョヮヵヶ"
+
+func lineBreakClassOf(r rune) lineBreakClass {
+	if r < 0x80 {
+		if c, ok := asciiLineBreakClasses[r]; ok {
+			return c
+		}
+		if r >= '0' && r <= '9' {
+			return lbNU
+		}
+		if r < 0x20 || r == 0x7f {
+			return lbCM
+		}
+		return lbAL
+	}
+
+	if c, ok := unicodeLineBreakClasses[r]; ok {
+		return c
+	}
+	switch {
+	case strings.ContainsRune(smallKana, r), r >= 0x31f0 && r <= 0x31ff, r >= 0xff67 && r <= 0xff70:
+		return lbNS
+	case unicode.In(r, unicode.Mn, unicode.Mc, unicode.Me):
+		return lbCM
+	case unicode.IsDigit(r) && r < 0xff10:
+		return lbNU
+	case isIdeographic(r):
+		return lbID
+	}
+
+	return lbAL
+}
+
+// isIdeographic reports whether r is a CJK character or an emoji, which
+// can be broken before and after.
+func isIdeographic(r rune) bool {
+	switch {
+	case r >= 0x2e80 && r <= 0x2fff, // Radicals and ideographic description.
+		r >= 0x3040 && r <= 0x30ff,   // Hiragana and Katakana.
+		r >= 0x3100 && r <= 0x4dbf,   // Bopomofo, Hangul compatibility, CJK extension A...
+		r >= 0x4e00 && r <= 0x9fff,   // CJK unified ideographs.
+		r >= 0xa000 && r <= 0xa4cf,   // Yi.
+		r >= 0xac00 && r <= 0xd7a3,   // Hangul syllables.
+		r >= 0xf900 && r <= 0xfaff,   // CJK compatibility ideographs.
+		r >= 0xfe30 && r <= 0xfe4f,   // CJK compatibility forms.
+		r >= 0xff01 && r <= 0xff60,   // Fullwidth forms.
+		r >= 0x1f000 && r <= 0x1faff, // Emoji and pictographs.
+		r >= 0x20000 && r <= 0x3fffd: // CJK extensions.
+		return true
+	}
+	return false
+}
+
+// lineBreakOpportunities returns, for each rune of text, whether the line
+// can be broken before it, following the rules of UAX #14.
+func lineBreakOpportunities(text []rune) []bool {
+	classes := make([]lineBreakClass, len(text))
+	combining := make([]bool, len(text))
+	for i, r := range text {
+		c := lineBreakClassOf(r)
+		if c == lbCM {
+			// Combining marks take the class of their base character (LB9, LB10).
+			combining[i] = true
+			c = lbAL
+			if i > 0 && classes[i-1] != lbSP && classes[i-1] != lbZW {
+				c = classes[i-1]
+			}
+		}
+		classes[i] = c
+	}
+
+	possible := make([]bool, len(text))
+	for i := 1; i < len(text); i++ {
+		// Don't break before spaces and combining marks (LB7, LB9).
+		if combining[i] || classes[i] == lbSP || classes[i] == lbZW {
+			continue
+		}
+
+		j := i - 1
+		for j >= 0 && classes[j] == lbSP {
+			j--
+		}
+		// Leading spaces aren't broken (LB2).
+		if j < 0 {
+			continue
+		}
+
+		possible[i] = canBreakBetween(classes[j], classes[i], j < i-1)
+	}
+
+	return possible
+}
+
+// canBreakBetween reports whether a line can be broken between a character
+// of class before and the following one of class after, which may be
+// separated by spaces.
+func canBreakBetween(before, after lineBreakClass, spaces bool) bool {
+	switch {
+	case before == lbZW: // LB8
+		return true
+	case after == lbWJ: // LB11
+		return false
+	case after == lbCL, after == lbCP, after == lbEX, after == lbIS, after == lbSY: // LB13
+		return false
+	case before == lbOP: // LB14
+		return false
+	case before == lbQU && after == lbOP: // LB15
+		return false
+	case (before == lbCL || before == lbCP) && after == lbNS: // LB16
+		return false
+	case before == lbB2 && after == lbB2: // LB17
+		return false
+	case spaces: // LB18
+		return true
+	}
+
+	switch {
+	case before == lbWJ, before == lbGL: // LB11, LB12
+		return false
+	case after == lbGL && before != lbBA && before != lbHY: // LB12a
+		return false
+	case before == lbQU, after == lbQU: // LB19
+		return false
+	case after == lbBA, after == lbHY, after == lbNS: // LB21
+		return false
+	case after == lbIN && (before == lbAL || before == lbID || before == lbIN || before == lbNU): // LB22
+		return false
+	case before == lbAL && after == lbNU, before == lbNU && after == lbAL, // LB23
+		before == lbID && after == lbPO:
+		return false
+	case before == lbPR && (after == lbID || after == lbAL), // LB24
+		before == lbPO && after == lbAL,
+		before == lbAL && (after == lbPR || after == lbPO):
+		return false
+	case (before == lbCL || before == lbCP || before == lbNU) && (after == lbPO || after == lbPR), // LB25
+		(before == lbPO || before == lbPR) && (after == lbOP || after == lbNU),
+		(before == lbHY || before == lbIS || before == lbNU || before == lbSY) && after == lbNU:
+		return false
+	case before == lbAL && after == lbAL: // LB28
+		return false
+	case before == lbIS && after == lbAL: // LB29
+		return false
+	case (before == lbAL || before == lbNU) && after == lbOP, // LB30
+		before == lbCP && (after == lbAL || after == lbNU):
+		return false
+	}
+
+	// Break everywhere else (LB31).
+	return true
+}
@@ -43,8 +43,29 @@ type PoConfig struct {
 	ManageHeader    bool
 	HeaderComments  bool
 	HeaderFields    bool
-	WordWrap        bool
-	HeaderConfig    *po.HeaderConfig
+	// WordWrap writes the strings like GNU gettext does: strings that span several
+	// lines start with an empty one, and lines end after each newline and at the
+	// last word boundary that fits in Width. Otherwise, each string is written in
+	// a single line.
+	WordWrap bool
+	// Width is the page width used by WordWrap and to pack the references.
+	// Zero means DefaultWidth. If negative, long lines aren't wrapped, but
+	// strings are still split after their newlines (like GNU --no-wrap).
+	Width        int
+	HeaderConfig *po.HeaderConfig
+	// PreserveSyntax writes the entries that weren't modified since they were
+	// parsed exactly as they were read, if the file kept its [po.Syntax].
+	// The header is not managed in this mode.
+	PreserveSyntax bool
+	// Charset of the output, the one declared in the header is used if empty.
+	// If set, the charset of the header is replaced.
+	Charset string
+	// StampRevision updates the PO-Revision-Date of the header if the entries
+	// changed since the file was parsed, which is only known if it kept its
+	// [po.Syntax]; otherwise the entries are always considered changed.
+	StampRevision bool
+	// LastTranslator replaces the Last-Translator field when the revision is stamped.
+	LastTranslator string
 
 	UseCustomObsoletePrefix  bool
 	CustomObsoletePrefixRune rune
@@ -129,12 +150,42 @@ func PoWithManageHeader(b bool) PoOption {
 	}
 }
 
+func PoWithCharset(charset string) PoOption {
+	return func(pc *PoConfig) {
+		pc.Charset = charset
+	}
+}
+
+func PoWithPreserveSyntax(p bool) PoOption {
+	return func(pc *PoConfig) {
+		pc.PreserveSyntax = p
+	}
+}
+
+func PoWithStampRevision(s bool) PoOption {
+	return func(pc *PoConfig) {
+		pc.StampRevision = s
+	}
+}
+
+func PoWithLastTranslator(t string) PoOption {
+	return func(pc *PoConfig) {
+		pc.LastTranslator = t
+	}
+}
+
 func PoWithWordWrap(w bool) PoOption {
 	return func(pc *PoConfig) {
 		pc.WordWrap = w
 	}
 }
 
+func PoWithWidth(w int) PoOption {
+	return func(pc *PoConfig) {
+		pc.Width = w
+	}
+}
+
 func PoWithHeaderFields(w bool) PoOption {
 	return func(pc *PoConfig) {
 		pc.HeaderFields = w
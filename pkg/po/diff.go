@@ -0,0 +1,262 @@
+package po
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Tom5521/gotext-tools/v2/internal/slices"
+)
+
+// DiffChange is a set of the differences found between two versions of an entry.
+type DiffChange uint
+
+const (
+	// DiffAdded means that the entry only exists in the new catalog.
+	DiffAdded DiffChange = 1 << iota
+	// DiffRemoved means that the entry only exists in the old catalog.
+	DiffRemoved
+	// DiffTranslation means that msgstr or any msgstr[i] changed.
+	DiffTranslation
+	// DiffPlural means that msgid_plural changed.
+	DiffPlural
+	// DiffFlags means that the set of flags changed, their order is ignored.
+	DiffFlags
+	// DiffComments means that the translator, extracted or previous ("#|") comments changed.
+	DiffComments
+	// DiffLocations means that the "#:" references changed.
+	DiffLocations
+	// DiffObsolete means that the entry became obsolete or was restored.
+	DiffObsolete
+)
+
+var diffChangeNames = []struct {
+	change DiffChange
+	name   string
+}{
+	{DiffAdded, "added"},
+	{DiffRemoved, "removed"},
+	{DiffTranslation, "translation"},
+	{DiffPlural, "plural"},
+	{DiffFlags, "flags"},
+	{DiffComments, "comments"},
+	{DiffLocations, "locations"},
+	{DiffObsolete, "obsolete"},
+}
+
+// Has reports whether c includes any of the changes of x.
+func (c DiffChange) Has(x DiffChange) bool {
+	return c&x != 0
+}
+
+// Names returns the names of the changes of c, like "translation" or "flags".
+func (c DiffChange) Names() []string {
+	names := []string{}
+	for _, n := range diffChangeNames {
+		if c.Has(n.change) {
+			names = append(names, n.name)
+		}
+	}
+	return names
+}
+
+func (c DiffChange) String() string {
+	if c == 0 {
+		return "none"
+	}
+	return strings.Join(c.Names(), ", ")
+}
+
+func diffChangeByName(name string) (DiffChange, bool) {
+	for _, n := range diffChangeNames {
+		if n.name == name {
+			return n.change, true
+		}
+	}
+	return 0, false
+}
+
+// MarshalJSON encodes the changes as a list of their names.
+func (c DiffChange) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Names())
+}
+
+// UnmarshalJSON decodes a list of change names.
+func (c *DiffChange) UnmarshalJSON(data []byte) error {
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return err
+	}
+
+	*c = 0
+	for _, name := range names {
+		change, ok := diffChangeByName(name)
+		if !ok {
+			return fmt.Errorf("unknown diff change %q", name)
+		}
+		*c |= change
+	}
+
+	return nil
+}
+
+// EntryDiff describes the differences between two versions of an entry.
+// Old is nil for added entries and New is nil for removed ones.
+type EntryDiff struct {
+	Old     *Entry
+	New     *Entry
+	Changes DiffChange
+}
+
+type jsonEntryDiff struct {
+	Old     *jsonEntry `json:"old,omitempty"`
+	New     *jsonEntry `json:"new,omitempty"`
+	Changes DiffChange `json:"changes"`
+}
+
+// MarshalJSON encodes the diff with the entries in lower case fields,
+// like "id" and "str", and the changes as a list of their names.
+func (d EntryDiff) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonEntryDiff{Old: newJSONEntry(d.Old), New: newJSONEntry(d.New), Changes: d.Changes})
+}
+
+// UnmarshalJSON decodes a diff encoded by [EntryDiff.MarshalJSON].
+func (d *EntryDiff) UnmarshalJSON(data []byte) error {
+	var j jsonEntryDiff
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*d = EntryDiff{Old: j.Old.entry(), New: j.New.entry(), Changes: j.Changes}
+	return nil
+}
+
+// Entry returns the new version of the entry, or the old one if it was removed.
+func (d EntryDiff) Entry() Entry {
+	if d.New != nil {
+		return *d.New
+	}
+	return *d.Old
+}
+
+// IsLocationOnly reports whether only the references of the entry changed.
+func (d EntryDiff) IsLocationOnly() bool {
+	return d.Changes == DiffLocations
+}
+
+// Diff is the list of the entries that differ between two catalogs.
+type Diff []EntryDiff
+
+// DiffEntries pairs the entries of old and new by [Entry.UnifiedID] and returns
+// the ones that differ. Changed and added entries come first, in the order of new,
+// followed by the removed entries in the order of old.
+// When an ID is repeated, only its first entry is compared.
+func DiffEntries(old, new Entries) Diff {
+	oldIndex := make(map[string]int, len(old))
+	for i, e := range old {
+		if _, ok := oldIndex[e.UnifiedID()]; !ok {
+			oldIndex[e.UnifiedID()] = i
+		}
+	}
+
+	var diff Diff
+	seen := make(map[string]bool, len(new))
+	for i := range new {
+		uid := new[i].UnifiedID()
+		if seen[uid] {
+			continue
+		}
+		seen[uid] = true
+
+		j, ok := oldIndex[uid]
+		if !ok {
+			diff = append(diff, EntryDiff{New: &new[i], Changes: DiffAdded})
+			continue
+		}
+		if changes := diffEntry(old[j], new[i]); changes != 0 {
+			diff = append(diff, EntryDiff{Old: &old[j], New: &new[i], Changes: changes})
+		}
+	}
+
+	for i := range old {
+		uid := old[i].UnifiedID()
+		if oldIndex[uid] == i && !seen[uid] {
+			diff = append(diff, EntryDiff{Old: &old[i], Changes: DiffRemoved})
+		}
+	}
+
+	return diff
+}
+
+// DiffFiles returns the differences between the entries of old and new.
+// See [DiffEntries].
+func DiffFiles(old, new *File) Diff {
+	return DiffEntries(old.Entries, new.Entries)
+}
+
+func diffEntry(old, new Entry) DiffChange {
+	var changes DiffChange
+	if old.Str != new.Str || !slices.Equal(old.Plurals, new.Plurals) {
+		changes |= DiffTranslation
+	}
+	if old.Plural != new.Plural {
+		changes |= DiffPlural
+	}
+	if !sameFlags(old.Flags, new.Flags) {
+		changes |= DiffFlags
+	}
+	if !slices.Equal(old.Comments, new.Comments) ||
+		!slices.Equal(old.ExtractedComments, new.ExtractedComments) ||
+		old.Previous != new.Previous {
+		changes |= DiffComments
+	}
+	if !old.Locations.Equal(new.Locations) {
+		changes |= DiffLocations
+	}
+	if old.Obsolete != new.Obsolete {
+		changes |= DiffObsolete
+	}
+
+	return changes
+}
+
+func sameFlags(a, b []string) bool {
+	a, b = NormalizeFlags(a), NormalizeFlags(b)
+	if len(a) != len(b) {
+		return false
+	}
+	for _, f := range a {
+		if !slices.Contains(b, f) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the entries with any of the given changes.
+func (d Diff) Filter(changes DiffChange) Diff {
+	var filtered Diff
+	for _, ed := range d {
+		if ed.Changes.Has(changes) {
+			filtered = append(filtered, ed)
+		}
+	}
+	return filtered
+}
+
+// IgnoreLocations returns the diff without the entries whose only change
+// are their references.
+func (d Diff) IgnoreLocations() Diff {
+	var filtered Diff
+	for _, ed := range d {
+		if !ed.IsLocationOnly() {
+			filtered = append(filtered, ed)
+		}
+	}
+	return filtered
+}
+
+// Count returns the number of entries with any of the given changes.
+func (d Diff) Count(changes DiffChange) int {
+	return len(d.Filter(changes))
+}
@@ -0,0 +1,130 @@
+package po_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+)
+
+func TestDiffEntries(t *testing.T) {
+	old := po.Entries{
+		{ID: "Open", Str: "Abrir", Locations: po.Locations{{File: "a.go", Line: 1}}},
+		{ID: "Save", Str: "Guardar", Flags: []string{"fuzzy", "go-format"}},
+		{ID: "Quit", Str: "Salir", Comments: []string{"old"}},
+		{ID: "Removed", Str: "Eliminado"},
+		{ID: "Same", Str: "Igual"},
+		{
+			ID:      "%d file",
+			Plural:  "%d files",
+			Plurals: po.PluralEntries{{ID: 0, Str: "%d archivo"}, {ID: 1, Str: "%d archivos"}},
+		},
+	}
+	new := po.Entries{
+		{ID: "Added", Str: "Añadido"},
+		{ID: "Open", Str: "Abrir", Locations: po.Locations{{File: "a.go", Line: 12}}},
+		{ID: "Save", Str: "Guardar", Flags: []string{"go-format", "fuzzy"}},
+		{ID: "Quit", Str: "Cerrar", Comments: []string{"new"}},
+		{ID: "Same", Str: "Igual"},
+		{
+			ID:      "%d file",
+			Plural:  "%d files",
+			Plurals: po.PluralEntries{{ID: 0, Str: "%d archivo"}, {ID: 1, Str: "%d ficheros"}},
+		},
+	}
+
+	diff := po.DiffEntries(old, new)
+	expected := []struct {
+		id      string
+		changes po.DiffChange
+	}{
+		{"Added", po.DiffAdded},
+		{"Open", po.DiffLocations},
+		{"Quit", po.DiffTranslation | po.DiffComments},
+		{"%d file", po.DiffTranslation},
+		{"Removed", po.DiffRemoved},
+	}
+	if len(diff) != len(expected) {
+		t.Fatalf("expected %d differences, got %d: %v", len(expected), len(diff), diff)
+	}
+	for i, e := range expected {
+		if diff[i].Entry().ID != e.id || diff[i].Changes != e.changes {
+			t.Errorf("%d: expected %q (%s), got %q (%s)",
+				i, e.id, e.changes, diff[i].Entry().ID, diff[i].Changes)
+		}
+	}
+
+	if !diff[1].IsLocationOnly() {
+		t.Error("expected a location only change")
+	}
+	if n := len(diff.IgnoreLocations()); n != 4 {
+		t.Errorf("expected 4 differences ignoring locations, got %d", n)
+	}
+	if n := diff.Count(po.DiffTranslation); n != 2 {
+		t.Errorf("expected 2 translation changes, got %d", n)
+	}
+	if diff[0].Old != nil || diff[4].New != nil {
+		t.Error("added entries must have no old version and removed ones no new version")
+	}
+}
+
+func TestDiffChangeJSON(t *testing.T) {
+	change := po.DiffTranslation | po.DiffFlags
+	data, err := json.Marshal(change)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `["translation","flags"]` {
+		t.Errorf("unexpected JSON %s", data)
+	}
+
+	var decoded po.DiffChange
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != change {
+		t.Errorf("expected %s, got %s", change, decoded)
+	}
+	if err = json.Unmarshal([]byte(`["moved"]`), &decoded); err == nil {
+		t.Error("expected an error for an unknown change")
+	}
+}
+
+func TestEntryDiffJSON(t *testing.T) {
+	diff := po.DiffEntries(
+		po.Entries{{ID: "Open", Str: "Abrir", Locations: po.Locations{{Line: 1, File: "main.go"}}}},
+		po.Entries{{ID: "Open", Str: "Abre", Previous: po.PreviousMessage{ID: "Opens"}}},
+	)
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{
+		`"str":"Abrir"`,
+		`"locations":[{"line":1,"file":"main.go"}]`,
+		`"previous":{"id":"Opens"}`,
+	} {
+		if !strings.Contains(string(data), field) {
+			t.Errorf("field %s not found in %s", field, data)
+		}
+	}
+	if strings.Count(string(data), `"previous"`) != 1 {
+		t.Errorf("empty previous messages must be omitted: %s", data)
+	}
+
+	var decoded po.Diff
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(diff, decoded) {
+		t.Errorf("expected %+v, decoded %+v", diff, decoded)
+	}
+
+	// The entries themselves keep the default encoding.
+	if data, _ = json.Marshal(po.Entry{ID: "Open"}); !strings.Contains(string(data), `"ID":"Open"`) {
+		t.Errorf("unexpected encoding of an entry: %s", data)
+	}
+}
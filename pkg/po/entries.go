@@ -92,12 +92,12 @@ func (e Entries) SortFunc(cmp Cmp[Entry]) Entries {
 func (e Entries) CatchDuplicateEntries() []error {
 	var errs []error
 
-	seen := make(map[string]int)
+	seen := make(EntryIndex, len(e))
 
 	for index, entry := range e {
 		uid := entry.UnifiedID()
-		orgIndex, ok := seen[uid]
-		if ok {
+		if !seen.add(uid, index) {
+			orgIndex := seen[uid]
 			errs = append(errs,
 				&InvalidEntryAtIndexError{
 					Reason: &InvalidEntryError{
@@ -109,9 +109,7 @@ func (e Entries) CatchDuplicateEntries() []error {
 					Index: index,
 				},
 			)
-			continue
 		}
-		seen[uid] = index
 	}
 
 	return errs
@@ -156,8 +154,9 @@ type MergeFunc func(a, b Entry) *Entry
 // If a matching UnifiedID is found in priorList, it merges that entry with 'b'.
 // Otherwise, it merges 'a' and 'b' as usual using SolveMerge.
 func MergeUsingPriorAsBase(priorList Entries) MergeFunc {
+	index := priorList.Indexed()
 	return func(a, b Entry) *Entry {
-		if i := priorList.IndexByUnifiedID(a.UnifiedID()); i != -1 {
+		if i := index.IndexByUnifiedID(a.UnifiedID()); i != -1 {
 			return SolveMerge(priorList[i], b)
 		}
 		return SolveMerge(a, b)
@@ -168,9 +167,10 @@ func MergeUsingPriorAsBase(priorList Entries) MergeFunc {
 // If the UnifiedID of the entry is not found in the priorList, the resulting merged entry is marked as obsolete.
 // This is useful for keeping non-prioritized entries while flagging them as deprecated or no longer in use.
 func MergeAndMarkObsoleteIfNotPrioritized(priorList []string) MergeFunc {
+	prior := stringSet(priorList)
 	return func(a, b Entry) *Entry {
 		n := SolveMerge(a, b)
-		if !slices.Contains(priorList, a.UnifiedID()) {
+		if _, ok := prior[a.UnifiedID()]; !ok {
 			n.Obsolete = true
 		}
 		return n
@@ -181,8 +181,9 @@ func MergeAndMarkObsoleteIfNotPrioritized(priorList []string) MergeFunc {
 // is present in the provided list of priority IDs (priorList).
 // If the UnifiedID is not found in the list, the merge is skipped and nil is returned.
 func MergeIfInPriorityList(priorList []string) MergeFunc {
+	prior := stringSet(priorList)
 	return func(a, b Entry) *Entry {
-		if slices.Contains(priorList, a.UnifiedID()) {
+		if _, ok := prior[a.UnifiedID()]; ok {
 			return SolveMerge(a, b)
 		}
 		return nil
@@ -192,14 +193,23 @@ func MergeIfInPriorityList(priorList []string) MergeFunc {
 // MergeUsingPriorityOrFallback returns a MergeFunc that prioritizes the version from priorList if found,
 // otherwise it falls back to SolveMerge.
 func MergeUsingPriorityOrFallback(priorList Entries) MergeFunc {
+	index := priorList.Indexed()
 	return func(a, b Entry) *Entry {
-		if i := priorList.IndexByUnifiedID(a.UnifiedID()); i != -1 {
+		if i := index.IndexByUnifiedID(a.UnifiedID()); i != -1 {
 			return &priorList[i]
 		}
 		return SolveMerge(a, b)
 	}
 }
 
+func stringSet(list []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(list))
+	for _, s := range list {
+		set[s] = struct{}{}
+	}
+	return set
+}
+
 // SolveMerge merges two Entry objects based on certain preference criteria.
 // It prefers the Entry with a higher priority according to CompareEntry.
 // It combines the Locations from both entries.
@@ -14,10 +14,10 @@ import (
 type Entry struct {
 	// Metadata and comments.
 
-	Flags             []string // List of flags (e.g., "fuzzy").
-	Comments          []string // Translator comments.
-	ExtractedComments []string // Automatically extracted comments.
-	Previous          []string // Previous msgid or msgstr lines.
+	Flags             []string        // List of flags (e.g., "fuzzy"), one per element.
+	Comments          []string        // Translator comments.
+	ExtractedComments []string        // Automatically extracted comments.
+	Previous          PreviousMessage // Previous msgctxt, msgid and msgid_plural ("#|" comments).
 
 	// Main fields.
 
@@ -30,14 +30,25 @@ type Entry struct {
 	Locations Locations     // List of source code references.
 }
 
+// PreviousMessage holds the msgctxt, msgid and msgid_plural that an entry
+// had before a merge matched it with a different message and marked it as fuzzy.
+type PreviousMessage struct {
+	Context string
+	ID      string
+	Plural  string
+}
+
+// IsZero reports whether p holds no previous message.
+func (p PreviousMessage) IsZero() bool {
+	return p == PreviousMessage{}
+}
+
 // markAsObsolete marks the entry as obsolete.
 func (e *Entry) markAsObsolete() { e.Obsolete = true }
 
 // markAsFuzzy adds the "fuzzy" flag to the entry if not already present.
 func (e *Entry) markAsFuzzy() {
-	if !e.IsFuzzy() {
-		e.Flags = append(e.Flags, "fuzzy")
-	}
+	e.AddFlag(FlagFuzzy)
 }
 
 // IsHeader reports whether the entry is a header (i.e., both ID and context are empty).
@@ -157,12 +168,12 @@ func (e Entry) HasContext() bool {
 
 // IsFuzzy reports whether the entry is marked as "fuzzy".
 func (e Entry) IsFuzzy() bool {
-	return slices.Contains(e.Flags, "fuzzy")
+	return e.HasFlag(FlagFuzzy)
 }
 
 func (e Entry) HasComments() bool {
 	return len(e.Flags) > 0 || len(e.Comments) > 0 ||
-		len(e.ExtractedComments) > 0 || len(e.Previous) > 0 ||
+		len(e.ExtractedComments) > 0 || !e.Previous.IsZero() ||
 		len(e.Locations) > 0
 }
 
@@ -3,9 +3,14 @@ package po
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
-var ErrBadPluralEntry = errors.New("the entry can't be plural and singular at the same time")
+var (
+	ErrBadPluralEntry = errors.New("the entry can't be plural and singular at the same time")
+	ErrEmptyPluralID  = errors.New("msgid_plural is empty")
+	ErrMissingHeader  = errors.New("the header entry is missing")
+)
 
 type DuplicatedEntryError struct {
 	OriginalIndex int
@@ -53,3 +58,290 @@ func (e *InvalidFileError) Error() string {
 func (e *InvalidFileError) Unwrap() error {
 	return e.Reason
 }
+
+type PluralFormsError struct {
+	Expr   string
+	Column int
+	Reason string
+}
+
+func (e *PluralFormsError) Error() string {
+	return fmt.Sprintf("plural expression %q is invalid at column %d: %s", e.Expr, e.Column, e.Reason)
+}
+
+// FormatErrorReason describes why the directives of a translation
+// don't match the ones of its source string.
+type FormatErrorReason int
+
+const (
+	// FormatMissingDirective means that an argument of the source is not used by the translation.
+	FormatMissingDirective FormatErrorReason = iota
+	// FormatExtraDirective means that the translation uses an argument that the source doesn't.
+	FormatExtraDirective
+	// FormatTypeMismatch means that an argument is used with incompatible types.
+	FormatTypeMismatch
+	// FormatReorderedWithoutIndex means that the translation changes the order
+	// of the directives without selecting the arguments explicitly.
+	FormatReorderedWithoutIndex
+	// FormatInvalidSource means that the directives of the source can't be parsed.
+	FormatInvalidSource
+	// FormatInvalidTranslation means that the directives of the translation can't be parsed.
+	FormatInvalidTranslation
+)
+
+func (r FormatErrorReason) String() string {
+	switch r {
+	case FormatMissingDirective:
+		return "missing directive"
+	case FormatExtraDirective:
+		return "extra directive"
+	case FormatTypeMismatch:
+		return "type mismatch"
+	case FormatReorderedWithoutIndex:
+		return "directives reordered without argument index"
+	case FormatInvalidSource:
+		return "invalid directives in msgid"
+	case FormatInvalidTranslation:
+		return "invalid directives in msgstr"
+	}
+	return fmt.Sprintf("FormatErrorReason(%d)", int(r))
+}
+
+type FormatError struct {
+	Kind   FormatKind
+	Reason FormatErrorReason
+	// Plural is the index of the checked plural form, or -1 for msgstr.
+	Plural int
+	// Arg is the number or the name of the argument, if any.
+	Arg      string
+	Expected string
+	Got      string
+}
+
+func (e *FormatError) Error() string {
+	msgstr := "msgstr"
+	if e.Plural >= 0 {
+		msgstr = fmt.Sprintf("msgstr[%d]", e.Plural)
+	}
+
+	switch e.Reason {
+	case FormatMissingDirective:
+		return fmt.Sprintf("%s: argument %s (%s) is not used in %s", e.Kind.Flag(), e.Arg, e.Expected, msgstr)
+	case FormatExtraDirective:
+		return fmt.Sprintf("%s: argument %s (%s) in %s doesn't exist in msgid", e.Kind.Flag(), e.Arg, e.Got, msgstr)
+	case FormatTypeMismatch:
+		return fmt.Sprintf("%s: argument %s is %s in msgid but %s in %s",
+			e.Kind.Flag(), e.Arg, e.Expected, e.Got, msgstr)
+	case FormatReorderedWithoutIndex:
+		return fmt.Sprintf("%s: %s changes the order of the directives (%s) to (%s) without argument indexes",
+			e.Kind.Flag(), msgstr, e.Expected, e.Got)
+	case FormatInvalidSource:
+		return fmt.Sprintf("%s: invalid directives in msgid: %s", e.Kind.Flag(), e.Expected)
+	}
+	return fmt.Sprintf("%s: invalid directives in %s: %s", e.Kind.Flag(), msgstr, e.Got)
+}
+
+// PluralCountError means that a plural entry doesn't have as many
+// translations as plural forms are declared in the header.
+type PluralCountError struct {
+	Expected int
+	Got      int
+}
+
+func (e *PluralCountError) Error() string {
+	return fmt.Sprintf("nplurals = %d but found %d plural forms", e.Expected, e.Got)
+}
+
+// PluralIndexError means that a msgstr[i] of a plural entry is missing
+// or is out of the range of the plural forms declared in the header.
+type PluralIndexError struct {
+	Index    int
+	Nplurals int
+	Missing  bool
+}
+
+func (e *PluralIndexError) Error() string {
+	if e.Missing {
+		return fmt.Sprintf("msgstr[%d] is missing", e.Index)
+	}
+	return fmt.Sprintf("msgstr[%d] is out of range, nplurals = %d", e.Index, e.Nplurals)
+}
+
+// NewlineError means that a string begins or ends with a newline
+// but the one it's checked against doesn't, or the other way around.
+type NewlineError struct {
+	// Source and Field are the keywords of the compared strings ("msgid", "msgstr[1]").
+	Source   string
+	Field    string
+	Trailing bool
+}
+
+func (e *NewlineError) Error() string {
+	position := "begin"
+	if e.Trailing {
+		position = "end"
+	}
+	return fmt.Sprintf("%s and %s entries do not both %s with '\\n'", e.Source, e.Field, position)
+}
+
+// InvalidIDCharError means that a msgctxt, msgid or msgid_plural contains
+// a character used as separator in MO files.
+type InvalidIDCharError struct {
+	// Field is the keyword of the string with the character ("msgid").
+	Field  string
+	Char   rune
+	Offset int // Byte offset of the character in the string.
+}
+
+func (e *InvalidIDCharError) Error() string {
+	return fmt.Sprintf("%s contains the character %q at byte %d", e.Field, e.Char, e.Offset)
+}
+
+// AcceleratorError means that a translation doesn't have exactly one keyboard
+// accelerator mark, like "&" in "&Open", while its msgid has one.
+type AcceleratorError struct {
+	Mark rune
+	// Field is the keyword of the translation ("msgstr", "msgstr[1]").
+	Field string
+	// Count is the number of marks found in the translation.
+	Count int
+}
+
+func (e *AcceleratorError) Error() string {
+	if e.Count == 0 {
+		return fmt.Sprintf("%s lacks the keyboard accelerator mark '%c'", e.Field, e.Mark)
+	}
+	return fmt.Sprintf("%s has too many keyboard accelerator marks '%c'", e.Field, e.Mark)
+}
+
+// HeaderFieldErrorReason describes the problem with a field of the header.
+type HeaderFieldErrorReason int
+
+const (
+	// HeaderFieldMissing means that a required field is not present.
+	HeaderFieldMissing HeaderFieldErrorReason = iota
+	// HeaderFieldDefault means that the field still has the initial value of templates.
+	HeaderFieldDefault
+	// HeaderFieldInvalid means that the value of the field can't be parsed or isn't supported.
+	HeaderFieldInvalid
+)
+
+func (r HeaderFieldErrorReason) String() string {
+	switch r {
+	case HeaderFieldMissing:
+		return "missing"
+	case HeaderFieldDefault:
+		return "default value"
+	case HeaderFieldInvalid:
+		return "invalid value"
+	}
+	return fmt.Sprintf("HeaderFieldErrorReason(%d)", int(r))
+}
+
+type HeaderFieldError struct {
+	Field  string
+	Reason HeaderFieldErrorReason
+	// Err is the cause of HeaderFieldInvalid errors.
+	Err error
+}
+
+func (e *HeaderFieldError) Error() string {
+	switch e.Reason {
+	case HeaderFieldMissing:
+		return fmt.Sprintf("header field %q is missing", e.Field)
+	case HeaderFieldDefault:
+		return fmt.Sprintf("header field %q still has the initial default value", e.Field)
+	}
+	return fmt.Sprintf("header field %q is invalid: %v", e.Field, e.Err)
+}
+
+func (e *HeaderFieldError) Unwrap() error {
+	return e.Err
+}
+
+// InvalidDomainError means that a domain name can't be used as the name of a file.
+type InvalidDomainError struct {
+	Domain string
+	Char   rune
+}
+
+func (e *InvalidDomainError) Error() string {
+	if e.Domain == "" {
+		return "the domain name is empty"
+	}
+	return fmt.Sprintf("domain name %q is not suitable as file name: character %q", e.Domain, e.Char)
+}
+
+type UnsupportedCharsetError struct {
+	Charset string
+}
+
+func (e *UnsupportedCharsetError) Error() string {
+	return fmt.Sprintf("charset %q is not supported", e.Charset)
+}
+
+type UnrepresentableCharError struct {
+	Charset string
+	// Field is the keyword of the string with the character ("msgid", "msgstr[1]").
+	Field  string
+	Char   rune
+	Offset int // Byte offset of the character in the string.
+}
+
+func (e *UnrepresentableCharError) Error() string {
+	return fmt.Sprintf("character %q (U+%04X) at byte %d of %s can't be represented in %s",
+		e.Char, e.Char, e.Offset, e.Field, e.Charset)
+}
+
+// Severity tells whether a [ParseError] prevents the file from being read correctly.
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// ParseError is a problem found while parsing a file.
+// Line and Column start at 1, and are 0 if the position is unknown.
+type ParseError struct {
+	Filename string
+	Line     int
+	Column   int
+	// Token is the offending token, if any.
+	Token    string
+	Severity Severity
+	Reason   error
+}
+
+func (e *ParseError) Error() string {
+	var b strings.Builder
+	if e.Filename != "" {
+		b.WriteString(e.Filename + ":")
+	}
+	if e.Line > 0 {
+		fmt.Fprintf(&b, "%d:", e.Line)
+		if e.Column > 0 {
+			fmt.Fprintf(&b, "%d:", e.Column)
+		}
+	}
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	fmt.Fprintf(&b, "%s: %v", e.Severity, e.Reason)
+
+	return b.String()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Reason
+}
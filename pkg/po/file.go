@@ -5,14 +5,25 @@ import (
 )
 
 // File represents a PO file with a name and a list of entries.
+//
+// File keeps an index of its entries by unified ID to speed up [File.Set]
+// and [File.Load]. The index is rebuilt when Entries is reassigned or its
+// length changes; if the ID or context of entries are modified in place,
+// [File.Reindex] must be called before the next lookup.
 type File struct {
 	Name    string // File name or path.
 	Entries        // List of translation entries.
+
+	// Syntax is the original text of the entries, if the parser was asked
+	// to keep it. It lets the compiler write back unmodified entries as they were.
+	Syntax *Syntax
+
+	index *fileIndex // Lookup index used by Set and Load.
 }
 
 // NewFile returns a new *File with the given name and entries.
 func NewFile(name string, entries ...Entry) *File {
-	return &File{name, entries}
+	return &File{Name: name, Entries: entries}
 }
 
 // Equal reports whether f and f2 contain the same entries and metadata.
@@ -23,18 +34,49 @@ func (f File) Equal(f2 File) bool {
 // Set sets the entry with the given id and context to e.
 // If the entry exists, it is replaced; otherwise, it is appended.
 func (f *File) Set(id, context string, e Entry) {
-	index := f.Index(id, context)
+	uid := Entry{ID: id, Context: context}.UnifiedID()
+	if !f.index.valid(f.Entries) {
+		f.index = newFileIndex(f.Entries)
+	}
+	index, ok := f.index.lookup(f.Entries, uid)
+	if !ok {
+		f.index = newFileIndex(f.Entries)
+		index, _ = f.index.lookup(f.Entries, uid)
+	}
+
 	if index == -1 {
 		f.Entries = append(f.Entries, e)
+		f.index.first = &f.Entries[0]
+		f.index.len = len(f.Entries)
+		f.index.ids.add(e.UnifiedID(), len(f.Entries)-1)
 		return
 	}
 	f.Entries[index] = e
+	if e.UnifiedID() != uid {
+		f.index = nil
+	}
+}
+
+// Reindex rebuilds the lookup index used by [File.Set] and [File.Load].
+func (f *File) Reindex() {
+	f.index = newFileIndex(f.Entries)
+}
+
+// indexByUnifiedID uses the cached index if it's still valid,
+// otherwise it falls back to a linear search.
+func (f File) indexByUnifiedID(uid string) int {
+	if f.index.valid(f.Entries) {
+		if i, ok := f.index.lookup(f.Entries, uid); ok {
+			return i
+		}
+	}
+	return f.IndexByUnifiedID(uid)
 }
 
 // LoadByUnifiedID returns the translation string for the entry with the given unified ID.
 // If no such entry exists, it returns an empty string.
 func (f File) LoadByUnifiedID(uid string) string {
-	i := f.IndexByUnifiedID(uid)
+	i := f.indexByUnifiedID(uid)
 	if i == -1 {
 		return ""
 	}
@@ -44,11 +86,7 @@ func (f File) LoadByUnifiedID(uid string) string {
 // Load returns the translation string for the entry with the given ID and context.
 // If no such entry exists, it returns an empty string.
 func (f File) Load(id string, context string) string {
-	i := f.Index(id, context)
-	if i == -1 {
-		return ""
-	}
-	return f.Entries[i].Str
+	return f.LoadByUnifiedID(Entry{ID: id, Context: context}.UnifiedID())
 }
 
 func (f File) CatchDuplicateEntries() []error {
@@ -0,0 +1,205 @@
+package po
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Well known flags.
+const (
+	FlagFuzzy  = "fuzzy"
+	FlagWrap   = "wrap"
+	FlagNoWrap = "no-wrap"
+)
+
+const rangeFlagPrefix = "range:"
+
+// ParseFlags splits the content of a "#," comment into its flags.
+// Empty and duplicated flags are dropped.
+func ParseFlags(line string) []string {
+	return NormalizeFlags([]string{line})
+}
+
+// NormalizeFlags splits comma separated flags, trims them and removes
+// empty and duplicated ones, keeping the order of the first appearance.
+func NormalizeFlags(flags []string) []string {
+	var normalized []string
+	seen := make(map[string]bool, len(flags))
+	for _, f := range flags {
+		for _, part := range strings.Split(f, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" || seen[part] {
+				continue
+			}
+			seen[part] = true
+			normalized = append(normalized, part)
+		}
+	}
+
+	return normalized
+}
+
+// flagOrder returns the group used to sort flags canonically:
+// fuzzy, format flags, range, wrapping and then anything else.
+func flagOrder(flag string) int {
+	switch {
+	case flag == FlagFuzzy:
+		return 0
+	case strings.HasSuffix(flag, "-format"):
+		return 1
+	case strings.HasPrefix(flag, rangeFlagPrefix):
+		return 2
+	case flag == FlagWrap || flag == FlagNoWrap:
+		return 3
+	}
+	return 4
+}
+
+// CanonicalFlags returns the normalized flags (see [NormalizeFlags]) in the
+// order used by GNU gettext: fuzzy first, then format flags, range and
+// wrapping flags. Other flags keep their relative order at the end.
+func CanonicalFlags(flags []string) []string {
+	normalized := NormalizeFlags(flags)
+	canonical := make([]string, 0, len(normalized))
+	for group := 0; group <= 4; group++ {
+		for _, f := range normalized {
+			if flagOrder(f) == group {
+				canonical = append(canonical, f)
+			}
+		}
+	}
+
+	return canonical
+}
+
+// HasFlag reports whether the entry has the given flag.
+func (e Entry) HasFlag(flag string) bool {
+	for _, f := range e.Flags {
+		if f == flag {
+			return true
+		}
+		// Flags that were not split, like the ones written by hand.
+		if strings.Contains(f, ",") {
+			for _, part := range strings.Split(f, ",") {
+				if strings.TrimSpace(part) == flag {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// AddFlag adds flag to the entry if it isn't already present.
+func (e *Entry) AddFlag(flag string) {
+	if !e.HasFlag(flag) {
+		e.Flags = append(e.Flags, flag)
+	}
+}
+
+// RemoveFlag removes all the occurrences of flag from the entry.
+func (e *Entry) RemoveFlag(flag string) {
+	if !e.HasFlag(flag) {
+		return
+	}
+
+	var flags []string
+	for _, f := range NormalizeFlags(e.Flags) {
+		if f != flag {
+			flags = append(flags, f)
+		}
+	}
+	e.Flags = flags
+}
+
+// IsNoWrap reports whether the entry has the "no-wrap" flag.
+func (e Entry) IsNoWrap() bool {
+	return e.HasFlag(FlagNoWrap)
+}
+
+// FormatFlags returns the format flags of the entry, mapping the format kind
+// to true for "<kind>-format" flags and to false for "no-<kind>-format" flags.
+// Unknown kinds are also reported.
+func (e Entry) FormatFlags() map[FormatKind]bool {
+	formats := make(map[FormatKind]bool)
+	for _, f := range NormalizeFlags(e.Flags) {
+		if !strings.HasSuffix(f, "-format") {
+			continue
+		}
+		kind := strings.TrimSuffix(f, "-format")
+		if strings.HasPrefix(kind, "no-") {
+			formats[FormatKind(strings.TrimPrefix(kind, "no-"))] = false
+			continue
+		}
+		if _, ok := formats[FormatKind(kind)]; !ok {
+			formats[FormatKind(kind)] = true
+		}
+	}
+
+	return formats
+}
+
+// FlagRange is the range of values of a "range: min..max" flag,
+// used for the number of plural messages.
+type FlagRange struct {
+	Min, Max int
+}
+
+func (r FlagRange) String() string {
+	return fmt.Sprintf("%s %d..%d", rangeFlagPrefix, r.Min, r.Max)
+}
+
+// ParseFlagRange parses a "range: min..max" flag.
+func ParseFlagRange(flag string) (FlagRange, error) {
+	if !strings.HasPrefix(flag, rangeFlagPrefix) {
+		return FlagRange{}, fmt.Errorf("%q is not a range flag", flag)
+	}
+
+	value := strings.TrimSpace(strings.TrimPrefix(flag, rangeFlagPrefix))
+	minStr, maxStr, ok := strings.Cut(value, "..")
+	if !ok {
+		return FlagRange{}, fmt.Errorf("invalid range %q", value)
+	}
+	minValue, err := strconv.Atoi(strings.TrimSpace(minStr))
+	if err != nil {
+		return FlagRange{}, fmt.Errorf("invalid range minimum: %w", err)
+	}
+	maxValue, err := strconv.Atoi(strings.TrimSpace(maxStr))
+	if err != nil {
+		return FlagRange{}, fmt.Errorf("invalid range maximum: %w", err)
+	}
+	if minValue > maxValue {
+		return FlagRange{}, fmt.Errorf("invalid range %d..%d", minValue, maxValue)
+	}
+
+	return FlagRange{Min: minValue, Max: maxValue}, nil
+}
+
+// Range returns the value of the "range:" flag of the entry.
+func (e Entry) Range() (FlagRange, bool) {
+	for _, f := range NormalizeFlags(e.Flags) {
+		if !strings.HasPrefix(f, rangeFlagPrefix) {
+			continue
+		}
+		r, err := ParseFlagRange(f)
+		if err != nil {
+			return FlagRange{}, false
+		}
+		return r, true
+	}
+
+	return FlagRange{}, false
+}
+
+// SetRange replaces the "range:" flag of the entry with r.
+func (e *Entry) SetRange(r FlagRange) {
+	var flags []string
+	for _, f := range NormalizeFlags(e.Flags) {
+		if !strings.HasPrefix(f, rangeFlagPrefix) {
+			flags = append(flags, f)
+		}
+	}
+	e.Flags = append(flags, r.String())
+}
@@ -0,0 +1,67 @@
+package po_test
+
+import (
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/v2/internal/util"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+)
+
+func TestCanonicalFlags(t *testing.T) {
+	got := po.CanonicalFlags([]string{"no-wrap, my-flag", "c-format,fuzzy", " fuzzy ", "range: 1..3", ""})
+	expected := []string{"fuzzy", "c-format", "range: 1..3", "no-wrap", "my-flag"}
+	if !util.Equal(got, expected) {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestEntryFlags(t *testing.T) {
+	entry := po.Entry{Flags: []string{"fuzzy, python-format", "no-c-format"}}
+
+	if !entry.HasFlag("python-format") || !entry.IsFuzzy() {
+		t.Error("flags joined with commas must be found")
+	}
+
+	formats := entry.FormatFlags()
+	if enabled, ok := formats[po.FormatPython]; !ok || !enabled {
+		t.Error("expected python-format to be enabled")
+	}
+	if enabled, ok := formats[po.FormatC]; !ok || enabled {
+		t.Error("expected c-format to be disabled")
+	}
+
+	entry.RemoveFlag(po.FlagFuzzy)
+	if entry.IsFuzzy() {
+		t.Error("fuzzy flag was not removed")
+	}
+	entry.AddFlag(po.FlagNoWrap)
+	entry.AddFlag(po.FlagNoWrap)
+	entry.SetRange(po.FlagRange{Min: 2, Max: 5})
+	entry.SetRange(po.FlagRange{Min: 0, Max: 5})
+
+	expected := []string{"python-format", "no-c-format", "no-wrap", "range: 0..5"}
+	if !util.Equal(entry.Flags, expected) {
+		t.Errorf("expected %q, got %q", expected, entry.Flags)
+	}
+}
+
+func TestParseFlagRange(t *testing.T) {
+	tests := []struct {
+		flag     string
+		expected po.FlagRange
+		fail     bool
+	}{
+		{"range: 0..10", po.FlagRange{Min: 0, Max: 10}, false},
+		{"range:1 .. 2", po.FlagRange{Min: 1, Max: 2}, false},
+		{"range: 5..1", po.FlagRange{}, true},
+		{"range: a..b", po.FlagRange{}, true},
+		{"fuzzy", po.FlagRange{}, true},
+	}
+
+	for _, test := range tests {
+		r, err := po.ParseFlagRange(test.flag)
+		if (err != nil) != test.fail || r != test.expected {
+			t.Errorf("%q: unexpected result %v (%v)", test.flag, r, err)
+		}
+	}
+}
@@ -0,0 +1,683 @@
+package po
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FormatKind identifies the language of the format directives of a message,
+// as declared by the "<kind>-format" flags.
+type FormatKind string
+
+const (
+	FormatC           FormatKind = "c"
+	FormatGo          FormatKind = "go"
+	FormatPython      FormatKind = "python"
+	FormatPythonBrace FormatKind = "python-brace"
+)
+
+// FormatKinds lists the format kinds that can be checked.
+var FormatKinds = []FormatKind{FormatC, FormatGo, FormatPython, FormatPythonBrace}
+
+// Flag returns the flag that marks a message as using this format ("c-format").
+func (k FormatKind) Flag() string {
+	return string(k) + "-format"
+}
+
+// NoFlag returns the flag that marks a message as not using this format ("no-c-format").
+func (k FormatKind) NoFlag() string {
+	return "no-" + k.Flag()
+}
+
+// formatArgs describes the arguments referenced by the directives of a string.
+type formatArgs struct {
+	// types maps each argument (its 1-based number or its name) to its type.
+	types map[string]string
+	// sequence lists the types of the directives in order of appearance,
+	// only when no directive selects its argument explicitly.
+	sequence []string
+	// indexed reports whether any directive selects its argument explicitly.
+	indexed bool
+}
+
+func newFormatArgs() *formatArgs {
+	return &formatArgs{types: make(map[string]string)}
+}
+
+func (a *formatArgs) set(arg, typ string) error {
+	if old, ok := a.types[arg]; ok && !formatTypesCompatible(old, typ) {
+		return fmt.Errorf("argument %s is used as both %q and %q", arg, old, typ)
+	}
+	a.types[arg] = typ
+	return nil
+}
+
+const formatTypeAny = "any"
+
+func formatTypesCompatible(a, b string) bool {
+	return a == b || a == formatTypeAny || b == formatTypeAny
+}
+
+// parseFormat parses the directives of s according to kind.
+func parseFormat(kind FormatKind, s string) (*formatArgs, error) {
+	switch kind {
+	case FormatC:
+		return parseCFormat(s)
+	case FormatGo:
+		return parseGoFormat(s)
+	case FormatPython:
+		return parsePythonFormat(s)
+	case FormatPythonBrace:
+		return parsePythonBraceFormat(s)
+	}
+	return nil, fmt.Errorf("unknown format %q", kind)
+}
+
+// readDigits returns the decimal number at the beginning of s and its length.
+func readDigits(s string) (int, int) {
+	var i int
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i == 0 {
+		return 0, 0
+	}
+	n, err := strconv.Atoi(s[:i])
+	if err != nil {
+		return 0, 0
+	}
+	return n, i
+}
+
+// readCArgNumber reads a "n$" argument number at the beginning of s.
+func readCArgNumber(s string) (int, int) {
+	n, size := readDigits(s)
+	if size == 0 || size >= len(s) || s[size] != '$' || n == 0 {
+		return 0, 0
+	}
+	return n, size + 1
+}
+
+var cLengthModifiers = []string{"hh", "ll", "h", "l", "L", "q", "j", "z", "Z", "t"}
+
+func cConversionType(length string, conv byte) (string, bool) {
+	var base string
+	switch conv {
+	case 'd', 'i':
+		base = "int"
+	case 'o', 'u', 'x', 'X':
+		base = "unsigned int"
+	case 'e', 'E', 'f', 'F', 'g', 'G', 'a', 'A':
+		base = "double"
+	case 'c':
+		base = "char"
+	case 'C':
+		return "wide char", true
+	case 's':
+		base = "string"
+	case 'S':
+		return "wide string", true
+	case 'p':
+		return "pointer", true
+	case 'n':
+		base = "count pointer"
+	default:
+		return "", false
+	}
+	if length == "" {
+		return base, true
+	}
+	return length + " " + base, true
+}
+
+func parseCFormat(s string) (*formatArgs, error) {
+	args := newFormatArgs()
+	var next int
+	var numbered, unnumbered bool
+
+	useArg := func(number int, typ string) error {
+		if number == 0 {
+			unnumbered = true
+			next++
+			number = next
+			args.sequence = append(args.sequence, typ)
+		} else {
+			numbered = true
+			args.indexed = true
+		}
+		if numbered && unnumbered {
+			return fmt.Errorf("numbered and unnumbered arguments are mixed")
+		}
+		return args.set(strconv.Itoa(number), typ)
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			continue
+		}
+		start := i
+		i++
+		if i < len(s) && s[i] == '%' {
+			continue
+		}
+
+		number, size := readCArgNumber(s[i:])
+		i += size
+		for i < len(s) && strings.IndexByte("-+ #0'I", s[i]) != -1 {
+			i++
+		}
+		// Width and precision.
+		for part := 0; part < 2; part++ {
+			if part == 1 {
+				if i >= len(s) || s[i] != '.' {
+					break
+				}
+				i++
+			}
+			if i < len(s) && s[i] == '*' {
+				i++
+				argNumber, size := readCArgNumber(s[i:])
+				i += size
+				if err := useArg(argNumber, "int"); err != nil {
+					return nil, fmt.Errorf("directive at %d: %w", start, err)
+				}
+				continue
+			}
+			_, size := readDigits(s[i:])
+			i += size
+		}
+		var length string
+		for _, l := range cLengthModifiers {
+			if strings.HasPrefix(s[i:], l) {
+				length = l
+				i += len(l)
+				break
+			}
+		}
+		if i >= len(s) {
+			return nil, fmt.Errorf("unterminated directive at %d", start)
+		}
+		typ, ok := cConversionType(length, s[i])
+		if !ok {
+			return nil, fmt.Errorf("invalid conversion %q at %d", s[i], start)
+		}
+		if err := useArg(number, typ); err != nil {
+			return nil, fmt.Errorf("directive at %d: %w", start, err)
+		}
+	}
+
+	return args, nil
+}
+
+func goVerbType(verb rune) (string, bool) {
+	switch verb {
+	case 'v', 'T':
+		return formatTypeAny, true
+	case 'e', 'E', 'f', 'F', 'g', 'G':
+		return "float", true
+	case 'x', 'X':
+		return "hexadecimal", true
+	case 'o', 'O':
+		return "octal", true
+	case 'b', 'c', 'd', 'p', 'q', 's', 't', 'U', 'w':
+		return string(verb), true
+	}
+	return "", false
+}
+
+// readGoArgIndex reads a "[n]" explicit argument index at the beginning of s.
+func readGoArgIndex(s string) (int, int, error) {
+	if s == "" || s[0] != '[' {
+		return 0, 0, nil
+	}
+	n, size := readDigits(s[1:])
+	if size == 0 || 1+size >= len(s) || s[1+size] != ']' || n == 0 {
+		return 0, 0, fmt.Errorf("bad argument index")
+	}
+	return n, size + 2, nil
+}
+
+func parseGoFormat(s string) (*formatArgs, error) {
+	args := newFormatArgs()
+	var argNum int
+
+	useArg := func(typ string) error {
+		argNum++
+		if !args.indexed {
+			args.sequence = append(args.sequence, typ)
+		}
+		return args.set(strconv.Itoa(argNum), typ)
+	}
+	readIndex := func(i *int) error {
+		n, size, err := readGoArgIndex(s[*i:])
+		if err != nil {
+			return err
+		}
+		if size > 0 {
+			args.indexed = true
+			argNum = n - 1
+			*i += size
+		}
+		return nil
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			continue
+		}
+		start := i
+		i++
+		if i < len(s) && s[i] == '%' {
+			continue
+		}
+		for i < len(s) && strings.IndexByte("+-# 0", s[i]) != -1 {
+			i++
+		}
+		// Width and precision.
+		for part := 0; part < 2; part++ {
+			if part == 1 {
+				if i >= len(s) || s[i] != '.' {
+					break
+				}
+				i++
+			}
+			if err := readIndex(&i); err != nil {
+				return nil, fmt.Errorf("directive at %d: %w", start, err)
+			}
+			if i < len(s) && s[i] == '*' {
+				i++
+				if err := useArg("d"); err != nil {
+					return nil, fmt.Errorf("directive at %d: %w", start, err)
+				}
+				continue
+			}
+			_, size := readDigits(s[i:])
+			i += size
+		}
+		if err := readIndex(&i); err != nil {
+			return nil, fmt.Errorf("directive at %d: %w", start, err)
+		}
+		if i >= len(s) {
+			return nil, fmt.Errorf("unterminated directive at %d", start)
+		}
+
+		verb := []rune(s[i:])[0]
+		typ, ok := goVerbType(verb)
+		if !ok {
+			return nil, fmt.Errorf("invalid verb %q at %d", verb, start)
+		}
+		i += len(string(verb)) - 1
+		if err := useArg(typ); err != nil {
+			return nil, fmt.Errorf("directive at %d: %w", start, err)
+		}
+	}
+
+	return args, nil
+}
+
+func pythonConversionType(conv byte) (string, bool) {
+	switch conv {
+	case 'd', 'i', 'u', 'o', 'x', 'X':
+		return "integer", true
+	case 'e', 'E', 'f', 'F', 'g', 'G':
+		return "float", true
+	case 'c':
+		return "character", true
+	case 's', 'r', 'a':
+		return "string", true
+	}
+	return "", false
+}
+
+func parsePythonFormat(s string) (*formatArgs, error) {
+	args := newFormatArgs()
+	var next int
+	var named, unnamed bool
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			continue
+		}
+		start := i
+		i++
+		if i < len(s) && s[i] == '%' {
+			continue
+		}
+
+		var name string
+		if i < len(s) && s[i] == '(' {
+			end := strings.IndexByte(s[i:], ')')
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated argument name at %d", start)
+			}
+			name = s[i+1 : i+end]
+			i += end + 1
+			named = true
+		} else {
+			unnamed = true
+		}
+		for i < len(s) && strings.IndexByte("#0- +", s[i]) != -1 {
+			i++
+		}
+		for part := 0; part < 2; part++ {
+			if part == 1 {
+				if i >= len(s) || s[i] != '.' {
+					break
+				}
+				i++
+			}
+			if i < len(s) && s[i] == '*' {
+				if name != "" {
+					return nil, fmt.Errorf("'*' can't be used with named arguments at %d", start)
+				}
+				i++
+				next++
+				args.sequence = append(args.sequence, "integer")
+				if err := args.set(strconv.Itoa(next), "integer"); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			_, size := readDigits(s[i:])
+			i += size
+		}
+		if i < len(s) && strings.IndexByte("hlL", s[i]) != -1 {
+			i++
+		}
+		if i >= len(s) {
+			return nil, fmt.Errorf("unterminated directive at %d", start)
+		}
+		typ, ok := pythonConversionType(s[i])
+		if !ok {
+			return nil, fmt.Errorf("invalid conversion %q at %d", s[i], start)
+		}
+		if named && unnamed {
+			return nil, fmt.Errorf("named and unnamed arguments are mixed")
+		}
+
+		arg := name
+		if name == "" {
+			next++
+			arg = strconv.Itoa(next)
+			args.sequence = append(args.sequence, typ)
+		} else {
+			args.indexed = true
+		}
+		if err := args.set(arg, typ); err != nil {
+			return nil, err
+		}
+	}
+
+	return args, nil
+}
+
+func parsePythonBraceFormat(s string) (*formatArgs, error) {
+	args := newFormatArgs()
+	var next int
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '}':
+			if i+1 < len(s) && s[i+1] == '}' {
+				i++
+				continue
+			}
+			return nil, fmt.Errorf("single '}' at %d", i)
+		case '{':
+			if i+1 < len(s) && s[i+1] == '{' {
+				i++
+				continue
+			}
+		default:
+			continue
+		}
+
+		start := i
+		i++
+		nameEnd := i
+		for nameEnd < len(s) && strings.IndexByte("}!:.[", s[nameEnd]) == -1 {
+			nameEnd++
+		}
+		name := s[i:nameEnd]
+
+		// Skip the rest of the field, format specs may contain nested fields.
+		depth := 1
+		for i = nameEnd; i < len(s) && depth > 0; i++ {
+			switch s[i] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			}
+		}
+		if depth > 0 {
+			return nil, fmt.Errorf("unterminated field at %d", start)
+		}
+		i--
+
+		if name == "" {
+			name = strconv.Itoa(next)
+			next++
+		} else {
+			args.indexed = true
+		}
+		if err := args.set(name, formatTypeAny); err != nil {
+			return nil, err
+		}
+	}
+
+	return args, nil
+}
+
+// FormatKind returns the format declared by the flags of the entry.
+// It returns false if there is no format flag, or if there is a
+// "no-<kind>-format" flag for the found kind.
+func (e Entry) FormatKind() (FormatKind, bool) {
+	formats := e.FormatFlags()
+	for _, kind := range FormatKinds {
+		if formats[kind] {
+			return kind, true
+		}
+	}
+
+	return "", false
+}
+
+// CheckFormat checks that the translations of the entry use the same
+// format directives as its msgid, or its msgid_plural for plural entries.
+//
+// Plural forms are checked assuming the default "n != 1" plural rule,
+// use [Entries.CheckFormat] to check them against the header.
+// It returns nil if the entry has no format flag or no translation.
+func (e Entry) CheckFormat() []error {
+	plural, _ := Header{}.PluralFunc()
+	return e.checkFormat(singleValuePluralForms(plural))
+}
+
+// checkFormat checks the entry format. The plural forms present in
+// singleForms are used for only one value of n, so they are allowed
+// to omit arguments, like in gettext.
+func (e Entry) checkFormat(singleForms map[int]bool) []error {
+	kind, ok := e.FormatKind()
+	if !ok {
+		return nil
+	}
+
+	var errs []error
+	addErrs := func(plural int, source, translation string, lenient bool) {
+		if translation == "" {
+			return
+		}
+		for _, err := range checkFormatString(kind, source, translation, lenient) {
+			err.Plural = plural
+			errs = append(errs, err)
+		}
+	}
+
+	if !e.IsPlural() || len(e.Plurals) == 0 {
+		addErrs(-1, e.ID, e.Str, false)
+		return errs
+	}
+
+	source := e.Plural
+	if source == "" {
+		source = e.ID
+	}
+	for _, pe := range e.Plurals {
+		addErrs(pe.ID, source, pe.Str, singleForms[pe.ID])
+	}
+
+	return errs
+}
+
+// checkFormatString compares the directives of the source string
+// and its translation.
+func checkFormatString(kind FormatKind, source, translation string, lenient bool) []*FormatError {
+	newErr := func(reason FormatErrorReason, arg, expected, got string) *FormatError {
+		return &FormatError{
+			Kind:     kind,
+			Reason:   reason,
+			Plural:   -1,
+			Arg:      arg,
+			Expected: expected,
+			Got:      got,
+		}
+	}
+
+	src, err := parseFormat(kind, source)
+	if err != nil {
+		return []*FormatError{newErr(FormatInvalidSource, "", err.Error(), "")}
+	}
+	dst, err := parseFormat(kind, translation)
+	if err != nil {
+		return []*FormatError{newErr(FormatInvalidTranslation, "", "", err.Error())}
+	}
+
+	// Translations that only swap the order of unindexed
+	// directives are reported as a single error.
+	if !src.indexed && !dst.indexed && len(src.sequence) == len(dst.sequence) &&
+		!equalTypeSequences(src.sequence, dst.sequence) &&
+		equalTypeSequences(sortedCopy(src.sequence), sortedCopy(dst.sequence)) {
+		return []*FormatError{newErr(
+			FormatReorderedWithoutIndex, "",
+			strings.Join(src.sequence, ", "), strings.Join(dst.sequence, ", "),
+		)}
+	}
+
+	var errs []*FormatError
+	for _, arg := range sortedFormatArgs(src.types, dst.types) {
+		srcType, inSrc := src.types[arg]
+		dstType, inDst := dst.types[arg]
+		switch {
+		case !inDst && !lenient:
+			errs = append(errs, newErr(FormatMissingDirective, arg, srcType, ""))
+		case !inSrc:
+			errs = append(errs, newErr(FormatExtraDirective, arg, "", dstType))
+		case inSrc && inDst && !formatTypesCompatible(srcType, dstType):
+			errs = append(errs, newErr(FormatTypeMismatch, arg, srcType, dstType))
+		}
+	}
+
+	return errs
+}
+
+func equalTypeSequences(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !formatTypesCompatible(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedCopy(s []string) []string {
+	c := append([]string(nil), s...)
+	sort.Strings(c)
+	return c
+}
+
+// sortedFormatArgs returns the union of the keys of a and b, with numbers
+// sorted numerically before names.
+func sortedFormatArgs(a, b map[string]string) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ni, erri := strconv.Atoi(keys[i])
+		nj, errj := strconv.Atoi(keys[j])
+		switch {
+		case erri == nil && errj == nil:
+			return ni < nj
+		case erri == nil:
+			return true
+		case errj == nil:
+			return false
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// singleValuePluralForms returns the plural forms that are selected
+// by only one value of n, by sampling the plural function.
+func singleValuePluralForms(plural PluralFunc) map[int]bool {
+	const samples = 1000
+	count := make(map[int]int)
+	for n := uint64(0); n < samples; n++ {
+		count[plural(n)]++
+	}
+
+	single := make(map[int]bool)
+	for form, c := range count {
+		if c == 1 {
+			single[form] = true
+		}
+	}
+
+	return single
+}
+
+// CheckFormat checks the format directives of all the translated,
+// non fuzzy and non obsolete entries. See [Entry.CheckFormat].
+//
+// The plural forms of the header are used to know which forms may
+// omit arguments because they only apply to a single number.
+func (e Entries) CheckFormat() []error {
+	var errs []error
+
+	plural, err := e.Header().PluralFunc()
+	if err != nil {
+		plural, _ = Header{}.PluralFunc()
+	}
+	singleForms := singleValuePluralForms(plural)
+
+	for index, entry := range e {
+		if entry.IsHeader() || entry.IsFuzzy() || entry.Obsolete {
+			continue
+		}
+		for _, err := range entry.checkFormat(singleForms) {
+			errs = append(errs,
+				&InvalidEntryAtIndexError{
+					Index: index,
+					Reason: &InvalidEntryError{
+						ID:     entry.UnifiedID(),
+						Reason: err,
+					},
+				},
+			)
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,134 @@
+package po_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+)
+
+func formatReasons(errs []error) []po.FormatErrorReason {
+	var reasons []po.FormatErrorReason
+	for _, err := range errs {
+		var ferr *po.FormatError
+		if errors.As(err, &ferr) {
+			reasons = append(reasons, ferr.Reason)
+		}
+	}
+	return reasons
+}
+
+func TestCheckFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		flag     string
+		id, str  string
+		expected []po.FormatErrorReason
+	}{
+		{"c ok", "c-format", "%d files in %s", "%d archivos en %s", nil},
+		{"c numbered", "c-format", "%d files in %s", "en %2$s hay %1$d archivos", nil},
+		{"c missing", "c-format", "%d files in %s", "%d archivos", []po.FormatErrorReason{po.FormatMissingDirective}},
+		{"c extra", "c-format", "%d files", "%d archivos en %s", []po.FormatErrorReason{po.FormatExtraDirective}},
+		{"c type", "c-format", "%d files", "%s archivos", []po.FormatErrorReason{po.FormatTypeMismatch}},
+		{"c length", "c-format", "%ld files", "%d archivos", []po.FormatErrorReason{po.FormatTypeMismatch}},
+		{"c case", "c-format", "%x", "%X", nil},
+		{"c percent", "c-format", "100%% of %s", "100%% de %s", nil},
+		{
+			"c reordered", "c-format", "%d files in %s", "en %s hay %d archivos",
+			[]po.FormatErrorReason{po.FormatReorderedWithoutIndex},
+		},
+		{"c invalid", "c-format", "%d", "%y", []po.FormatErrorReason{po.FormatInvalidTranslation}},
+		{"go ok", "go-format", "%d files in %q", "%d archivos en %q", nil},
+		{"go indexed", "go-format", "%d files in %s", "en %[2]s hay %[1]d archivos", nil},
+		{"go any", "go-format", "%d files", "%v archivos", nil},
+		{"go width", "go-format", "%*d", "%[2]d %[1]*d", nil},
+		{
+			"go reordered", "go-format", "%d files in %s", "en %s hay %d archivos",
+			[]po.FormatErrorReason{po.FormatReorderedWithoutIndex},
+		},
+		{"go type", "go-format", "%d", "%s", []po.FormatErrorReason{po.FormatTypeMismatch}},
+		{"python named", "python-format", "%(n)d in %(dir)s", "en %(dir)s hay %(n)d", nil},
+		{
+			"python named missing", "python-format", "%(n)d in %(dir)s", "hay %(n)d",
+			[]po.FormatErrorReason{po.FormatMissingDirective},
+		},
+		{"python type", "python-format", "%(n)d", "%(n)s", []po.FormatErrorReason{po.FormatTypeMismatch}},
+		{"brace ok", "python-brace-format", "{n} in {dir!r:>{w}}", "{dir} contiene {n}", nil},
+		{"brace auto", "python-brace-format", "{} and {}", "{} y {}", nil},
+		{"brace extra", "python-brace-format", "{n}", "{n} {m}", []po.FormatErrorReason{po.FormatExtraDirective}},
+		{"brace escaped", "python-brace-format", "{{n}}", "{{m}}", nil},
+		{"no format", "no-c-format", "%d", "%s", nil},
+		{"no flag", "", "%d", "%s", nil},
+		{"untranslated", "c-format", "%d", "", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entry := po.Entry{ID: test.id, Str: test.str}
+			if test.flag != "" {
+				entry.Flags = []string{test.flag}
+			}
+
+			got := formatReasons(entry.CheckFormat())
+			if len(got) != len(test.expected) {
+				t.Fatalf("expected %v, got %v (%v)", test.expected, got, entry.CheckFormat())
+			}
+			for i := range got {
+				if got[i] != test.expected[i] {
+					t.Errorf("expected %v, got %v", test.expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckFormatPlurals(t *testing.T) {
+	entries := po.Entries{
+		{
+			Str: "Language: ru\n" +
+				"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : " +
+				"n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n",
+		},
+		{
+			Flags:  []string{"c-format"},
+			ID:     "%d file",
+			Plural: "%d files",
+			Plurals: po.PluralEntries{
+				{ID: 0, Str: "%d файл"},
+				{ID: 1, Str: "%d файла"},
+				{ID: 2, Str: "файлов"},
+			},
+		},
+		{
+			Flags:  []string{"fuzzy", "c-format"},
+			ID:     "%s",
+			Plural: "%s",
+			Plurals: po.PluralEntries{
+				{ID: 0, Str: "%d"},
+			},
+		},
+	}
+
+	errs := entries.CheckFormat()
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+
+	var ferr *po.FormatError
+	if !errors.As(errs[0], &ferr) {
+		t.Fatalf("expected a FormatError, got %v", errs[0])
+	}
+	if ferr.Plural != 2 || ferr.Reason != po.FormatMissingDirective || ferr.Arg != "1" {
+		t.Errorf("unexpected error: %#v", ferr)
+	}
+
+	// Singular forms used by only one number may omit it.
+	entries[0].Str = "Plural-Forms: nplurals=2; plural=(n != 1);\n"
+	entries[1].Plurals = po.PluralEntries{
+		{ID: 0, Str: "un archivo"},
+		{ID: 1, Str: "%d archivos"},
+	}
+	if errs = entries.CheckFormat(); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
@@ -27,6 +27,26 @@ var knownHeaderFields = map[string]struct{}{
 	"X-Generator":               {},
 }
 
+// defaultedHeaderFields are the fields that [Header.ToConfig] fills in when
+// the header doesn't have them.
+var defaultedHeaderFields = []string{"MIME-Version", "Content-Type", "Content-Transfer-Encoding"}
+
+// headerFieldOrder is the order in which GNU gettext writes the known fields.
+var headerFieldOrder = []string{
+	"Project-Id-Version",
+	"Report-Msgid-Bugs-To",
+	"POT-Creation-Date",
+	"PO-Revision-Date",
+	"Last-Translator",
+	"Language-Team",
+	"Language",
+	"MIME-Version",
+	"Content-Type",
+	"Content-Transfer-Encoding",
+	"Plural-Forms",
+	"X-Generator",
+}
+
 // HeaderField represents a single key-value pair in a header.
 type HeaderField struct {
 	Key   string // The name of the header field.
@@ -49,7 +69,7 @@ func NewHeader(options ...HeaderOption) Header {
 }
 
 var (
-	headerRegex = regexp.MustCompile(`(.*)\s*:\s*(.*)`)
+	headerRegex = regexp.MustCompile(`([^:]+?)\s*:\s*(.*)`)
 	exprRegex   = regexp.MustCompile(
 		`(?: *(\S+?) *= *(.+?) *; *)`,
 	)
@@ -94,36 +114,42 @@ func (h Header) ToConfig() HeaderConfig {
 	mtype, params, err := mime.ParseMediaType(h.Load("Content-Type"))
 	if err == nil {
 		mediatype = mtype
-		chset := params["charset"]
-		if util.SupportedCharsets[chset] {
+		if chset := util.NormalizeCharset(params["charset"]); chset != "" {
 			charset = chset
 		}
 	}
 
-	var nplurals uint = 2
-	var plural string
-	{
-		pluralForms := h.Load("Plural-Forms")
-		matches := parseAdvHeaderField(pluralForms)
-		if npluralsStr, ok := matches["nplurals"]; ok {
-			np, err := strconv.ParseUint(npluralsStr, 10, strconv.IntSize)
-			if err == nil {
-				nplurals = uint(np)
-			}
-		}
-		if pluralStr, ok := matches["plural"]; ok {
-			plural = pluralStr
-		}
+	transferEncoding := h.Load("Content-Transfer-Encoding")
+	if transferEncoding == "" {
+		transferEncoding = "8bit"
 	}
 
 	extraFields := make([]HeaderField, 0, len(h.Fields))
+	fieldOrder := make([]string, 0, len(h.Fields))
 
 	for _, hf := range h.Fields {
+		fieldOrder = append(fieldOrder, hf.Key)
 		if _, known := knownHeaderFields[hf.Key]; !known {
 			extraFields = append(extraFields, hf)
 		}
 	}
 
+	var nplurals uint = 2
+	var plural string
+	{
+		pluralForms := h.Load("Plural-Forms")
+		matches := parseAdvHeaderField(pluralForms)
+		np, err := strconv.ParseUint(matches["nplurals"], 10, strconv.IntSize)
+		switch {
+		case err == nil:
+			nplurals = uint(np)
+			plural = matches["plural"]
+		case slices.Contains(fieldOrder, "Plural-Forms"):
+			// Placeholders like "nplurals=INTEGER; plural=EXPRESSION;" are kept as they are.
+			extraFields = append(extraFields, HeaderField{Key: "Plural-Forms", Value: pluralForms})
+		}
+	}
+
 	extraFields = slices.Clip(extraFields)
 
 	return HeaderConfig{
@@ -138,11 +164,12 @@ func (h Header) ToConfig() HeaderConfig {
 		MimeVersion:             mimeVersion,
 		MediaType:               mediatype,
 		Charset:                 charset,
-		ContentTransferEncoding: "8bit",
+		ContentTransferEncoding: transferEncoding,
 		Plural:                  plural,
 		Nplurals:                nplurals,
 		XGenerator:              h.Load("X-Generator"),
 		ExtraFields:             extraFields,
+		FieldOrder:              fieldOrder,
 	}
 }
 
@@ -162,6 +189,31 @@ func (h Header) Nplurals() (nplurals uint) {
 	return nplurals
 }
 
+// PluralFunc compiles the Plural-Forms header field into a function
+// that returns the index of the plural form to use for a given n.
+//
+// If the header has no Plural-Forms field, the germanic rule
+// "nplurals=2; plural=(n != 1);" is used. Indexes greater or equal
+// than nplurals are reported as 0.
+func (h Header) PluralFunc() (PluralFunc, error) {
+	var nplurals uint64 = 2
+	plural := "(n != 1)"
+
+	fields := parseAdvHeaderField(h.Load("Plural-Forms"))
+	if np, ok := fields["nplurals"]; ok {
+		n, err := strconv.ParseUint(np, 10, 64)
+		if err != nil || n == 0 {
+			return nil, fmt.Errorf("invalid nplurals value %q", np)
+		}
+		nplurals = n
+	}
+	if p, ok := fields["plural"]; ok {
+		plural = p
+	}
+
+	return limitPluralFunc(plural, nplurals)
+}
+
 func (h Header) ToEntry() Entry {
 	var b strings.Builder
 
@@ -169,7 +221,7 @@ func (h Header) ToEntry() Entry {
 		if field.Value != " " {
 			field.Value = " " + field.Value
 		}
-		fmt.Fprintf(&b, "\n%s:%s\n", field.Key, field.Value)
+		fmt.Fprintf(&b, "%s:%s\n", field.Key, field.Value)
 	}
 	entry := Entry{Str: b.String()}
 	if h.Template {
@@ -196,6 +248,56 @@ type HeaderConfig struct {
 	Plural                  string
 	XGenerator              string
 	ExtraFields             []HeaderField
+	// FieldOrder is the order of the fields of the header the config was
+	// read from (see [Header.ToConfig]). [HeaderConfig.ToHeader] writes the
+	// fields in this order, and the ones that aren't in it after them.
+	// The fields in it are written even if they're empty, while the
+	// MIME-Version, Content-Type and Content-Transfer-Encoding that
+	// ToConfig fills in aren't added if they're not in it.
+	FieldOrder []string
+}
+
+// PluralFunc compiles the Plural and Nplurals fields into a function
+// that returns the index of the plural form to use for a given n.
+func (cfg HeaderConfig) PluralFunc() (PluralFunc, error) {
+	if cfg.Plural == "" || cfg.Nplurals == 0 {
+		return limitPluralFunc("(n != 1)", 2)
+	}
+
+	return limitPluralFunc(cfg.Plural, uint64(cfg.Nplurals))
+}
+
+// SetPluralFormsFromLanguage fills Plural and Nplurals with the plural
+// forms of the Language field, using the built-in locale database, and
+// Charset with the default charset of the language if it's empty.
+// It reports whether the language was found.
+func (cfg *HeaderConfig) SetPluralFormsFromLanguage() bool {
+	locale, ok := LookupLocale(cfg.Language)
+	if !ok {
+		return false
+	}
+	cfg.Nplurals = locale.PluralForms.Nplurals
+	cfg.Plural = locale.PluralForms.Plural
+	if cfg.Charset == "" {
+		cfg.Charset = locale.Charset
+	}
+
+	return true
+}
+
+func limitPluralFunc(plural string, nplurals uint64) (PluralFunc, error) {
+	f, err := CompilePluralForms(plural)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(n uint64) int {
+		i := f(n)
+		if i < 0 || uint64(i) >= nplurals {
+			return 0
+		}
+		return i
+	}, nil
 }
 
 func (cfg HeaderConfig) Validate() []error {
@@ -208,6 +310,11 @@ func (cfg HeaderConfig) Validate() []error {
 		if cfg.Nplurals == 0 {
 			errs = append(errs, errors.New("nplurals can't be zero"))
 		}
+		if cfg.Plural != "" {
+			if _, err := CompilePluralForms(cfg.Plural); err != nil {
+				errs = append(errs, err)
+			}
+		}
 	}
 
 	if cfg.MediaType != "text/plain" && cfg.MediaType != "" {
@@ -219,7 +326,7 @@ func (cfg HeaderConfig) Validate() []error {
 			fmt.Errorf("content-transfer-encoding(%s) must be 8bit", cfg.ContentTransferEncoding),
 		)
 	}
-	if !util.SupportedCharsets[cfg.Charset] && cfg.Charset != "" {
+	if !util.SupportedCharsets[util.NormalizeCharset(cfg.Charset)] && cfg.Charset != "" && cfg.Charset != "CHARSET" {
 		errs = append(errs, fmt.Errorf("%q isn't a supported charset", cfg.Charset))
 	}
 
@@ -228,37 +335,71 @@ func (cfg HeaderConfig) Validate() []error {
 
 func (cfg HeaderConfig) ToHeader() (h Header) {
 	h.Template = cfg.Template
-	h.sSet("Project-Id-Version", cfg.ProjectIDVersion)
-	h.sSet("Report-Msgid-Bugs-To", cfg.ReportMsgidBugsTo)
-	if cfg.Template {
-		h.sSet("POT-Creation-Date", cfg.POTCreationDate)
-	}
-	h.sSet("PO-Revision-Date", cfg.PORevisionDate)
-	h.sSet("Last-Translator", cfg.LastTranslator)
-	h.sSet("Language-Team", cfg.LanguageTeam)
-	h.sSet("Language", cfg.Language)
-	h.sSet("MIME-Version", cfg.MimeVersion)
+	set := func(key, value string) {
+		switch {
+		case slices.Contains(cfg.FieldOrder, key):
+			h.Set(key, value)
+		case len(cfg.FieldOrder) > 0 && slices.Contains(defaultedHeaderFields, key):
+		default:
+			h.sSet(key, value)
+		}
+	}
+
+	set("Project-Id-Version", cfg.ProjectIDVersion)
+	set("Report-Msgid-Bugs-To", cfg.ReportMsgidBugsTo)
+	set("POT-Creation-Date", cfg.POTCreationDate)
+	set("PO-Revision-Date", cfg.PORevisionDate)
+	set("Last-Translator", cfg.LastTranslator)
+	set("Language-Team", cfg.LanguageTeam)
+	set("Language", cfg.Language)
+	set("MIME-Version", cfg.MimeVersion)
 	if cfg.MediaType != "" && cfg.Charset != "" {
-		h.sSet(
+		set(
 			"Content-Type",
 			mime.FormatMediaType(cfg.MediaType, map[string]string{"charset": cfg.Charset}),
 		)
 	}
-	h.sSet("Content-Transfer-Encoding", cfg.ContentTransferEncoding)
-	if cfg.Nplurals != 0 && cfg.Plural != "" && !cfg.Template {
-		h.sSet(
+	set("Content-Transfer-Encoding", cfg.ContentTransferEncoding)
+	// Templates have no plural forms, unless they were read from a header with them.
+	if cfg.Nplurals != 0 && cfg.Plural != "" && (!cfg.Template || slices.Contains(cfg.FieldOrder, "Plural-Forms")) {
+		set(
 			"Plural-Forms",
 			fmt.Sprintf("nplurals=%d; plural=%s;", cfg.Nplurals, cfg.Plural),
 		)
 	}
-	h.sSet("X-Generator", cfg.XGenerator)
+	set("X-Generator", cfg.XGenerator)
 
 	for _, field := range cfg.ExtraFields {
-		h.sSet(field.Key, field.Value)
+		set(field.Key, field.Value)
 	}
+	h.sortFields(cfg.FieldOrder)
+
 	return h
 }
 
+// sortFields moves the fields in order to the start of the header, in that
+// order, keeping the rest after them.
+func (h *Header) sortFields(order []string) {
+	if len(order) == 0 {
+		return
+	}
+
+	sorted := make([]HeaderField, 0, len(h.Fields))
+	for _, key := range order {
+		isKey := func(f HeaderField) bool { return f.Key == key }
+		if i := slices.IndexFunc(h.Fields, isKey); i != -1 && !slices.ContainsFunc(sorted, isKey) {
+			sorted = append(sorted, h.Fields[i])
+		}
+	}
+	for _, field := range h.Fields {
+		if !slices.Contains(order, field.Key) {
+			sorted = append(sorted, field)
+		}
+	}
+
+	h.Fields = sorted
+}
+
 func HeaderConfigFromOptions(options ...HeaderOption) HeaderConfig {
 	var h HeaderConfig
 	for _, opt := range options {
@@ -312,6 +453,55 @@ func (e Entries) HasHeader() bool {
 	return e.Index("", "") != -1
 }
 
+// SetHeaderField sets the value of a field of the header of the entries,
+// keeping the order and the format of the rest of the header. A missing
+// known field is inserted before the first field that GNU gettext writes
+// after it, and any other missing field is appended. The header is added
+// if there isn't one.
+func (e *Entries) SetHeaderField(key, value string) {
+	i := e.Index("", "")
+	if i == -1 {
+		*e = append(Entries{{}}, *e...)
+		i = 0
+	}
+	field := key + ": " + value
+
+	lines := strings.SplitAfter((*e)[i].Str, "\n")
+	insert := -1
+	for j, line := range lines {
+		m := headerRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if m[1] == key {
+			lines[j] = field + line[len(strings.TrimRight(line, "\n")):]
+			(*e)[i].Str = strings.Join(lines, "")
+			return
+		}
+		if insert == -1 && fieldGoesBefore(key, m[1]) {
+			insert = j
+		}
+	}
+	if insert != -1 {
+		lines = append(lines[:insert], append([]string{field + "\n"}, lines[insert:]...)...)
+		(*e)[i].Str = strings.Join(lines, "")
+		return
+	}
+
+	str := (*e)[i].Str
+	if str != "" && !strings.HasSuffix(str, "\n") {
+		str += "\n"
+	}
+	(*e)[i].Str = str + field + "\n"
+}
+
+// fieldGoesBefore reports whether GNU gettext writes the known field key
+// before the known field other.
+func fieldGoesBefore(key, other string) bool {
+	i, j := slices.Index(headerFieldOrder, key), slices.Index(headerFieldOrder, other)
+	return i != -1 && j != -1 && i < j
+}
+
 func EntryToHeader(entry Entry) Header {
 	var h Header
 
@@ -0,0 +1,106 @@
+package po
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HeaderDateLayout is the layout of the dates of the header, like
+// "2025-08-23 14:05+0200" (YYYY-MM-DD HH:MM+ZZZZ), in the format of the time package.
+const HeaderDateLayout = "2006-01-02 15:04-0700"
+
+// headerDateLayouts are the layouts accepted by [ParseHeaderDate]. Some
+// editors write the seconds too.
+var headerDateLayouts = []string{
+	HeaderDateLayout,
+	"2006-01-02 15:04:05-0700",
+}
+
+// ParseHeaderDate parses a date of the header in the layout used by gettext.
+// Placeholders like "YEAR-MO-DA HO:MI+ZONE" are reported as errors.
+func ParseHeaderDate(date string) (time.Time, error) {
+	date = strings.TrimSpace(date)
+
+	var err error
+	for _, layout := range headerDateLayouts {
+		var t time.Time
+		t, err = time.Parse(layout, date)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, err
+}
+
+// FormatHeaderDate formats t in the layout used by gettext for the dates of the header.
+func FormatHeaderDate(t time.Time) string {
+	return t.Format(HeaderDateLayout)
+}
+
+// HeaderNow returns the time used to stamp the dates of the header. For
+// reproducible builds it is the time in the SOURCE_DATE_EPOCH environment
+// variable, in UTC, if it is set to a valid timestamp; otherwise it's the
+// current local time.
+func HeaderNow() time.Time {
+	if epoch, ok := os.LookupEnv("SOURCE_DATE_EPOCH"); ok {
+		seconds, err := strconv.ParseInt(strings.TrimSpace(epoch), 10, 64)
+		if err == nil {
+			return time.Unix(seconds, 0).UTC()
+		}
+	}
+
+	return time.Now()
+}
+
+// POTCreationTime parses the POT-Creation-Date field.
+func (h Header) POTCreationTime() (time.Time, error) {
+	return ParseHeaderDate(h.Load("POT-Creation-Date"))
+}
+
+// PORevisionTime parses the PO-Revision-Date field.
+func (h Header) PORevisionTime() (time.Time, error) {
+	return ParseHeaderDate(h.Load("PO-Revision-Date"))
+}
+
+// SetPOTCreationTime sets the POT-Creation-Date field to t.
+func (h *Header) SetPOTCreationTime(t time.Time) {
+	h.Set("POT-Creation-Date", FormatHeaderDate(t))
+}
+
+// SetPORevisionTime sets the PO-Revision-Date field to t.
+func (h *Header) SetPORevisionTime(t time.Time) {
+	h.Set("PO-Revision-Date", FormatHeaderDate(t))
+}
+
+// POTCreationTime parses the POTCreationDate field.
+func (cfg HeaderConfig) POTCreationTime() (time.Time, error) {
+	return ParseHeaderDate(cfg.POTCreationDate)
+}
+
+// PORevisionTime parses the PORevisionDate field.
+func (cfg HeaderConfig) PORevisionTime() (time.Time, error) {
+	return ParseHeaderDate(cfg.PORevisionDate)
+}
+
+// SetPOTCreationTime sets the POTCreationDate field to t.
+func (cfg *HeaderConfig) SetPOTCreationTime(t time.Time) {
+	cfg.POTCreationDate = FormatHeaderDate(t)
+}
+
+// SetPORevisionTime sets the PORevisionDate field to t.
+func (cfg *HeaderConfig) SetPORevisionTime(t time.Time) {
+	cfg.PORevisionDate = FormatHeaderDate(t)
+}
+
+// StampRevision sets the PO-Revision-Date field of the header of the entries
+// to date, and the Last-Translator field to lastTranslator if it isn't empty.
+// The rest of the header is kept untouched; it is added if it doesn't exist.
+func (e *Entries) StampRevision(date time.Time, lastTranslator string) {
+	e.SetHeaderField("PO-Revision-Date", FormatHeaderDate(date))
+	if lastTranslator != "" {
+		e.SetHeaderField("Last-Translator", lastTranslator)
+	}
+}
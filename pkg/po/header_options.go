@@ -1,5 +1,7 @@
 package po
 
+import "time"
+
 type HeaderOption func(*HeaderConfig)
 
 func HeaderWithConfig(c HeaderConfig) HeaderOption {
@@ -32,6 +34,20 @@ func HeaderWithPORevisionDate(r string) HeaderOption {
 	}
 }
 
+// HeaderWithPOTCreationTime sets the POT-Creation-Date to t, see [FormatHeaderDate].
+func HeaderWithPOTCreationTime(t time.Time) HeaderOption {
+	return func(hc *HeaderConfig) {
+		hc.SetPOTCreationTime(t)
+	}
+}
+
+// HeaderWithPORevisionTime sets the PO-Revision-Date to t, see [FormatHeaderDate].
+func HeaderWithPORevisionTime(t time.Time) HeaderOption {
+	return func(hc *HeaderConfig) {
+		hc.SetPORevisionTime(t)
+	}
+}
+
 func HeaderWithXGenerator(g string) HeaderOption {
 	return func(hc *HeaderConfig) {
 		hc.XGenerator = g
@@ -97,3 +113,13 @@ func HeaderWithLastTranslator(translator string) HeaderOption {
 		hc.LastTranslator = translator
 	}
 }
+
+// HeaderWithLanguagePluralForms sets the Language field and fills the
+// plural forms of the language from the built-in locale database.
+// If the language is unknown, the plural forms are left unchanged.
+func HeaderWithLanguagePluralForms(lang string) HeaderOption {
+	return func(hc *HeaderConfig) {
+		hc.Language = lang
+		hc.SetPluralFormsFromLanguage()
+	}
+}
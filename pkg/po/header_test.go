@@ -0,0 +1,128 @@
+package po_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tom5521/gotext-tools/v2/internal/slices"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+)
+
+func TestHeaderDate(t *testing.T) {
+	zone := time.FixedZone("", 2*60*60)
+	tests := []struct {
+		date     string
+		expected time.Time
+		fail     bool
+	}{
+		{"2025-08-23 14:05+0200", time.Date(2025, 8, 23, 14, 5, 0, 0, zone), false},
+		{"2025-08-23 14:05:30+0200", time.Date(2025, 8, 23, 14, 5, 30, 0, zone), false},
+		{"YEAR-MO-DA HO:MI+ZONE", time.Time{}, true},
+		{"", time.Time{}, true},
+	}
+
+	for _, test := range tests {
+		got, err := po.ParseHeaderDate(test.date)
+		if test.fail {
+			if err == nil {
+				t.Errorf("%q: expected an error", test.date)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.date, err)
+			continue
+		}
+		if !got.Equal(test.expected) {
+			t.Errorf("%q: expected %v, got %v", test.date, test.expected, got)
+		}
+	}
+
+	date := time.Date(2025, 8, 23, 14, 5, 0, 0, zone)
+	if got := po.FormatHeaderDate(date); got != "2025-08-23 14:05+0200" {
+		t.Errorf("unexpected formatted date %q", got)
+	}
+
+	var h po.Header
+	h.SetPORevisionTime(date)
+	if got, err := h.PORevisionTime(); err != nil || !got.Equal(date) {
+		t.Errorf("expected %v, got %v (%v)", date, got, err)
+	}
+}
+
+func TestHeaderNow(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	expected := time.Unix(1700000000, 0).UTC()
+	if got := po.HeaderNow(); !got.Equal(expected) || got.Location() != time.UTC {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	t.Setenv("SOURCE_DATE_EPOCH", "invalid")
+	if got := po.HeaderNow(); time.Since(got) > time.Minute {
+		t.Errorf("expected the current time, got %v", got)
+	}
+}
+
+func TestHeaderConfigRoundTrip(t *testing.T) {
+	var h po.Header
+	h.Set("Project-Id-Version", "test 1.0")
+	h.Set("Report-Msgid-Bugs-To", "")
+	h.Set("Language", "es")
+	h.Set("X-Poedit-Basepath", "..")
+	h.Set("X-Custom", "")
+	h.Set("PO-Revision-Date", "2025-08-23 14:05+0200")
+	h.Set("Content-Type", "text/plain; charset=UTF-8")
+	h.Set("Plural-Forms", "nplurals=2; plural=(n != 1);")
+	h.Set("X-Generator", "Poedit 3.4")
+
+	got := h.ToConfig().ToHeader()
+
+	var keys []string
+	for _, f := range got.Fields {
+		keys = append(keys, f.Key)
+	}
+	expected := []string{
+		"Project-Id-Version",
+		"Report-Msgid-Bugs-To",
+		"Language",
+		"X-Poedit-Basepath",
+		"X-Custom",
+		"PO-Revision-Date",
+		"Content-Type",
+		"Plural-Forms",
+		"X-Generator",
+	}
+	for i, key := range expected {
+		j := slices.Index(keys, key)
+		if j == -1 {
+			t.Errorf("field %q lost in the round trip: %v", key, keys)
+			continue
+		}
+		if j < i {
+			t.Errorf("field %q moved: %v", key, keys)
+		}
+		if got.Load(key) != h.Load(key) {
+			t.Errorf("%q: expected %q, got %q", key, h.Load(key), got.Load(key))
+		}
+	}
+	for _, key := range keys {
+		if !slices.Contains(expected, key) {
+			t.Errorf("field %q added in the round trip: %v", key, keys)
+		}
+	}
+}
+
+func TestEntriesStampRevision(t *testing.T) {
+	entries := po.Entries{
+		{Str: "Project-Id-Version: test\nPO-Revision-Date: YEAR-MO-DA HO:MI+ZONE\nLanguage: es\n"},
+		{ID: "File", Str: "Archivo"},
+	}
+
+	date := time.Date(2025, 8, 23, 14, 5, 0, 0, time.UTC)
+	entries.StampRevision(date, "Jane Doe <jane@example.com>")
+	expected := "Project-Id-Version: test\nPO-Revision-Date: 2025-08-23 14:05+0000\n" +
+		"Last-Translator: Jane Doe <jane@example.com>\nLanguage: es\n"
+	if entries[0].Str != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, entries[0].Str)
+	}
+}
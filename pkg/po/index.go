@@ -0,0 +1,92 @@
+package po
+
+// EntryIndex maps the UnifiedID of entries to their position in an [Entries] slice.
+//
+// It allows constant time lookups where the methods of [Entries]
+// would scan the whole slice. When there are duplicated entries,
+// the position of the first one is stored, matching [Entries.IndexByUnifiedID].
+//
+// The index is a snapshot: it is not updated if the indexed entries change.
+type EntryIndex map[string]int
+
+// Indexed returns an EntryIndex of e.
+func (e Entries) Indexed() EntryIndex {
+	index := make(EntryIndex, len(e))
+	for i, entry := range e {
+		index.add(entry.UnifiedID(), i)
+	}
+
+	return index
+}
+
+// add registers uid at position i unless it is already registered.
+// It reports whether uid was added.
+func (idx EntryIndex) add(uid string, i int) bool {
+	if _, ok := idx[uid]; ok {
+		return false
+	}
+	idx[uid] = i
+	return true
+}
+
+// IndexByUnifiedID returns the position of the entry with the given
+// unified ID, or -1 if it is not present.
+func (idx EntryIndex) IndexByUnifiedID(uid string) int {
+	i, ok := idx[uid]
+	if !ok {
+		return -1
+	}
+	return i
+}
+
+// Index returns the position of the entry with the given id and context,
+// or -1 if it is not present.
+func (idx EntryIndex) Index(id, context string) int {
+	return idx.IndexByUnifiedID(Entry{ID: id, Context: context}.UnifiedID())
+}
+
+// ContainsUnifiedID reports whether an entry with the given unified ID is present.
+func (idx EntryIndex) ContainsUnifiedID(uid string) bool {
+	_, ok := idx[uid]
+	return ok
+}
+
+// fileIndex is the lookup index cached by [File].
+//
+// It remembers which backing array and length it was built for,
+// so a reassigned, grown or shrunk Entries slice is detected and
+// the index rebuilt.
+type fileIndex struct {
+	first *Entry
+	len   int
+	ids   EntryIndex
+}
+
+func (fi *fileIndex) valid(e Entries) bool {
+	if fi == nil || fi.len != len(e) {
+		return false
+	}
+	return len(e) == 0 || fi.first == &e[0]
+}
+
+func newFileIndex(e Entries) *fileIndex {
+	fi := &fileIndex{len: len(e), ids: e.Indexed()}
+	if len(e) > 0 {
+		fi.first = &e[0]
+	}
+	return fi
+}
+
+// lookup returns the position of uid in e, verifying that the cached
+// position still holds an entry with that unified ID.
+func (fi *fileIndex) lookup(e Entries, uid string) (int, bool) {
+	i, ok := fi.ids[uid]
+	if !ok {
+		return -1, true
+	}
+	if e[i].UnifiedID() != uid {
+		// The entry was renamed in place, the index can't be trusted.
+		return -1, false
+	}
+	return i, true
+}
@@ -0,0 +1,72 @@
+package po_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+)
+
+func generateEntries(n int, translated bool) po.Entries {
+	entries := make(po.Entries, n)
+	for i := range entries {
+		entries[i] = po.Entry{
+			ID:        fmt.Sprintf("Message number %d", i),
+			Locations: po.Locations{{File: "main.go", Line: i}},
+		}
+		if i%10 == 0 {
+			entries[i].Context = "ctx"
+		}
+		if translated {
+			entries[i].Str = fmt.Sprintf("Mensaje número %d", i)
+		}
+	}
+	return entries
+}
+
+func BenchmarkEntriesLookup(b *testing.B) {
+	entries := generateEntries(10000, true)
+	uid := entries[len(entries)-1].UnifiedID()
+
+	b.Run("Linear", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			entries.IndexByUnifiedID(uid)
+		}
+	})
+	b.Run("Indexed", func(b *testing.B) {
+		index := entries.Indexed()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			index.IndexByUnifiedID(uid)
+		}
+	})
+	b.Run("BuildIndex", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			entries.Indexed()
+		}
+	})
+}
+
+func BenchmarkFileSet(b *testing.B) {
+	entries := generateEntries(5000, true)
+
+	for i := 0; i < b.N; i++ {
+		f := po.NewFile("bench.po")
+		for _, e := range entries {
+			f.Set(e.ID, e.Context, e)
+		}
+	}
+}
+
+func BenchmarkMergeExact(b *testing.B) {
+	def := generateEntries(5000, true)
+	ref := generateEntries(5000, false)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		d := append(po.Entries(nil), def...)
+		b.StartTimer()
+		po.Merge(d, ref, po.MergeWithSort(false))
+	}
+}
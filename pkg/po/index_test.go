@@ -0,0 +1,55 @@
+package po_test
+
+import (
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+)
+
+func TestEntryIndex(t *testing.T) {
+	entries := po.Entries{
+		{ID: "a"},
+		{ID: "b", Context: "ctx"},
+		{ID: "a"},
+		{ID: "c"},
+	}
+	index := entries.Indexed()
+
+	for _, e := range entries {
+		if got, expected := index.IndexByUnifiedID(e.UnifiedID()), entries.IndexByUnifiedID(e.UnifiedID()); got != expected {
+			t.Errorf("%q: expected %d, got %d", e.UnifiedID(), expected, got)
+		}
+	}
+	if index.Index("b", "ctx") != 1 || index.Index("b", "") != -1 {
+		t.Error("context must be part of the key")
+	}
+	if index.ContainsUnifiedID("d") {
+		t.Error("unexpected entry")
+	}
+}
+
+func TestFileSetLoad(t *testing.T) {
+	f := po.NewFile("test.po")
+	f.Set("a", "", po.Entry{ID: "a", Str: "1"})
+	f.Set("b", "ctx", po.Entry{ID: "b", Context: "ctx", Str: "2"})
+	f.Set("a", "", po.Entry{ID: "a", Str: "3"})
+
+	if len(f.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(f.Entries))
+	}
+	if f.Load("a", "") != "3" || f.Load("b", "ctx") != "2" || f.Load("b", "") != "" {
+		t.Error("unexpected lookup results")
+	}
+
+	// Entries modified from outside must be taken into account.
+	f.Entries[0].ID = "renamed"
+	f.Reindex()
+	if f.Load("a", "") != "" || f.Load("renamed", "") != "3" {
+		t.Error("renamed entries must be found")
+	}
+	f.Entries = append(f.Entries, po.Entry{ID: "c", Str: "4"})
+	f.Set("renamed", "", po.Entry{ID: "renamed", Str: "5"})
+	if f.Load("c", "") != "4" || f.Load("renamed", "") != "5" || len(f.Entries) != 3 {
+		t.Error("appended entries must be found")
+	}
+}
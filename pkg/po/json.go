@@ -0,0 +1,105 @@
+package po
+
+// The JSON forms of the entries used by the diffs and the merge reports.
+// The types of the catalog have no tags of their own, so they're encoded
+// with the default field names if they're marshaled directly.
+
+type jsonPrevious struct {
+	Context string `json:"context,omitempty"`
+	ID      string `json:"id,omitempty"`
+	Plural  string `json:"plural,omitempty"`
+}
+
+func newJSONPrevious(p *PreviousMessage) *jsonPrevious {
+	if p == nil {
+		return nil
+	}
+	return &jsonPrevious{Context: p.Context, ID: p.ID, Plural: p.Plural}
+}
+
+func (j *jsonPrevious) previous() *PreviousMessage {
+	if j == nil {
+		return nil
+	}
+	return &PreviousMessage{Context: j.Context, ID: j.ID, Plural: j.Plural}
+}
+
+type jsonLocation struct {
+	Line int    `json:"line"`
+	File string `json:"file"`
+}
+
+type jsonPlural struct {
+	ID  int    `json:"id"`
+	Str string `json:"str"`
+}
+
+type jsonEntry struct {
+	Flags             []string       `json:"flags,omitempty"`
+	Comments          []string       `json:"comments,omitempty"`
+	ExtractedComments []string       `json:"extracted_comments,omitempty"`
+	Previous          *jsonPrevious  `json:"previous,omitempty"`
+	Obsolete          bool           `json:"obsolete,omitempty"`
+	ID                string         `json:"id"`
+	Context           string         `json:"context,omitempty"`
+	Plural            string         `json:"plural,omitempty"`
+	Plurals           []jsonPlural   `json:"plurals,omitempty"`
+	Str               string         `json:"str"`
+	Locations         []jsonLocation `json:"locations,omitempty"`
+}
+
+func newJSONEntry(e *Entry) *jsonEntry {
+	if e == nil {
+		return nil
+	}
+
+	j := &jsonEntry{
+		Flags:             e.Flags,
+		Comments:          e.Comments,
+		ExtractedComments: e.ExtractedComments,
+		Obsolete:          e.Obsolete,
+		ID:                e.ID,
+		Context:           e.Context,
+		Plural:            e.Plural,
+		Str:               e.Str,
+	}
+	if !e.Previous.IsZero() {
+		j.Previous = newJSONPrevious(&e.Previous)
+	}
+	for _, pe := range e.Plurals {
+		j.Plurals = append(j.Plurals, jsonPlural{ID: pe.ID, Str: pe.Str})
+	}
+	for _, l := range e.Locations {
+		j.Locations = append(j.Locations, jsonLocation{Line: l.Line, File: l.File})
+	}
+
+	return j
+}
+
+func (j *jsonEntry) entry() *Entry {
+	if j == nil {
+		return nil
+	}
+
+	e := &Entry{
+		Flags:             j.Flags,
+		Comments:          j.Comments,
+		ExtractedComments: j.ExtractedComments,
+		Obsolete:          j.Obsolete,
+		ID:                j.ID,
+		Context:           j.Context,
+		Plural:            j.Plural,
+		Str:               j.Str,
+	}
+	if p := j.Previous.previous(); p != nil {
+		e.Previous = *p
+	}
+	for _, pe := range j.Plurals {
+		e.Plurals = append(e.Plurals, PluralEntry{ID: pe.ID, Str: pe.Str})
+	}
+	for _, l := range j.Locations {
+		e.Locations = append(e.Locations, Location{Line: l.Line, File: l.File})
+	}
+
+	return e
+}
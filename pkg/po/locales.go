@@ -0,0 +1,303 @@
+package po
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Tom5521/gotext-tools/v2/internal/slices"
+)
+
+// PluralForms represents the value of a Plural-Forms header field.
+type PluralForms struct {
+	Nplurals uint   // Number of plural forms.
+	Plural   string // C expression that selects the plural form for n.
+}
+
+// String returns the plural forms formatted as a Plural-Forms header value.
+func (p PluralForms) String() string {
+	return fmt.Sprintf("nplurals=%d; plural=%s;", p.Nplurals, p.Plural)
+}
+
+// Locale describes a language (optionally specific to a territory)
+// known by the built-in locale database.
+type Locale struct {
+	Code        string      // ISO 639 code, followed by an ISO 3166 territory if any (e.g. "pt_BR").
+	Name        string      // English name of the language.
+	PluralForms PluralForms // Plural rule used by gettext for the language.
+	Charset     string      // Default charset for new catalogs, UTF-8 unless the database sets another one.
+}
+
+// Plural rules shared by several languages.
+var (
+	pluralsOnlyOne    = PluralForms{1, "0"}
+	pluralsGermanic   = PluralForms{2, "(n != 1)"}
+	pluralsRomanic    = PluralForms{2, "(n > 1)"}
+	pluralsEastSlavic = PluralForms{
+		3,
+		"(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2)",
+	}
+	pluralsCzechSlovak = PluralForms{3, "(n==1) ? 0 : (n>=2 && n<=4) ? 1 : 2"}
+	pluralsPolish      = PluralForms{3, "(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2)"}
+	pluralsLithuanian  = PluralForms{3, "(n%10==1 && n%100!=11 ? 0 : n%10>=2 && (n%100<10 || n%100>=20) ? 1 : 2)"}
+	pluralsLatvian     = PluralForms{3, "(n%10==1 && n%100!=11 ? 0 : n != 0 ? 1 : 2)"}
+	pluralsRomanian    = PluralForms{3, "(n==1 ? 0 : (n==0 || (n%100 > 0 && n%100 < 20)) ? 1 : 2)"}
+	pluralsSlovenian   = PluralForms{4, "(n%100==1 ? 0 : n%100==2 ? 1 : n%100==3 || n%100==4 ? 2 : 3)"}
+	pluralsArabic      = PluralForms{
+		6,
+		"(n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5)",
+	}
+	pluralsIrish          = PluralForms{5, "(n==1 ? 0 : n==2 ? 1 : (n>2 && n<7) ? 2 : (n>6 && n<11) ? 3 : 4)"}
+	pluralsScottishGaelic = PluralForms{4, "(n==1 || n==11) ? 0 : (n==2 || n==12) ? 1 : (n > 2 && n < 20) ? 2 : 3"}
+	pluralsWelsh          = PluralForms{4, "(n==1) ? 0 : (n==2) ? 1 : (n != 8 && n != 11) ? 2 : 3"}
+	pluralsMaltese        = PluralForms{
+		4,
+		"(n==1 ? 0 : n==0 || (n%100>1 && n%100<11) ? 1 : (n%100>10 && n%100<20) ? 2 : 3)",
+	}
+	pluralsIcelandic  = PluralForms{2, "(n%10!=1 || n%100==11)"}
+	pluralsMacedonian = PluralForms{2, "(n==1 || n%10==1) ? 0 : 1"}
+	pluralsCornish    = PluralForms{4, "(n==1) ? 0 : (n==2) ? 1 : (n == 3) ? 2 : 3"}
+	pluralsKashubian  = PluralForms{3, "(n==1) ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2"}
+	pluralsMandinka   = PluralForms{3, "(n==0 ? 0 : n==1 ? 1 : 2)"}
+	pluralsJavanese   = PluralForms{2, "(n != 0)"}
+)
+
+// defaultLocaleCharset is the charset of the locales the database doesn't set one for.
+const defaultLocaleCharset = "UTF-8"
+
+// locales is the built-in locale database.
+//
+// The plural rules are the ones used by GNU gettext and the
+// Unicode CLDR project, written in the gettext C syntax.
+var locales = map[string]Locale{
+	"ach":   {Name: "Acholi", PluralForms: pluralsRomanic},
+	"af":    {Name: "Afrikaans", PluralForms: pluralsGermanic},
+	"ak":    {Name: "Akan", PluralForms: pluralsRomanic},
+	"am":    {Name: "Amharic", PluralForms: pluralsRomanic},
+	"an":    {Name: "Aragonese", PluralForms: pluralsGermanic},
+	"ar":    {Name: "Arabic", PluralForms: pluralsArabic},
+	"arn":   {Name: "Mapudungun", PluralForms: pluralsRomanic},
+	"as":    {Name: "Assamese", PluralForms: pluralsGermanic},
+	"ast":   {Name: "Asturian", PluralForms: pluralsGermanic},
+	"ay":    {Name: "Aymara", PluralForms: pluralsOnlyOne},
+	"az":    {Name: "Azerbaijani", PluralForms: pluralsGermanic},
+	"be":    {Name: "Belarusian", PluralForms: pluralsEastSlavic},
+	"bg":    {Name: "Bulgarian", PluralForms: pluralsGermanic},
+	"bn":    {Name: "Bengali", PluralForms: pluralsGermanic},
+	"bo":    {Name: "Tibetan", PluralForms: pluralsOnlyOne},
+	"br":    {Name: "Breton", PluralForms: pluralsRomanic},
+	"brx":   {Name: "Bodo", PluralForms: pluralsGermanic},
+	"bs":    {Name: "Bosnian", PluralForms: pluralsEastSlavic},
+	"ca":    {Name: "Catalan", PluralForms: pluralsGermanic},
+	"cgg":   {Name: "Chiga", PluralForms: pluralsOnlyOne},
+	"cs":    {Name: "Czech", PluralForms: pluralsCzechSlovak},
+	"csb":   {Name: "Kashubian", PluralForms: pluralsKashubian},
+	"cy":    {Name: "Welsh", PluralForms: pluralsWelsh},
+	"da":    {Name: "Danish", PluralForms: pluralsGermanic},
+	"de":    {Name: "German", PluralForms: pluralsGermanic},
+	"de_AT": {Name: "German (Austria)", PluralForms: pluralsGermanic},
+	"de_CH": {Name: "German (Switzerland)", PluralForms: pluralsGermanic},
+	"doi":   {Name: "Dogri", PluralForms: pluralsGermanic},
+	"dz":    {Name: "Dzongkha", PluralForms: pluralsOnlyOne},
+	"el":    {Name: "Greek", PluralForms: pluralsGermanic},
+	"en":    {Name: "English", PluralForms: pluralsGermanic},
+	"en_AU": {Name: "English (Australia)", PluralForms: pluralsGermanic},
+	"en_CA": {Name: "English (Canada)", PluralForms: pluralsGermanic},
+	"en_GB": {Name: "English (United Kingdom)", PluralForms: pluralsGermanic},
+	"en_US": {Name: "English (United States)", PluralForms: pluralsGermanic},
+	"eo":    {Name: "Esperanto", PluralForms: pluralsGermanic},
+	"es":    {Name: "Spanish", PluralForms: pluralsGermanic},
+	"es_AR": {Name: "Spanish (Argentina)", PluralForms: pluralsGermanic},
+	"es_ES": {Name: "Spanish (Spain)", PluralForms: pluralsGermanic},
+	"es_MX": {Name: "Spanish (Mexico)", PluralForms: pluralsGermanic},
+	"et":    {Name: "Estonian", PluralForms: pluralsGermanic},
+	"eu":    {Name: "Basque", PluralForms: pluralsGermanic},
+	"fa":    {Name: "Persian", PluralForms: pluralsRomanic},
+	"ff":    {Name: "Fulah", PluralForms: pluralsGermanic},
+	"fi":    {Name: "Finnish", PluralForms: pluralsGermanic},
+	"fil":   {Name: "Filipino", PluralForms: pluralsRomanic},
+	"fo":    {Name: "Faroese", PluralForms: pluralsGermanic},
+	"fr":    {Name: "French", PluralForms: pluralsRomanic},
+	"fr_CA": {Name: "French (Canada)", PluralForms: pluralsRomanic},
+	"fur":   {Name: "Friulian", PluralForms: pluralsGermanic},
+	"fy":    {Name: "Western Frisian", PluralForms: pluralsGermanic},
+	"ga":    {Name: "Irish", PluralForms: pluralsIrish},
+	"gd":    {Name: "Scottish Gaelic", PluralForms: pluralsScottishGaelic},
+	"gl":    {Name: "Galician", PluralForms: pluralsGermanic},
+	"gu":    {Name: "Gujarati", PluralForms: pluralsGermanic},
+	"gun":   {Name: "Gun", PluralForms: pluralsRomanic},
+	"ha":    {Name: "Hausa", PluralForms: pluralsGermanic},
+	"he":    {Name: "Hebrew", PluralForms: pluralsGermanic},
+	"hi":    {Name: "Hindi", PluralForms: pluralsGermanic},
+	"hne":   {Name: "Chhattisgarhi", PluralForms: pluralsGermanic},
+	"hr":    {Name: "Croatian", PluralForms: pluralsEastSlavic},
+	"hu":    {Name: "Hungarian", PluralForms: pluralsGermanic},
+	"hy":    {Name: "Armenian", PluralForms: pluralsGermanic},
+	"ia":    {Name: "Interlingua", PluralForms: pluralsGermanic},
+	"id":    {Name: "Indonesian", PluralForms: pluralsOnlyOne},
+	"is":    {Name: "Icelandic", PluralForms: pluralsIcelandic},
+	"it":    {Name: "Italian", PluralForms: pluralsGermanic},
+	"ja":    {Name: "Japanese", PluralForms: pluralsOnlyOne},
+	"jbo":   {Name: "Lojban", PluralForms: pluralsOnlyOne},
+	"jv":    {Name: "Javanese", PluralForms: pluralsJavanese},
+	"ka":    {Name: "Georgian", PluralForms: pluralsOnlyOne},
+	"kk":    {Name: "Kazakh", PluralForms: pluralsGermanic},
+	"kl":    {Name: "Greenlandic", PluralForms: pluralsGermanic},
+	"km":    {Name: "Khmer", PluralForms: pluralsOnlyOne},
+	"kn":    {Name: "Kannada", PluralForms: pluralsGermanic},
+	"ko":    {Name: "Korean", PluralForms: pluralsOnlyOne},
+	"ku":    {Name: "Kurdish", PluralForms: pluralsGermanic},
+	"kw":    {Name: "Cornish", PluralForms: pluralsCornish},
+	"ky":    {Name: "Kyrgyz", PluralForms: pluralsGermanic},
+	"lb":    {Name: "Luxembourgish", PluralForms: pluralsGermanic},
+	"ln":    {Name: "Lingala", PluralForms: pluralsRomanic},
+	"lo":    {Name: "Lao", PluralForms: pluralsOnlyOne},
+	"lt":    {Name: "Lithuanian", PluralForms: pluralsLithuanian},
+	"lv":    {Name: "Latvian", PluralForms: pluralsLatvian},
+	"mai":   {Name: "Maithili", PluralForms: pluralsGermanic},
+	"mfe":   {Name: "Mauritian Creole", PluralForms: pluralsRomanic},
+	"mg":    {Name: "Malagasy", PluralForms: pluralsRomanic},
+	"mi":    {Name: "Maori", PluralForms: pluralsRomanic},
+	"mk":    {Name: "Macedonian", PluralForms: pluralsMacedonian},
+	"ml":    {Name: "Malayalam", PluralForms: pluralsGermanic},
+	"mn":    {Name: "Mongolian", PluralForms: pluralsGermanic},
+	"mni":   {Name: "Manipuri", PluralForms: pluralsGermanic},
+	"mnk":   {Name: "Mandinka", PluralForms: pluralsMandinka},
+	"mr":    {Name: "Marathi", PluralForms: pluralsGermanic},
+	"ms":    {Name: "Malay", PluralForms: pluralsOnlyOne},
+	"mt":    {Name: "Maltese", PluralForms: pluralsMaltese},
+	"my":    {Name: "Burmese", PluralForms: pluralsOnlyOne},
+	"nah":   {Name: "Nahuatl", PluralForms: pluralsGermanic},
+	"nap":   {Name: "Neapolitan", PluralForms: pluralsGermanic},
+	"nb":    {Name: "Norwegian Bokmål", PluralForms: pluralsGermanic},
+	"ne":    {Name: "Nepali", PluralForms: pluralsGermanic},
+	"nl":    {Name: "Dutch", PluralForms: pluralsGermanic},
+	"nl_BE": {Name: "Dutch (Belgium)", PluralForms: pluralsGermanic},
+	"nn":    {Name: "Norwegian Nynorsk", PluralForms: pluralsGermanic},
+	"no":    {Name: "Norwegian", PluralForms: pluralsGermanic},
+	"nso":   {Name: "Northern Sotho", PluralForms: pluralsGermanic},
+	"oc":    {Name: "Occitan", PluralForms: pluralsRomanic},
+	"or":    {Name: "Odia", PluralForms: pluralsGermanic},
+	"pa":    {Name: "Punjabi", PluralForms: pluralsGermanic},
+	"pap":   {Name: "Papiamento", PluralForms: pluralsGermanic},
+	"pl":    {Name: "Polish", PluralForms: pluralsPolish},
+	"pms":   {Name: "Piedmontese", PluralForms: pluralsGermanic},
+	"ps":    {Name: "Pashto", PluralForms: pluralsGermanic},
+	"pt":    {Name: "Portuguese", PluralForms: pluralsGermanic},
+	"pt_BR": {Name: "Portuguese (Brazil)", PluralForms: pluralsRomanic},
+	"pt_PT": {Name: "Portuguese (Portugal)", PluralForms: pluralsGermanic},
+	"rm":    {Name: "Romansh", PluralForms: pluralsGermanic},
+	"ro":    {Name: "Romanian", PluralForms: pluralsRomanian},
+	"ru":    {Name: "Russian", PluralForms: pluralsEastSlavic},
+	"rw":    {Name: "Kinyarwanda", PluralForms: pluralsGermanic},
+	"sah":   {Name: "Yakut", PluralForms: pluralsOnlyOne},
+	"sat":   {Name: "Santali", PluralForms: pluralsGermanic},
+	"sco":   {Name: "Scots", PluralForms: pluralsGermanic},
+	"sd":    {Name: "Sindhi", PluralForms: pluralsGermanic},
+	"se":    {Name: "Northern Sami", PluralForms: pluralsGermanic},
+	"si":    {Name: "Sinhala", PluralForms: pluralsGermanic},
+	"sk":    {Name: "Slovak", PluralForms: pluralsCzechSlovak},
+	"sl":    {Name: "Slovenian", PluralForms: pluralsSlovenian},
+	"so":    {Name: "Somali", PluralForms: pluralsGermanic},
+	"son":   {Name: "Songhay", PluralForms: pluralsGermanic},
+	"sq":    {Name: "Albanian", PluralForms: pluralsGermanic},
+	"sr":    {Name: "Serbian", PluralForms: pluralsEastSlavic},
+	"su":    {Name: "Sundanese", PluralForms: pluralsOnlyOne},
+	"sv":    {Name: "Swedish", PluralForms: pluralsGermanic},
+	"sw":    {Name: "Swahili", PluralForms: pluralsGermanic},
+	"ta":    {Name: "Tamil", PluralForms: pluralsGermanic},
+	"te":    {Name: "Telugu", PluralForms: pluralsGermanic},
+	"tg":    {Name: "Tajik", PluralForms: pluralsRomanic},
+	"th":    {Name: "Thai", PluralForms: pluralsOnlyOne},
+	"ti":    {Name: "Tigrinya", PluralForms: pluralsRomanic},
+	"tk":    {Name: "Turkmen", PluralForms: pluralsGermanic},
+	"tr":    {Name: "Turkish", PluralForms: pluralsGermanic},
+	"tt":    {Name: "Tatar", PluralForms: pluralsOnlyOne},
+	"ug":    {Name: "Uyghur", PluralForms: pluralsOnlyOne},
+	"uk":    {Name: "Ukrainian", PluralForms: pluralsEastSlavic},
+	"ur":    {Name: "Urdu", PluralForms: pluralsGermanic},
+	"uz":    {Name: "Uzbek", PluralForms: pluralsRomanic},
+	"vi":    {Name: "Vietnamese", PluralForms: pluralsOnlyOne},
+	"wa":    {Name: "Walloon", PluralForms: pluralsRomanic},
+	"wo":    {Name: "Wolof", PluralForms: pluralsOnlyOne},
+	"yo":    {Name: "Yoruba", PluralForms: pluralsGermanic},
+	"zh":    {Name: "Chinese", PluralForms: pluralsOnlyOne},
+	"zh_CN": {Name: "Chinese (China)", PluralForms: pluralsOnlyOne},
+	"zh_HK": {Name: "Chinese (Hong Kong)", PluralForms: pluralsOnlyOne},
+	"zh_TW": {Name: "Chinese (Taiwan)", PluralForms: pluralsOnlyOne},
+}
+
+// normalizeLocaleCode converts codes like "pt-br", "pt_BR.UTF-8" or
+// "sr_RS@latin" to the "ll_TT" form used by the locale database.
+func normalizeLocaleCode(code string) string {
+	code = strings.TrimSpace(code)
+	if i := strings.IndexAny(code, ".@"); i != -1 {
+		code = code[:i]
+	}
+	code = strings.ReplaceAll(code, "-", "_")
+
+	lang, territory, found := strings.Cut(code, "_")
+	lang = strings.ToLower(lang)
+	if !found {
+		return lang
+	}
+	return lang + "_" + strings.ToUpper(territory)
+}
+
+// LookupLocale returns the locale that matches code.
+//
+// The code is an ISO 639 language code, optionally followed by a
+// territory, a charset and a modifier ("pt_BR", "pt-BR", "sr_RS.UTF-8@latin").
+// If the territory is unknown, the locale of the language is returned
+// with the code given.
+func LookupLocale(code string) (Locale, bool) {
+	normalized := normalizeLocaleCode(code)
+	if normalized == "" {
+		return Locale{}, false
+	}
+
+	locale, ok := locales[normalized]
+	if !ok {
+		lang, _, _ := strings.Cut(normalized, "_")
+		locale, ok = locales[lang]
+		if !ok {
+			return Locale{}, false
+		}
+	}
+
+	locale.Code = normalized
+	if locale.Charset == "" {
+		locale.Charset = defaultLocaleCharset
+	}
+
+	return locale, true
+}
+
+// PluralFormsFor returns the plural forms of the language identified by code.
+// See [LookupLocale] for the accepted codes.
+func PluralFormsFor(code string) (PluralForms, bool) {
+	locale, ok := LookupLocale(code)
+	return locale.PluralForms, ok
+}
+
+// CharsetFor returns the default charset for new catalogs of the language
+// identified by code, and UTF-8 if the language is unknown.
+// See [LookupLocale] for the accepted codes.
+func CharsetFor(code string) (string, bool) {
+	locale, ok := LookupLocale(code)
+	if !ok {
+		return defaultLocaleCharset, false
+	}
+	return locale.Charset, true
+}
+
+// Locales returns all the locales of the built-in database.
+func Locales() []Locale {
+	list := make([]Locale, 0, len(locales))
+	for code := range locales {
+		locale, _ := LookupLocale(code)
+		list = append(list, locale)
+	}
+	slices.SortFunc(list, func(a, b Locale) int { return strings.Compare(a.Code, b.Code) })
+
+	return list
+}
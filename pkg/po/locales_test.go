@@ -0,0 +1,86 @@
+package po_test
+
+import (
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+)
+
+func TestLocalesPluralForms(t *testing.T) {
+	for _, locale := range po.Locales() {
+		f, err := po.CompilePluralForms(locale.PluralForms.Plural)
+		if err != nil {
+			t.Errorf("%s: %v", locale.Code, err)
+			continue
+		}
+
+		seen := make(map[int]bool)
+		for n := uint64(0); n < 1000; n++ {
+			i := f(n)
+			if i < 0 || uint(i) >= locale.PluralForms.Nplurals {
+				t.Errorf("%s: index %d for n=%d is out of nplurals", locale.Code, i, n)
+				break
+			}
+			seen[i] = true
+		}
+		if uint(len(seen)) != locale.PluralForms.Nplurals {
+			t.Errorf("%s: only %d of %d plural forms are used", locale.Code, len(seen), locale.PluralForms.Nplurals)
+		}
+	}
+}
+
+func TestLookupLocale(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+		nplurals uint
+		found    bool
+	}{
+		{"pl", "pl", 3, true},
+		{"pt_BR", "pt_BR", 2, true},
+		{"pt-br", "pt_BR", 2, true},
+		{"sr_RS.UTF-8@latin", "sr_RS", 3, true},
+		{"ar_EG", "ar_EG", 6, true},
+		{"xx", "", 0, false},
+		{" ", "", 0, false},
+	}
+
+	for _, test := range tests {
+		locale, ok := po.LookupLocale(test.code)
+		if ok != test.found || locale.Code != test.expected || locale.PluralForms.Nplurals != test.nplurals {
+			t.Errorf("%q: unexpected result %+v (%v)", test.code, locale, ok)
+		}
+	}
+
+	if forms, _ := po.PluralFormsFor("pt_BR"); forms.Plural != "(n > 1)" {
+		t.Errorf("unexpected plural forms for pt_BR: %s", forms)
+	}
+	if charset, ok := po.CharsetFor("uk"); !ok || charset != "UTF-8" {
+		t.Errorf("unexpected charset for uk: %s", charset)
+	}
+	if charset, ok := po.CharsetFor("xx"); ok || charset != "UTF-8" {
+		t.Errorf("unexpected charset for an unknown language: %s", charset)
+	}
+}
+
+func TestHeaderWithLanguagePluralForms(t *testing.T) {
+	cfg := po.DefaultHeaderConfig(po.HeaderWithLanguagePluralForms("ru"))
+	if cfg.Language != "ru" || cfg.Nplurals != 3 {
+		t.Errorf("unexpected header config: %s", cfg)
+	}
+
+	cfg = po.HeaderConfig{}
+	po.HeaderWithLanguagePluralForms("pl")(&cfg)
+	if cfg.Charset != "UTF-8" {
+		t.Errorf("the charset of the language wasn't filled: %s", cfg)
+	}
+	cfg = po.DefaultTemplateHeaderConfig(po.HeaderWithLanguagePluralForms("pl"))
+	if cfg.Charset != "CHARSET" {
+		t.Errorf("the charset of a template was replaced: %s", cfg)
+	}
+
+	cfg = po.DefaultHeaderConfig(po.HeaderWithLanguagePluralForms("unknown"))
+	if cfg.Nplurals != 2 || cfg.Plural != "(n != 1)" {
+		t.Errorf("the default plural forms must be kept: %s", cfg)
+	}
+}
@@ -1,6 +1,10 @@
 package po
 
 import (
+	"strconv"
+	"strings"
+	"unicode"
+
 	"github.com/Tom5521/gotext-tools/v2/internal/slices"
 	"github.com/Tom5521/gotext-tools/v2/internal/util"
 )
@@ -19,6 +23,89 @@ func (l Location) Equal(l2 Location) bool {
 	return util.Equal(l, l2)
 }
 
+// Unicode isolation marks used by gettext to enclose file names with spaces.
+const (
+	FirstStrongIsolate    = '\u2068'
+	PopDirectionalIsolate = '\u2069'
+)
+
+// Reference returns the location formatted as in a "#:" comment. The line
+// is omitted if withLine is false or if it is negative, and file names with
+// spaces are enclosed in Unicode isolation marks like GNU gettext does.
+func (l Location) Reference(withLine bool) string {
+	file := l.File
+	if strings.IndexFunc(file, unicode.IsSpace) != -1 {
+		file = string(FirstStrongIsolate) + file + string(PopDirectionalIsolate)
+	}
+	if !withLine || l.Line < 0 {
+		return file
+	}
+
+	return file + ":" + strconv.Itoa(l.Line)
+}
+
+// ParseReferences parses the content of a "#:" comment, which can hold
+// several whitespace separated references. References without a line number
+// get -1 as line, and file names enclosed in Unicode isolation marks may
+// contain spaces.
+func ParseReferences(s string) Locations {
+	var locations Locations
+
+	for {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		if s == "" {
+			return locations
+		}
+
+		var file string
+		if strings.HasPrefix(s, string(FirstStrongIsolate)) {
+			s = strings.TrimPrefix(s, string(FirstStrongIsolate))
+			end := strings.IndexRune(s, PopDirectionalIsolate)
+			if end == -1 {
+				end = len(s)
+			}
+			file = s[:end]
+			s = strings.TrimPrefix(s[end:], string(PopDirectionalIsolate))
+
+			line := -1
+			if strings.HasPrefix(s, ":") {
+				end = strings.IndexFunc(s, unicode.IsSpace)
+				if end == -1 {
+					end = len(s)
+				}
+				if n, err := strconv.Atoi(s[1:end]); err == nil {
+					line = n
+					s = s[end:]
+				}
+			}
+			locations = append(locations, Location{File: file, Line: line})
+			continue
+		}
+
+		end := strings.IndexFunc(s, unicode.IsSpace)
+		if end == -1 {
+			end = len(s)
+		}
+		locations = append(locations, parseReference(s[:end]))
+		s = s[end:]
+	}
+}
+
+// parseReference parses a single "file:line" reference. Only the text after
+// the last colon is taken as line, so Windows drive letters are kept.
+func parseReference(ref string) Location {
+	i := strings.LastIndexByte(ref, ':')
+	if i == -1 {
+		return Location{File: ref, Line: -1}
+	}
+	line, err := strconv.Atoi(ref[i+1:])
+	if err != nil || line < 0 || ref[i+1] == '+' {
+		return Location{File: ref, Line: -1}
+	}
+
+	return Location{File: ref[:i], Line: line}
+}
+
 type Locations []Location
 
 func (l Locations) String() string {
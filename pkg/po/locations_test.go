@@ -30,3 +30,53 @@ func TestSortLocations(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestParseReferences(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected po.Locations
+	}{
+		{"a.go:10 b.go:22", po.Locations{{File: "a.go", Line: 10}, {File: "b.go", Line: 22}}},
+		{"a.go  b.go:3\t", po.Locations{{File: "a.go", Line: -1}, {File: "b.go", Line: 3}}},
+		{`C:\src\main.c:7 D:\x.c`, po.Locations{{File: `C:\src\main.c`, Line: 7}, {File: `D:\x.c`, Line: -1}}},
+		{
+			"\u2068my file.go\u2069:5 \u2068other file.go\u2069 c.go:1",
+			po.Locations{
+				{File: "my file.go", Line: 5},
+				{File: "other file.go", Line: -1},
+				{File: "c.go", Line: 1},
+			},
+		},
+		{"", nil},
+	}
+
+	for _, test := range tests {
+		got := po.ParseReferences(test.input)
+		if !util.Equal(got, test.expected) {
+			t.Errorf("%q: expected %v, got %v", test.input, test.expected, got)
+		}
+	}
+}
+
+func TestLocationReference(t *testing.T) {
+	tests := []struct {
+		location po.Location
+		withLine bool
+		expected string
+	}{
+		{po.Location{File: "a.go", Line: 3}, true, "a.go:3"},
+		{po.Location{File: "a.go", Line: 3}, false, "a.go"},
+		{po.Location{File: "a.go", Line: -1}, true, "a.go"},
+		{po.Location{File: "my file.go", Line: 1}, true, "\u2068my file.go\u2069:1"},
+	}
+
+	for _, test := range tests {
+		got := test.location.Reference(test.withLine)
+		if got != test.expected {
+			t.Errorf("expected %q, got %q", test.expected, got)
+		}
+		if parsed := po.ParseReferences(got); len(parsed) != 1 || parsed[0].File != test.location.File {
+			t.Errorf("%q doesn't round trip: %v", got, parsed)
+		}
+	}
+}
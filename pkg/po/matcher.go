@@ -0,0 +1,375 @@
+package po
+
+import (
+	"math/bits"
+	"unicode/utf8"
+
+	"github.com/Tom5521/gotext-tools/v2/internal/slices"
+
+	fuzzy "github.com/paul-mannino/go-fuzzywuzzy"
+)
+
+// DefaultFuzzyThreshold is the minimum similarity of fuzzy matches used
+// when [MergeConfig.Threshold] is zero, the same as GNU msgmerge.
+const DefaultFuzzyThreshold = 60
+
+// Matcher measures how similar the messages of two entries are, from 0
+// (unrelated) to 100 (equal), to find the fuzzy matches of a merge.
+type Matcher interface {
+	Similarity(e1, e2 Entry) int
+}
+
+// PrefilterMatcher is implemented by the matchers that can discard most
+// of the candidates of a message without comparing them, which keeps the
+// merge of large catalogs fast.
+type PrefilterMatcher interface {
+	Matcher
+	// MaxSimilarity returns an upper bound of the similarity of two
+	// messages whose msgids have len1 and len2 runes.
+	MaxSimilarity(len1, len2 int) int
+	// NgramSize returns the length in runes of the n-grams that the msgids
+	// of two messages must share to be considered similar, or 0 if they
+	// don't need to share any.
+	NgramSize() int
+}
+
+var (
+	_ PrefilterMatcher = FstrcmpMatcher{}
+	_ PrefilterMatcher = ExactMatcher{}
+	_ Matcher          = TokenSetMatcher{}
+	_ Matcher          = RatioMatcher{}
+)
+
+// FstrcmpMatcher compares the msgids like the fstrcmp function of GNU
+// gettext: the similarity is the number of runes of their longest common
+// subsequence divided by their average length. Messages with different
+// contexts are slightly less similar than the ones with the same context.
+//
+// To stay fast on large catalogs, like GNU msgmerge does, only the msgids
+// that share a 3-gram, or that are shorter than one, are compared. So
+// messages without three consecutive runes in common are never matched.
+type FstrcmpMatcher struct{}
+
+func (FstrcmpMatcher) Similarity(e1, e2 Entry) int {
+	similarity := fstrcmp(e1.ID, e2.ID)
+	if e1.Context != e2.Context {
+		similarity *= 0.99
+	}
+
+	return int(similarity * 100)
+}
+
+func (FstrcmpMatcher) MaxSimilarity(len1, len2 int) int {
+	if len1+len2 == 0 {
+		return 100
+	}
+	if len1 > len2 {
+		len1, len2 = len2, len1
+	}
+	return 200 * len1 / (len1 + len2)
+}
+
+func (FstrcmpMatcher) NgramSize() int {
+	return 3
+}
+
+// fstrcmp returns the similarity of a and b, from 0 to 1, as twice the
+// length of their longest common subsequence divided by the sum of their lengths.
+func fstrcmp(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra)+len(rb) == 0 {
+		return 1
+	}
+
+	return float64(2*lcsLength(ra, rb)) / float64(len(ra)+len(rb))
+}
+
+// lcsLength returns the length of the longest common subsequence of a and
+// b with the bit-parallel algorithm of Hyyrö, which processes 64 runes of
+// the shorter string at once.
+func lcsLength(a, b []rune) int {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	if len(b) == 0 {
+		return 0
+	}
+
+	// The bit i of the masks of a rune is set if b[i] is that rune.
+	// ASCII runes, the most common ones, are kept in an array.
+	words := (len(b) + 63) / 64
+	var ascii [utf8.RuneSelf][]uint64
+	others := make(map[rune][]uint64)
+	masks := make([]uint64, 0, words*len(b))
+	mask := func(r rune, create bool) []uint64 {
+		var m []uint64
+		if r < utf8.RuneSelf {
+			m = ascii[r]
+		} else {
+			m = others[r]
+		}
+		if m != nil || !create {
+			return m
+		}
+
+		masks = masks[:len(masks)+words]
+		m = masks[len(masks)-words:]
+		if r < utf8.RuneSelf {
+			ascii[r] = m
+		} else {
+			others[r] = m
+		}
+		return m
+	}
+	for i, r := range b {
+		mask(r, true)[i/64] |= 1 << (i % 64)
+	}
+
+	v := make([]uint64, words)
+	for i := range v {
+		v[i] = ^uint64(0)
+	}
+	for _, r := range a {
+		m := mask(r, false)
+		if m == nil {
+			continue
+		}
+		var carry uint64
+		for i := range v {
+			u := v[i] & m[i]
+			var sum uint64
+			sum, carry = bits.Add64(v[i], u, carry)
+			v[i] = sum | (v[i] - u)
+		}
+	}
+
+	// The length is the number of zero bits in the first len(b) bits.
+	var ones int
+	for i, w := range v {
+		if i == words-1 && len(b)%64 != 0 {
+			w &= 1<<(len(b)%64) - 1
+		}
+		ones += bits.OnesCount64(w)
+	}
+
+	return len(b) - ones
+}
+
+// TokenSetMatcher compares the sets of words of the msgids, ignoring
+// their order, case and punctuation, so "Open file" and "File: open"
+// are equal. It's useful for longer messages whose words are reordered.
+type TokenSetMatcher struct{}
+
+func (TokenSetMatcher) Similarity(e1, e2 Entry) int {
+	if e1.ID == e2.ID {
+		return 100
+	}
+	return fuzzy.TokenSetRatio(e1.ID, e2.ID, false, true)
+}
+
+// ExactMatcher only matches the messages with the same msgid and context,
+// so merges don't create fuzzy entries.
+type ExactMatcher struct{}
+
+func (ExactMatcher) Similarity(e1, e2 Entry) int {
+	if e1.ID == e2.ID && e1.Context == e2.Context {
+		return 100
+	}
+	return 0
+}
+
+func (ExactMatcher) MaxSimilarity(len1, len2 int) int {
+	if len1 == len2 {
+		return 100
+	}
+	return 0
+}
+
+func (ExactMatcher) NgramSize() int {
+	return 0
+}
+
+// RatioMatcher is the average of the Levenshtein ratios of the msgids,
+// contexts and plural msgids, see [EntryIDMatchRatio].
+type RatioMatcher struct{}
+
+func (RatioMatcher) Similarity(e1, e2 Entry) int {
+	return EntryIDMatchRatio(e1, e2)
+}
+
+// FuzzyIndex finds the most similar entries to a message among a set of
+// candidates, skipping the ones that can't reach the threshold when the
+// matcher is a [PrefilterMatcher].
+//
+// A FuzzyIndex must not be used concurrently.
+type FuzzyIndex struct {
+	entries   Entries
+	matcher   Matcher
+	prefilter PrefilterMatcher
+	threshold int
+
+	lengths []int            // Length in runes of the msgid of each entry.
+	ngrams  map[string][]int // Entries that contain each n-gram.
+	short   []int            // Entries with msgids shorter than an n-gram.
+	marks   []int            // Last query that visited each entry.
+	query   int
+}
+
+// FuzzyMatch is an entry found by [FuzzyIndex.Search].
+type FuzzyMatch struct {
+	Index      int // Position of the entry in the indexed entries.
+	Similarity int
+}
+
+// NewFuzzyIndex returns a FuzzyIndex of entries that finds the ones whose
+// similarity to a message, according to matcher, is at least threshold.
+// The header is never matched. The entries must not be modified while
+// the index is used.
+func NewFuzzyIndex(entries Entries, matcher Matcher, threshold int) *FuzzyIndex {
+	fi := &FuzzyIndex{
+		entries:   entries,
+		matcher:   matcher,
+		threshold: threshold,
+		lengths:   make([]int, len(entries)),
+		marks:     make([]int, len(entries)),
+	}
+	fi.prefilter, _ = matcher.(PrefilterMatcher)
+	for i, e := range entries {
+		fi.lengths[i] = utf8.RuneCountInString(e.ID)
+	}
+
+	if fi.prefilter == nil || fi.prefilter.NgramSize() <= 0 {
+		return fi
+	}
+
+	n := fi.prefilter.NgramSize()
+	fi.ngrams = make(map[string][]int)
+	for i, e := range entries {
+		if e.IsHeader() {
+			continue
+		}
+		grams := ngrams(e.ID, n)
+		if len(grams) == 0 {
+			fi.short = append(fi.short, i)
+		}
+		for _, g := range grams {
+			postings := fi.ngrams[g]
+			if len(postings) == 0 || postings[len(postings)-1] != i {
+				fi.ngrams[g] = append(postings, i)
+			}
+		}
+	}
+
+	return fi
+}
+
+// ngrams returns the n-grams of s, in order and with duplicates.
+func ngrams(s string, n int) []string {
+	runes := []rune(s)
+	if n <= 0 || len(runes) < n {
+		return nil
+	}
+
+	grams := make([]string, 0, len(runes)-n+1)
+	for i := 0; i+n <= len(runes); i++ {
+		grams = append(grams, string(runes[i:i+n]))
+	}
+	return grams
+}
+
+// candidates calls visit once with the index of each entry that may reach
+// the threshold, and with the upper bound of its similarity to e.
+func (fi *FuzzyIndex) candidates(e Entry, visit func(i, bound int)) {
+	fi.query++
+	length := utf8.RuneCountInString(e.ID)
+
+	try := func(i int) {
+		if fi.marks[i] == fi.query {
+			return
+		}
+		fi.marks[i] = fi.query
+
+		if fi.entries[i].IsHeader() {
+			return
+		}
+		bound := 100
+		if fi.prefilter != nil {
+			bound = fi.prefilter.MaxSimilarity(length, fi.lengths[i])
+			if bound < fi.threshold {
+				return
+			}
+		}
+		visit(i, bound)
+	}
+
+	grams := ngrams(e.ID, fi.ngramSize())
+	if fi.ngrams == nil || len(grams) == 0 {
+		for i := range fi.entries {
+			try(i)
+		}
+		return
+	}
+
+	for _, g := range grams {
+		for _, i := range fi.ngrams[g] {
+			try(i)
+		}
+	}
+	for _, i := range fi.short {
+		try(i)
+	}
+}
+
+// Best returns the index of the most similar entry to e and its similarity,
+// and false if no entry reaches the threshold. Ties are resolved in favor
+// of the first entry.
+func (fi *FuzzyIndex) Best(e Entry) (int, int, bool) {
+	return fi.best(e, nil)
+}
+
+// best is like Best, but ignores the entries for which skip returns true.
+func (fi *FuzzyIndex) best(e Entry, skip func(i int) bool) (int, int, bool) {
+	best, bestSimilarity := -1, -1
+	fi.candidates(e, func(i, bound int) {
+		if bound < bestSimilarity || skip != nil && skip(i) {
+			return
+		}
+		similarity := fi.matcher.Similarity(e, fi.entries[i])
+		if similarity < fi.threshold {
+			return
+		}
+		if similarity > bestSimilarity || similarity == bestSimilarity && i < best {
+			best, bestSimilarity = i, similarity
+		}
+	})
+
+	return best, bestSimilarity, best != -1
+}
+
+// Search returns the entries whose similarity to e reaches the threshold,
+// from the most to the least similar, and in their order when they're
+// equally similar.
+func (fi *FuzzyIndex) Search(e Entry) []FuzzyMatch {
+	var matches []FuzzyMatch
+	fi.candidates(e, func(i, _ int) {
+		if similarity := fi.matcher.Similarity(e, fi.entries[i]); similarity >= fi.threshold {
+			matches = append(matches, FuzzyMatch{Index: i, Similarity: similarity})
+		}
+	})
+
+	slices.SortFunc(matches, func(a, b FuzzyMatch) int {
+		if a.Similarity != b.Similarity {
+			return b.Similarity - a.Similarity
+		}
+		return a.Index - b.Index
+	})
+
+	return matches
+}
+
+func (fi *FuzzyIndex) ngramSize() int {
+	if fi.prefilter == nil {
+		return 0
+	}
+	return fi.prefilter.NgramSize()
+}
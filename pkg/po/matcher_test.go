@@ -0,0 +1,111 @@
+package po_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+)
+
+func TestFstrcmpMatcher(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"Open", "Open", 100},
+		{"Open the file", "Open the files", 96},
+		{"Open", "Close", 22},
+		{"", "", 100},
+		{"abc", "", 0},
+	}
+
+	var m po.FstrcmpMatcher
+	for _, test := range tests {
+		got := m.Similarity(po.Entry{ID: test.a}, po.Entry{ID: test.b})
+		if got != test.expected {
+			t.Errorf("%q, %q: expected %d, got %d", test.a, test.b, test.expected, got)
+		}
+		if bound := m.MaxSimilarity(len([]rune(test.a)), len([]rune(test.b))); bound < got {
+			t.Errorf("%q, %q: bound %d is lower than the similarity %d", test.a, test.b, bound, got)
+		}
+	}
+
+	same := m.Similarity(po.Entry{ID: "Open the file"}, po.Entry{ID: "Open the files"})
+	other := m.Similarity(po.Entry{ID: "Open the file"}, po.Entry{ID: "Open the files", Context: "menu"})
+	if other >= same {
+		t.Errorf("a different context must lower the similarity: %d >= %d", other, same)
+	}
+}
+
+// constMatcher finds all the messages equally similar.
+type constMatcher struct{}
+
+func (constMatcher) Similarity(e1, e2 po.Entry) int {
+	return 100
+}
+
+func TestMergeMatcher(t *testing.T) {
+	def := po.Entries{
+		{ID: "Open", Str: "Abrir"},
+		{ID: "Save the document", Str: "Guardar el documento"},
+	}
+	ref := po.Entries{
+		{ID: "Close"},
+		{ID: "Save the documents"},
+	}
+
+	merged := po.Merge(def, ref, po.MergeWithSort(false))
+	if i := merged.Index("Close", ""); i == -1 || merged[i].IsFuzzy() || merged[i].Str != "" {
+		t.Errorf("\"Close\" must not be matched with \"Open\": %v", merged)
+	}
+	if i := merged.Index("Save the documents", ""); i == -1 || !merged[i].IsFuzzy() ||
+		merged[i].Str != "Guardar el documento" {
+		t.Errorf("expected a fuzzy match: %v", merged)
+	}
+
+	merged = po.Merge(def, ref, po.MergeWithSort(false), po.MergeWithThreshold(99))
+	if i := merged.Index("Save the documents", ""); i == -1 || merged[i].IsFuzzy() {
+		t.Errorf("unexpected fuzzy match over the threshold: %v", merged)
+	}
+
+	merged = po.Merge(def, ref, po.MergeWithSort(false), po.MergeWithMatcher(po.ExactMatcher{}))
+	for _, e := range merged {
+		if e.IsFuzzy() {
+			t.Errorf("unexpected fuzzy entry with ExactMatcher: %v", e)
+		}
+	}
+
+	merged = po.Merge(def, ref, po.MergeWithSort(false), po.MergeWithMatcher(constMatcher{}))
+	if i := merged.Index("Close", ""); i == -1 || !merged[i].IsFuzzy() {
+		t.Errorf("the custom matcher wasn't used: %v", merged)
+	}
+
+	tokens := po.TokenSetMatcher{}
+	if got := tokens.Similarity(po.Entry{ID: "Open file"}, po.Entry{ID: "File: open"}); got != 100 {
+		t.Errorf("expected equal token sets, got %d", got)
+	}
+}
+
+func BenchmarkMergeFuzzy(b *testing.B) {
+	words := []string{"open", "close", "file", "folder", "save", "print", "document", "window", "search", "settings"}
+	message := func(i int) string {
+		n := len(words)
+		return fmt.Sprintf("%s the %s %d with %s", words[i%n], words[i/n%n], i, words[i*7%n])
+	}
+
+	// A tenth of the messages changed in the new catalog.
+	var def, ref po.Entries
+	for i := 0; i < 2000; i++ {
+		def = append(def, po.Entry{ID: message(i), Str: fmt.Sprintf("Mensaje %d", i)})
+		if i%10 == 0 {
+			ref = append(ref, po.Entry{ID: message(i) + " now"})
+		} else {
+			ref = append(ref, po.Entry{ID: message(i)})
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		po.Merge(def, ref, po.MergeWithSort(false))
+	}
+}
@@ -1,5 +1,11 @@
 package po
 
+import (
+	"strings"
+
+	"github.com/Tom5521/gotext-tools/v2/internal/slices"
+)
+
 // SortMode defines the mode used to sort PO entries.
 type SortMode int
 
@@ -30,20 +36,56 @@ func (mode SortMode) SortMethod(entries Entries) func() Entries {
 	return entries.PrepareSorter(method)
 }
 
+// TranslationMemory provides translations of messages for [MergeWithConfig],
+// see the tm package.
+type TranslationMemory interface {
+	// Suggest returns the most similar message to e translated to lang, and
+	// its similarity from 0 to 100, if the similarity reaches threshold.
+	// A similarity of 100 means that the message is the same as e.
+	Suggest(e Entry, lang string, threshold int) (Entry, int, bool)
+}
+
 // MergeConfig specifies options for merging entry sets.
 type MergeConfig struct {
 	FuzzyMatch      bool     // Enables fuzzy matching of entries.
 	KeepPreviousIDs bool     // Retains original IDs on unmatched entries.
+	Previous        bool     // Records the previous message of fuzzy matched entries.
 	Sort            bool     // Enables sorting after merge.
 	SortMode        SortMode // Sort order to use if sorting is enabled.
+	// Matcher measures the similarity of the messages for fuzzy matching,
+	// [FstrcmpMatcher] is used if nil.
+	Matcher Matcher
+	// Threshold is the minimum similarity, from 1 to 100, of fuzzy matches.
+	// [DefaultFuzzyThreshold] is used if zero.
+	Threshold int
+	// StampRevision updates the PO-Revision-Date of the header of def when
+	// the merge changes its entries, see [Entries.StampRevision].
+	StampRevision bool
+	// LastTranslator replaces the Last-Translator field when the revision is stamped.
+	LastTranslator string
+	// Report, if not nil, is filled with the outcome of each message.
+	Report *MergeReport
+	// HeaderPolicy selects how the header of ref is merged into def.
+	HeaderPolicy HeaderPolicy
+	// Memory, if not nil, provides translations for the messages of ref
+	// that aren't in def, like the compendiums of GNU msgmerge. Its
+	// translations are looked up in the Language of the header of def, or
+	// else in Language. The Memory isn't used if both are empty.
+	Memory TranslationMemory
+	// Language fills the Language field of the merged header if it's missing.
+	// The Plural-Forms field is filled from the language if it's missing.
+	// Both are ignored with [HeaderKeep].
+	Language string
 }
 
 // DefaultMergeConfig returns a MergeConfig with default values, optionally modified by MergeOptions.
 func DefaultMergeConfig(opts ...MergeOption) MergeConfig {
 	cfg := MergeConfig{
-		FuzzyMatch: true,
-		Sort:       true,
-		SortMode:   SortByAll,
+		FuzzyMatch:   true,
+		Previous:     true,
+		Sort:         true,
+		SortMode:     SortByAll,
+		HeaderPolicy: HeaderUpdate,
 	}
 	cfg.ApplyOption(opts...)
 	return cfg
@@ -84,80 +126,375 @@ func MergeWithKeepPreviousIDs(k bool) MergeOption {
 	return func(mc *MergeConfig) { mc.KeepPreviousIDs = k }
 }
 
+// MergeWithPrevious returns a MergeOption that enables or disables recording
+// the previous msgctxt, msgid and msgid_plural of fuzzy matched entries.
+func MergeWithPrevious(p bool) MergeOption {
+	return func(mc *MergeConfig) { mc.Previous = p }
+}
+
+// MergeWithMatcher returns a MergeOption that sets the Matcher used for fuzzy matching.
+func MergeWithMatcher(m Matcher) MergeOption {
+	return func(mc *MergeConfig) { mc.Matcher = m }
+}
+
+// MergeWithThreshold returns a MergeOption that sets the minimum similarity of fuzzy matches.
+func MergeWithThreshold(t int) MergeOption {
+	return func(mc *MergeConfig) { mc.Threshold = t }
+}
+
+// MergeWithStampRevision returns a MergeOption that enables or disables
+// updating the PO-Revision-Date when the entries change.
+func MergeWithStampRevision(s bool) MergeOption {
+	return func(mc *MergeConfig) { mc.StampRevision = s }
+}
+
+// MergeWithReport returns a MergeOption that sets the MergeReport filled by the merge.
+func MergeWithReport(r *MergeReport) MergeOption {
+	return func(mc *MergeConfig) { mc.Report = r }
+}
+
+// MergeWithMemory returns a MergeOption that sets the TranslationMemory used for the new messages.
+func MergeWithMemory(m TranslationMemory) MergeOption {
+	return func(mc *MergeConfig) { mc.Memory = m }
+}
+
+// MergeWithHeaderPolicy returns a MergeOption that sets the HeaderPolicy.
+func MergeWithHeaderPolicy(p HeaderPolicy) MergeOption {
+	return func(mc *MergeConfig) { mc.HeaderPolicy = p }
+}
+
+// MergeWithLanguage returns a MergeOption that sets the Language filled in the header.
+func MergeWithLanguage(lang string) MergeOption {
+	return func(mc *MergeConfig) { mc.Language = lang }
+}
+
+// MergeWithLastTranslator returns a MergeOption that sets the Last-Translator
+// written when the revision is stamped.
+func MergeWithLastTranslator(t string) MergeOption {
+	return func(mc *MergeConfig) { mc.LastTranslator = t }
+}
+
 // NOTE: This thing is slightly broken.
 
 // MergeWithConfig merges entries from def and ref using the provided MergeConfig.
 //
-// If FuzzyMatch is enabled, unmatched entries may be matched based on string similarity.
+// The entries of def that match an entry of ref keep their translations and
+// translator comments, and take the extracted comments, references, flags
+// and msgid_plural of ref.
+// If FuzzyMatch is enabled, unmatched entries may be matched with the most
+// similar message according to the Matcher, if its similarity reaches the Threshold
+// and no other entry of def matches it.
 // If KeepPreviousIDs is set, original IDs are preserved in unmatched entries.
+// If Memory is set and the language of def is known, the messages of ref that
+// aren't in def take their translation from it if it has the same message or,
+// with FuzzyMatch, a more similar one than def.
+// If Previous is set, fuzzy matched entries get the message they were matched
+// with in [Entry.Previous].
 // If Sort is enabled, the resulting set is sorted using the given SortMode.
+// The header is merged following the HeaderPolicy.
+// If StampRevision is enabled and the messages of def changed, the revision
+// of its header is updated.
+// If Report is set, it's replaced with the outcome of the merge.
 func MergeWithConfig(config MergeConfig, def, ref Entries) Entries {
 	// NOTE: The implicit memory aliasing in loops
 	// are intentional, don't try to change it.
 
+	if config.Report != nil {
+		*config.Report = MergeReport{}
+	}
+
 	nplurals := int(ref.Header().Nplurals())
+	defNplurals := int(def.Header().Nplurals())
+	original := slices.Clone(def)
 	def = def.Solve()
 
+	suggest := config.suggester(def)
+	refIndex := ref.Indexed()
+	refFuzzy := config.fuzzyIndex(ref)
+	// Like msgmerge, only the messages of ref that no entry of def matches
+	// exactly can be fuzzy matched, and each one only once, so the merged
+	// entries don't repeat a message.
+	claimed := make([]bool, len(ref))
+	for _, entry := range def {
+		if i := refIndex.IndexByUnifiedID(entry.UnifiedID()); i != -1 && !entry.IsHeader() {
+			claimed[i] = true
+		}
+	}
 	for i, entry := range def {
-		if mergeDef(config, &entry, ref) {
+		if mergeDef(config, &entry, ref, refIndex, refFuzzy, claimed, suggest, defNplurals) {
 			continue
 		}
 		def[i] = entry
 	}
 
+	// The def IDs may have changed by fuzzy matching,
+	// so the index must be built after merging them.
+	defIndex := def.Indexed()
+	defFuzzy := config.fuzzyIndex(def)
 	for _, entry := range ref {
-
-		if mergeRef(config, &entry, def, nplurals) {
+		if mergeRef(config, &entry, def, defIndex, defFuzzy, suggest, nplurals) {
 			continue
 		}
+		defIndex.add(entry.UnifiedID(), len(def))
 		def = append(def, entry)
 	}
 
+	def = config.mergeHeader(def, ref)
+
 	if config.Sort {
 		def = config.SortMode.SortMethod(def)()
 	}
 
+	// The changes of the header alone, like a new POT-Creation-Date, aren't a new revision.
+	changed := slices.ContainsFunc(DiffEntries(original, def), func(d EntryDiff) bool {
+		return !d.Entry().IsHeader()
+	})
+	if config.StampRevision && def.HasHeader() && changed {
+		def.StampRevision(HeaderNow(), config.LastTranslator)
+	}
+
 	return def
 }
 
-func mergeRef(config MergeConfig, entry *Entry, def Entries, nplurals int) bool {
-	if def.ContainsUnifiedID(entry.UnifiedID()) || entry.IsHeader() {
+// fuzzyIndex returns the index used to find the fuzzy matches among
+// entries, or nil if fuzzy matching is disabled.
+func (m MergeConfig) fuzzyIndex(entries Entries) *FuzzyIndex {
+	if !m.FuzzyMatch {
+		return nil
+	}
+
+	matcher := m.Matcher
+	if matcher == nil {
+		matcher = FstrcmpMatcher{}
+	}
+
+	return NewFuzzyIndex(entries, matcher, m.threshold())
+}
+
+// threshold returns the minimum similarity of the fuzzy matches, which
+// is 100, only the same messages, if fuzzy matching is disabled.
+func (m MergeConfig) threshold() int {
+	switch {
+	case !m.FuzzyMatch:
+		return 100
+	case m.Threshold == 0:
+		return DefaultFuzzyThreshold
+	}
+	return m.Threshold
+}
+
+// suggestFunc looks up the translation of a message in the TranslationMemory of a merge.
+type suggestFunc func(e Entry, threshold int) (Entry, int, bool)
+
+// suggester returns the lookup in the Memory for the language of def, or
+// nil if there is no Memory or the language is unknown, since translations
+// to any other language would be suggested.
+func (m MergeConfig) suggester(def Entries) suggestFunc {
+	if m.Memory == nil {
+		return nil
+	}
+
+	header := def.Header()
+	lang := strings.TrimSpace(header.Load("Language"))
+	if lang == "" {
+		lang = m.Language
+	}
+	if lang == "" {
+		return nil
+	}
+	return func(e Entry, threshold int) (Entry, int, bool) {
+		return m.Memory.Suggest(e, lang, threshold)
+	}
+}
+
+func (s suggestFunc) lookup(e Entry, threshold int) (Entry, int, bool) {
+	if s == nil {
+		return Entry{}, 0, false
+	}
+	return s(e, threshold)
+}
+
+func mergeRef(
+	config MergeConfig,
+	entry *Entry,
+	def Entries,
+	defIndex EntryIndex,
+	defFuzzy *FuzzyIndex,
+	suggest suggestFunc,
+	nplurals int,
+) bool {
+	if defIndex.ContainsUnifiedID(entry.UnifiedID()) || entry.IsHeader() {
 		return true
 	}
+
+	var bestID, similarity int
+	var fuzzy bool
 	if config.FuzzyMatch {
-		if bestID, ratio := def.BestIDRatio(*entry); ratio >= 50 {
+		bestID, similarity, fuzzy = defFuzzy.Best(*entry)
+	}
+	if suggestion, memorySimilarity, ok := suggest.lookup(*entry, config.threshold()); ok &&
+		(!fuzzy || memorySimilarity > similarity) {
+		mergeEntryStrings(entry, suggestion)
+		result := resultOf(MergeExact, *entry)
+		if memorySimilarity < 100 {
 			entry.markAsFuzzy()
-			best := def[bestID]
-			mergeEntryStrings(entry, best)
+			if config.Previous {
+				entry.Previous = previousMessageOf(suggestion)
+			}
+			result = fuzzyResult(*entry, suggestion, memorySimilarity)
 		}
-	} else if entry.IsPlural() {
+		result.Memory = true
+		config.Report.Add(result)
+		return false
+	}
+
+	if fuzzy {
+		entry.markAsFuzzy()
+		best := def[bestID]
+		mergeEntryStrings(entry, best)
+		if config.Previous {
+			entry.Previous = previousMessageOf(best)
+		}
+		config.Report.Add(fuzzyResult(*entry, best, similarity))
+		return false
+	}
+	if !config.FuzzyMatch && entry.IsPlural() {
 		for i := 0; i < nplurals; i++ {
 			entry.Plurals = append(entry.Plurals, PluralEntry{i, entry.ID})
 		}
 	}
+	config.Report.Add(resultOf(MergeNew, *entry))
 	return false
 }
 
-func mergeDef(config MergeConfig, e *Entry, ref Entries) bool {
-	if ref.ContainsUnifiedID(e.UnifiedID()) || e.IsHeader() {
+func mergeDef(
+	config MergeConfig,
+	e *Entry,
+	ref Entries,
+	refIndex EntryIndex,
+	refFuzzy *FuzzyIndex,
+	claimed []bool,
+	suggest suggestFunc,
+	nplurals int,
+) bool {
+	if e.IsHeader() {
 		return true
 	}
+	if i := refIndex.IndexByUnifiedID(e.UnifiedID()); i != -1 {
+		outcome := MergeExact
+		if e.Obsolete {
+			outcome = MergeRevived
+		}
+		mergeFields(e, ref[i], nplurals)
+		config.Report.Add(resultOf(outcome, *e))
+		return false
+	}
+
+	original := *e
 	switch {
 	case config.FuzzyMatch:
-		if bestID, ratio := ref.BestIDRatio(*e); ratio >= 50 {
+		bestID, similarity, ok := refFuzzy.best(*e, func(i int) bool { return claimed[i] })
+		if ok {
+			// The memory has a better translation than a fuzzy one for the message.
+			_, _, exact := suggest.lookup(ref[bestID], 100)
+			ok = !exact
+		}
+		if ok {
 			e.markAsFuzzy()
-			e.ID = ref[bestID].ID
-		} else {
-			e.markAsObsolete()
+			if config.Previous {
+				e.Previous = previousMessageOf(*e)
+			}
+			claimed[bestID] = true
+			mergeFields(e, ref[bestID], nplurals)
+			config.Report.Add(fuzzyResult(*e, original, similarity))
+			return false
 		}
 	case config.KeepPreviousIDs:
 		e.markAsFuzzy()
-	default:
-		e.markAsObsolete()
+		config.Report.Add(fuzzyResult(*e, original, 0))
+		return false
+	}
+
+	e.markAsObsolete()
+	if !original.Obsolete {
+		config.Report.Add(resultOf(MergeObsolete, *e))
 	}
 	return false
 }
 
+func resultOf(outcome MergeOutcome, e Entry) MergeResult {
+	return MergeResult{Outcome: outcome, Context: e.Context, ID: e.ID}
+}
+
+// fuzzyResult returns the result of e, which took the translation of matched.
+func fuzzyResult(e, matched Entry, similarity int) MergeResult {
+	result := resultOf(MergeFuzzy, e)
+	previous := previousMessageOf(matched)
+	result.Matched = &previous
+	result.Similarity = similarity
+	return result
+}
+
+// mergeFields updates e, an entry of def, with the fields of its matching
+// entry of ref, like GNU msgmerge: the translations, translator comments
+// and the fuzzy flag are kept, while the extracted comments, references,
+// flags and msgid_plural come from ref.
+//
+// If the msgid_plural changed, the translations are adapted to it and
+// the entry becomes fuzzy. Untranslated entries are never fuzzy, and only
+// fuzzy entries keep their previous message.
+func mergeFields(e *Entry, ref Entry, nplurals int) {
+	fuzzy := e.IsFuzzy()
+
+	e.ID, e.Context = ref.ID, ref.Context
+	e.Obsolete = false
+	e.ExtractedComments = slices.Clone(ref.ExtractedComments)
+	e.Locations = slices.Clone(ref.Locations)
+	e.Flags = nil
+	for _, f := range NormalizeFlags(ref.Flags) {
+		if f != FlagFuzzy {
+			e.Flags = append(e.Flags, f)
+		}
+	}
+
+	if e.Plural != ref.Plural {
+		fuzzy = true
+		switch {
+		case e.Plural == "":
+			// The translation is used for all the plural forms.
+			plurals := make(PluralEntries, nplurals)
+			for i := range plurals {
+				plurals[i] = PluralEntry{ID: i, Str: e.Str}
+			}
+			e.Str, e.Plurals = "", plurals
+		case ref.Plural == "":
+			e.Str = ""
+			if len(e.Plurals) > 0 {
+				e.Str = slices.Clone(e.Plurals).Sort()[0].Str
+			}
+			e.Plurals = nil
+		}
+		e.Plural = ref.Plural
+	}
+
+	hasTranslation := e.Str != "" || slices.ContainsFunc(e.Plurals, func(pe PluralEntry) bool {
+		return pe.Str != ""
+	})
+	if fuzzy && hasTranslation {
+		e.markAsFuzzy()
+	} else {
+		e.Previous = PreviousMessage{}
+	}
+}
+
+func previousMessageOf(e Entry) PreviousMessage {
+	return PreviousMessage{
+		Context: e.Context,
+		ID:      e.ID,
+		Plural:  e.Plural,
+	}
+}
+
 // mergeEntryStrings copies translation strings from source to target.
 //
 // If both entries are plural, plural forms are copied.
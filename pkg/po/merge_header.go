@@ -0,0 +1,75 @@
+package po
+
+import (
+	"strconv"
+	"strings"
+)
+
+// HeaderPolicy selects how [MergeWithConfig] merges the header of def with the header of ref.
+type HeaderPolicy int
+
+const (
+	// HeaderKeep keeps the header of def untouched.
+	HeaderKeep HeaderPolicy = iota
+	// HeaderUpdate takes the fields that describe the template, Project-Id-Version,
+	// Report-Msgid-Bugs-To and POT-Creation-Date, from ref and keeps the
+	// rest, which describe the translation, from def. The header of ref is
+	// used if def doesn't have one.
+	HeaderUpdate
+	// HeaderReplace replaces the content of the header of def with the one of ref.
+	HeaderReplace
+)
+
+// refHeaderFields are the fields taken from ref by [HeaderUpdate].
+var refHeaderFields = []string{"Project-Id-Version", "Report-Msgid-Bugs-To", "POT-Creation-Date"}
+
+// isPlaceholder reports whether value is empty or the initial value of a template field.
+func isPlaceholder(value string) bool {
+	value = strings.TrimSpace(value)
+	return value == "" || value == "PACKAGE VERSION" || value == "YEAR-MO-DA HO:MI+ZONE"
+}
+
+// mergeHeader merges the header of ref into def following the HeaderPolicy,
+// and fills the Language and Plural-Forms fields if they're missing.
+func (m MergeConfig) mergeHeader(def, ref Entries) Entries {
+	if m.HeaderPolicy == HeaderKeep {
+		return def
+	}
+
+	if refIndex := ref.Index("", ""); refIndex != -1 {
+		defIndex := def.Index("", "")
+		switch {
+		case defIndex == -1:
+			def = append(Entries{ref[refIndex]}, def...)
+		case m.HeaderPolicy == HeaderReplace:
+			def[defIndex].Str = ref[refIndex].Str
+		case m.HeaderPolicy == HeaderUpdate:
+			refHeader := ref.Header()
+			for _, key := range refHeaderFields {
+				if value := refHeader.Load(key); !isPlaceholder(value) {
+					def.SetHeaderField(key, value)
+				}
+			}
+		}
+	}
+	if !def.HasHeader() {
+		return def
+	}
+
+	header := def.Header()
+	language := strings.TrimSpace(header.Load("Language"))
+	if language == "" && m.Language != "" {
+		language = m.Language
+		def.SetHeaderField("Language", language)
+	}
+
+	// Placeholders like "nplurals=INTEGER; plural=EXPRESSION;" are missing values.
+	nplurals := parseAdvHeaderField(header.Load("Plural-Forms"))["nplurals"]
+	if _, err := strconv.ParseUint(nplurals, 10, 64); err != nil && language != "" {
+		if forms, ok := PluralFormsFor(language); ok {
+			def.SetHeaderField("Plural-Forms", forms.String())
+		}
+	}
+
+	return def
+}
@@ -0,0 +1,187 @@
+package po
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MergeOutcome is what a merge did with a message.
+type MergeOutcome int
+
+const (
+	// MergeExact means that the message of ref was found in def, or in the
+	// translation memory.
+	MergeExact MergeOutcome = iota
+	// MergeFuzzy means that the message took the translation of a similar
+	// message of def, or of the translation memory, and was marked as fuzzy.
+	MergeFuzzy
+	// MergeNew means that the message of ref has no translation in def.
+	MergeNew
+	// MergeObsolete means that the message of def is no longer in ref.
+	MergeObsolete
+	// MergeRevived means that an obsolete message of def is back in ref.
+	MergeRevived
+)
+
+var mergeOutcomeNames = []string{
+	MergeExact:    "exact",
+	MergeFuzzy:    "fuzzy",
+	MergeNew:      "new",
+	MergeObsolete: "obsolete",
+	MergeRevived:  "revived",
+}
+
+func (o MergeOutcome) String() string {
+	if o < 0 || int(o) >= len(mergeOutcomeNames) {
+		return fmt.Sprintf("MergeOutcome(%d)", int(o))
+	}
+	return mergeOutcomeNames[o]
+}
+
+// MarshalText encodes the outcome as its name, like "fuzzy".
+func (o MergeOutcome) MarshalText() ([]byte, error) {
+	if o < 0 || int(o) >= len(mergeOutcomeNames) {
+		return nil, fmt.Errorf("unknown merge outcome %d", int(o))
+	}
+	return []byte(o.String()), nil
+}
+
+// UnmarshalText decodes the name of an outcome.
+func (o *MergeOutcome) UnmarshalText(text []byte) error {
+	for i, name := range mergeOutcomeNames {
+		if name == string(text) {
+			*o = MergeOutcome(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown merge outcome %q", text)
+}
+
+// MergeResult is the outcome of the merge of a message.
+type MergeResult struct {
+	Outcome MergeOutcome `json:"outcome"`
+	Context string       `json:"context,omitempty"`
+	ID      string       `json:"id"`
+	// Matched is the message of def, or of the memory, whose translation was taken by a fuzzy
+	// match, and Similarity is how similar both messages are, from 0 to 100.
+	// For the unmatched messages kept as fuzzy by KeepPreviousIDs, Matched
+	// is the message itself and Similarity is zero.
+	Matched    *PreviousMessage `json:"matched,omitempty"`
+	Similarity int              `json:"similarity,omitempty"`
+	// Memory reports whether the translation was taken from [MergeConfig.Memory].
+	Memory bool `json:"memory,omitempty"`
+}
+
+type (
+	mergeResultFields MergeResult
+	jsonMergeResult   struct {
+		mergeResultFields
+		Matched *jsonPrevious `json:"matched,omitempty"`
+	}
+)
+
+// MarshalJSON encodes the result with the fields of Matched in lower case.
+func (r MergeResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonMergeResult{mergeResultFields(r), newJSONPrevious(r.Matched)})
+}
+
+// UnmarshalJSON decodes a result encoded by [MergeResult.MarshalJSON].
+func (r *MergeResult) UnmarshalJSON(data []byte) error {
+	var j jsonMergeResult
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*r = MergeResult(j.mergeResultFields)
+	r.Matched = j.Matched.previous()
+	return nil
+}
+
+// MergeTotals holds the number of messages with each [MergeOutcome].
+type MergeTotals struct {
+	Exact    int `json:"exact"`
+	Fuzzy    int `json:"fuzzy"`
+	New      int `json:"new"`
+	Obsolete int `json:"obsolete"`
+	Revived  int `json:"revived"`
+}
+
+// MergeReport describes what a merge did with each message, see [MergeConfig.Report].
+//
+// The header and the messages of def that were already obsolete and are
+// still missing in ref are not reported.
+type MergeReport struct {
+	Results []MergeResult `json:"results"`
+	Totals  MergeTotals   `json:"totals"`
+}
+
+// Add records a result and counts it in the totals. It does nothing if r is nil.
+func (r *MergeReport) Add(result MergeResult) {
+	if r == nil {
+		return
+	}
+
+	r.Results = append(r.Results, result)
+	switch result.Outcome {
+	case MergeExact:
+		r.Totals.Exact++
+	case MergeFuzzy:
+		r.Totals.Fuzzy++
+	case MergeNew:
+		r.Totals.New++
+	case MergeObsolete:
+		r.Totals.Obsolete++
+	case MergeRevived:
+		r.Totals.Revived++
+	}
+}
+
+// Filter returns the results with the given outcome.
+func (r MergeReport) Filter(outcome MergeOutcome) []MergeResult {
+	var results []MergeResult
+	for _, result := range r.Results {
+		if result.Outcome == outcome {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// String returns a summary like the one of msgmerge --verbose:
+// "10 exact, 2 fuzzy, 3 new, 1 obsolete, 0 revived."
+func (t MergeTotals) String() string {
+	return fmt.Sprintf("%d exact, %d fuzzy, %d new, %d obsolete, %d revived.",
+		t.Exact, t.Fuzzy, t.New, t.Obsolete, t.Revived)
+}
+
+// String returns the totals followed by a line for each fuzzy, obsolete
+// and revived message, which are the ones that need a review.
+func (r MergeReport) String() string {
+	var b strings.Builder
+	b.WriteString(r.Totals.String())
+	for _, result := range r.Results {
+		switch result.Outcome {
+		case MergeFuzzy:
+			fmt.Fprintf(&b, "\nfuzzy: %s", quoteMessage(result.Context, result.ID))
+			if result.Matched != nil && result.Similarity > 0 {
+				fmt.Fprintf(&b, " from %s (%d%%)",
+					quoteMessage(result.Matched.Context, result.Matched.ID), result.Similarity)
+			}
+			if result.Memory {
+				b.WriteString(" in the translation memory")
+			}
+		case MergeObsolete, MergeRevived:
+			fmt.Fprintf(&b, "\n%s: %s", result.Outcome, quoteMessage(result.Context, result.ID))
+		}
+	}
+
+	return b.String()
+}
+
+func quoteMessage(context, id string) string {
+	if context == "" {
+		return fmt.Sprintf("%q", id)
+	}
+	return fmt.Sprintf("%q (context %q)", id, context)
+}
@@ -2,15 +2,18 @@ package po_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/Tom5521/gotext-tools/v2/internal/util"
 	"github.com/Tom5521/gotext-tools/v2/pkg/po"
 	"github.com/Tom5521/gotext-tools/v2/pkg/po/compile"
 	"github.com/Tom5521/gotext-tools/v2/pkg/po/parse"
+	"github.com/Tom5521/gotext-tools/v2/pkg/tm"
 )
 
 func TestMergeWithMsgmerge(t *testing.T) {
@@ -137,3 +140,383 @@ func TestMergeWithMsgmerge(t *testing.T) {
 		})
 	}
 }
+
+func TestMergePrevious(t *testing.T) {
+	def := po.Entries{
+		{Context: "menu", ID: "Open the file", Str: "Abre el archivo"},
+		{ID: "Close", Str: "Cerrar"},
+	}
+	ref := po.Entries{
+		{Context: "menu", ID: "Open the files"},
+		{ID: "Close"},
+	}
+
+	merged := po.Merge(def, ref, po.MergeWithSort(false))
+	i := merged.Index("Open the files", "menu")
+	if i == -1 {
+		t.Fatal("fuzzy matched entry not found")
+	}
+
+	expected := po.PreviousMessage{Context: "menu", ID: "Open the file"}
+	if !merged[i].IsFuzzy() || merged[i].Previous != expected {
+		t.Errorf("expected a fuzzy entry with previous %v, got %v", expected, merged[i])
+	}
+	if j := merged.Index("Close", ""); j == -1 || !merged[j].Previous.IsZero() {
+		t.Error("exact matches must not have a previous message")
+	}
+
+	merged = po.Merge(def, ref, po.MergeWithPrevious(false))
+	if i = merged.Index("Open the files", "menu"); i == -1 || !merged[i].Previous.IsZero() {
+		t.Error("previous message recorded with Previous disabled")
+	}
+}
+
+func TestMergeStampRevision(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	const header = "Project-Id-Version: test\nPO-Revision-Date: 2020-01-01 00:00+0000\n"
+	def := po.Entries{
+		{Str: header},
+		{ID: "Close", Str: "Cerrar"},
+	}
+
+	merged := po.Merge(def, po.Entries{{ID: "Close"}}, po.MergeWithStampRevision(true))
+	if merged[0].Str != header {
+		t.Errorf("revision stamped without changes:\n%s", merged[0].Str)
+	}
+
+	merged = po.Merge(def, po.Entries{{ID: "Close"}, {ID: "Open"}},
+		po.MergeWithStampRevision(true),
+		po.MergeWithLastTranslator("Jane Doe <jane@example.com>"),
+	)
+	i := merged.Index("", "")
+	if i == -1 {
+		t.Fatal("header not found")
+	}
+	h := merged.Header()
+	if got := h.Load("PO-Revision-Date"); got != "2023-11-14 22:13+0000" {
+		t.Errorf("unexpected revision date %q", got)
+	}
+	if got := h.Load("Last-Translator"); got != "Jane Doe <jane@example.com>" {
+		t.Errorf("unexpected last translator %q", got)
+	}
+}
+
+func TestMergeFields(t *testing.T) {
+	def := po.Entries{
+		{
+			Comments:          []string{"translator note"},
+			ExtractedComments: []string{"stale note"},
+			Locations:         po.Locations{{File: "old.go", Line: 1}},
+			ID:                "Open",
+			Str:               "Abrir",
+		},
+		{Flags: []string{"fuzzy"}, ID: "Close", Str: "Cerrar"},
+		{Flags: []string{"fuzzy"}, ID: "Save"},
+		{ID: "%d file", Str: "%d archivo"},
+		{
+			ID:      "%d folder",
+			Plural:  "%d folders",
+			Plurals: po.PluralEntries{{ID: 0, Str: "%d carpeta"}, {ID: 1, Str: "%d carpetas"}},
+		},
+	}
+	ref := po.Entries{
+		{
+			Flags:             []string{"go-format"},
+			ExtractedComments: []string{"new note"},
+			Locations:         po.Locations{{File: "new.go", Line: 42}},
+			ID:                "Open",
+		},
+		{ID: "Close"},
+		{ID: "Save"},
+		{Flags: []string{"go-format"}, ID: "%d file", Plural: "%d files"},
+		{ID: "%d folder"},
+	}
+
+	merged := po.Merge(def, ref, po.MergeWithSort(false))
+	get := func(id string) po.Entry {
+		i := merged.Index(id, "")
+		if i == -1 {
+			t.Fatalf("entry %q not found", id)
+		}
+		return merged[i]
+	}
+
+	open := get("Open")
+	expected := po.Entry{
+		Flags:             []string{"go-format"},
+		Comments:          []string{"translator note"},
+		ExtractedComments: []string{"new note"},
+		Locations:         po.Locations{{File: "new.go", Line: 42}},
+		ID:                "Open",
+		Str:               "Abrir",
+	}
+	if !util.Equal(open, expected) {
+		t.Error(util.NamedDiff("expected", "obtained", expected, open))
+	}
+
+	if !get("Close").IsFuzzy() {
+		t.Error("the fuzzy flag of translated entries must be kept")
+	}
+	if get("Save").IsFuzzy() {
+		t.Error("untranslated entries must not be fuzzy")
+	}
+
+	file := get("%d file")
+	if !file.IsFuzzy() || file.Plural != "%d files" || len(file.Plurals) != 2 ||
+		file.Plurals[1].Str != "%d archivo" || file.Str != "" || !file.HasFlag("go-format") {
+		t.Errorf("unexpected entry with a new msgid_plural: %v", file)
+	}
+
+	folder := get("%d folder")
+	if !folder.IsFuzzy() || folder.Plural != "" || folder.Plurals != nil || folder.Str != "%d carpeta" {
+		t.Errorf("unexpected entry without its msgid_plural: %v", folder)
+	}
+	if def[4].Plural == "" || len(def[4].Plurals) != 2 {
+		t.Error("def was modified")
+	}
+}
+
+func TestMergeReport(t *testing.T) {
+	def := po.Entries{
+		{ID: "Close", Str: "Cerrar"},
+		{ID: "Open the file", Str: "Abre el archivo"},
+		{ID: "Quit", Str: "Salir", Obsolete: true},
+		{ID: "Print", Str: "Imprimir"},
+		{ID: "Gone", Str: "Ido", Obsolete: true},
+	}
+	ref := po.Entries{
+		{ID: "Close"},
+		{ID: "Open the files"},
+		{ID: "Quit"},
+		{ID: "Help"},
+	}
+
+	var report po.MergeReport
+	po.Merge(def, ref, po.MergeWithReport(&report))
+
+	expected := po.MergeTotals{Exact: 1, Fuzzy: 1, New: 1, Obsolete: 1, Revived: 1}
+	if report.Totals != expected {
+		t.Errorf("expected totals %v, got %v", expected, report.Totals)
+	}
+
+	fuzzy := report.Filter(po.MergeFuzzy)
+	if len(fuzzy) != 1 || fuzzy[0].ID != "Open the files" || fuzzy[0].Matched == nil ||
+		fuzzy[0].Matched.ID != "Open the file" || fuzzy[0].Similarity < po.DefaultFuzzyThreshold {
+		t.Errorf("unexpected fuzzy results: %+v", fuzzy)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded po.MergeReport
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(report, decoded) {
+		t.Errorf("expected %+v, decoded %+v", report, decoded)
+	}
+	if !bytes.Contains(data, []byte(`"matched":{"id":"Open the file"}`)) {
+		t.Errorf("unexpected encoding of the matched message: %s", data)
+	}
+	if !bytes.Contains(data, []byte(`"outcome":"revived"`)) {
+		t.Errorf("outcomes must be encoded by name: %s", data)
+	}
+
+	po.Merge(def, ref, po.MergeWithReport(&report), po.MergeWithFuzzyMatch(false))
+	if report.Totals.Fuzzy != 0 || report.Totals.New != 2 {
+		t.Errorf("the report wasn't reset: %v", report.Totals)
+	}
+}
+
+func TestMergeHeader(t *testing.T) {
+	def := po.Entries{
+		{Str: "Project-Id-Version: app 1.0\n" +
+			"POT-Creation-Date: 2020-01-01 00:00+0000\n" +
+			"PO-Revision-Date: 2020-02-01 00:00+0000\n" +
+			"Last-Translator: Jane Doe <jane@example.com>\n" +
+			"Language: \n"},
+		{ID: "Close", Str: "Cerrar"},
+	}
+	ref := po.Entries{
+		{Flags: []string{"fuzzy"}, Str: "Project-Id-Version: app 2.0\n" +
+			"Report-Msgid-Bugs-To: bugs@example.com\n" +
+			"POT-Creation-Date: 2023-11-14 22:13+0000\n" +
+			"PO-Revision-Date: YEAR-MO-DA HO:MI+ZONE\n" +
+			"Last-Translator: FULL NAME <EMAIL@ADDRESS>\n"},
+		{ID: "Close"},
+	}
+
+	tests := []struct {
+		name     string
+		opts     []po.MergeOption
+		expected map[string]string
+	}{
+		{
+			"Keep",
+			[]po.MergeOption{po.MergeWithHeaderPolicy(po.HeaderKeep), po.MergeWithLanguage("es")},
+			map[string]string{
+				"Project-Id-Version": "app 1.0",
+				"POT-Creation-Date":  "2020-01-01 00:00+0000",
+				"Language":           "",
+				"Plural-Forms":       "",
+			},
+		},
+		{
+			"Update",
+			[]po.MergeOption{po.MergeWithLanguage("es")},
+			map[string]string{
+				"Project-Id-Version":   "app 2.0",
+				"Report-Msgid-Bugs-To": "bugs@example.com",
+				"POT-Creation-Date":    "2023-11-14 22:13+0000",
+				"PO-Revision-Date":     "2020-02-01 00:00+0000",
+				"Last-Translator":      "Jane Doe <jane@example.com>",
+				"Language":             "es",
+				"Plural-Forms":         "nplurals=2; plural=(n != 1);",
+			},
+		},
+		{
+			"Replace",
+			[]po.MergeOption{po.MergeWithHeaderPolicy(po.HeaderReplace)},
+			map[string]string{
+				"Project-Id-Version": "app 2.0",
+				"Last-Translator":    "FULL NAME <EMAIL@ADDRESS>",
+				"Language":           "",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged := po.Merge(def, ref, test.opts...)
+			header := merged.Header()
+			for key, value := range test.expected {
+				if got := header.Load(key); got != value {
+					t.Errorf("expected %s %q, got %q", key, value, got)
+				}
+			}
+		})
+	}
+
+	merged := po.Merge(po.Entries{{ID: "Close", Str: "Cerrar"}}, ref, po.MergeWithLanguage("pl"))
+	header := merged.Header()
+	if header.Load("Project-Id-Version") != "app 2.0" || header.Load("Language") != "pl" ||
+		header.Nplurals() != 3 {
+		t.Errorf("expected the header of ref with the language filled:\n%s", merged[0].Str)
+	}
+
+	// The missing fields are added where GNU gettext writes them.
+	def = po.Entries{
+		{Str: "Project-Id-Version: app 1.0\n" +
+			"Language: es\n" +
+			"Content-Type: text/plain; charset=UTF-8\n" +
+			"X-Generator: Poedit 3.4\n"},
+		{ID: "Close", Str: "Cerrar"},
+	}
+	merged = po.Merge(def, ref)
+	expected := "Project-Id-Version: app 2.0\n" +
+		"Report-Msgid-Bugs-To: bugs@example.com\n" +
+		"POT-Creation-Date: 2023-11-14 22:13+0000\n" +
+		"Language: es\n" +
+		"Content-Type: text/plain; charset=UTF-8\n" +
+		"Plural-Forms: nplurals=2; plural=(n != 1);\n" +
+		"X-Generator: Poedit 3.4\n"
+	if merged[0].Str != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, merged[0].Str)
+	}
+}
+
+func TestMergeUniqueIDs(t *testing.T) {
+	tests := []struct {
+		name     string
+		def      po.Entries
+		expected po.MergeTotals
+	}{
+		{
+			"ExactMatched",
+			po.Entries{
+				{ID: "Open the file", Str: "Abrir el archivo"},
+				{ID: "Open the file.", Str: "Abrir el archivo."},
+			},
+			po.MergeTotals{Exact: 1, Obsolete: 1},
+		},
+		{
+			"FuzzyMatched",
+			po.Entries{
+				{ID: "Open the file.", Str: "Abrir el archivo."},
+				{ID: "Open the file!", Str: "¡Abrir el archivo!"},
+			},
+			po.MergeTotals{Fuzzy: 1, Obsolete: 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var report po.MergeReport
+			merged := po.Merge(test.def, po.Entries{{ID: "Open the file"}}, po.MergeWithReport(&report))
+
+			seen := make(map[string]bool)
+			for _, e := range merged {
+				if seen[e.UnifiedID()] {
+					t.Errorf("the message %q is repeated:\n%v", e.ID, merged)
+				}
+				seen[e.UnifiedID()] = true
+			}
+			if report.Totals != test.expected {
+				t.Errorf("expected totals %v, got %v", test.expected, report.Totals)
+			}
+		})
+	}
+}
+
+func TestMergeMemory(t *testing.T) {
+	memory := tm.New()
+	memory.Add(
+		tm.Unit{TargetLang: "es", Source: "Save the file", Target: "Guardar el archivo"},
+		tm.Unit{TargetLang: "es", Source: "Open the file", Target: "Abrir el archivo"},
+		tm.Unit{TargetLang: "fr", Source: "Help", Target: "Aide"},
+	)
+
+	def := po.Entries{
+		{Str: "Language: es\n"},
+		{ID: "Close", Str: "Cerrar"},
+	}
+	ref := po.Entries{
+		{ID: "Close"},
+		{ID: "Save the file"},
+		{ID: "Open the files"},
+		{ID: "Help"},
+	}
+
+	var report po.MergeReport
+	merged := po.Merge(def, ref, po.MergeWithMemory(memory), po.MergeWithReport(&report), po.MergeWithSort(false))
+
+	expected := po.MergeTotals{Exact: 2, Fuzzy: 1, New: 1}
+	if report.Totals != expected {
+		t.Errorf("expected totals %v, got %v", expected, report.Totals)
+	}
+	if i := merged.Index("Save the file", ""); i == -1 || merged[i].Str != "Guardar el archivo" || merged[i].IsFuzzy() {
+		t.Errorf("unexpected exact translation from the memory: %v", merged)
+	}
+	if i := merged.Index("Open the files", ""); i == -1 || merged[i].Str != "Abrir el archivo" || !merged[i].IsFuzzy() {
+		t.Errorf("unexpected fuzzy translation from the memory: %v", merged)
+	}
+	if i := merged.Index("Help", ""); i == -1 || merged[i].Str != "" {
+		t.Errorf("the translations to other languages must not be used: %v", merged)
+	}
+	for _, result := range report.Results {
+		if memoryResult := result.ID == "Save the file" || result.ID == "Open the files"; result.Memory != memoryResult {
+			t.Errorf("unexpected Memory of %q: %v", result.ID, result.Memory)
+		}
+	}
+
+	// Without a language the memory would suggest translations to any language.
+	po.Merge(def[1:], ref, po.MergeWithMemory(memory), po.MergeWithReport(&report))
+	if report.Totals.New != 3 {
+		t.Errorf("the memory was used without knowing the language: %v", report.Totals)
+	}
+	merged = po.Merge(def[1:], ref, po.MergeWithMemory(memory), po.MergeWithLanguage("fr"), po.MergeWithSort(false))
+	if i := merged.Index("Help", ""); i == -1 || merged[i].Str != "Aide" {
+		t.Errorf("the memory wasn't used in the Language of the config: %v", merged)
+	}
+}
@@ -126,11 +126,8 @@ func msgcatMergeEntry(
 	originalFilename, newFilename string,
 ) Entry {
 	mergedEntry := Entry{
-		Flags: msgcatMergeComments(
-			originalEntry.Flags,
-			newEntry.Flags,
-			originalFilename,
-			newFilename,
+		Flags: NormalizeFlags(
+			append(append([]string{}, originalEntry.Flags...), newEntry.Flags...),
 		),
 		Comments: msgcatMergeComments(
 			originalEntry.Comments,
@@ -144,12 +141,7 @@ func msgcatMergeEntry(
 			originalFilename,
 			newFilename,
 		),
-		Previous: msgcatMergeComments(
-			originalEntry.Previous,
-			newEntry.Previous,
-			originalFilename,
-			newFilename,
-		),
+		Previous: msgcatMergePrevious(originalEntry.Previous, newEntry.Previous),
 
 		ID:      originalEntry.ID,
 		Context: originalEntry.Context,
@@ -175,7 +167,7 @@ func msgcatMergeEntry(
 	if !mergedEntry.IsFuzzy() &&
 		(originalEntry.FullUnifiedID() != newEntry.FullUnifiedID() ||
 			originalEntry.UnifiedStr() != newEntry.UnifiedStr()) {
-		mergedEntry.Flags = append(mergedEntry.Flags, "fuzzy")
+		mergedEntry.AddFlag(FlagFuzzy)
 	}
 
 	return mergedEntry
@@ -230,6 +222,13 @@ func msgcatMergeStrings(originalStr, newStr string, originalFilename, newFilenam
 	return builder.String()
 }
 
+func msgcatMergePrevious(originalPrevious, newPrevious PreviousMessage) PreviousMessage {
+	if originalPrevious.IsZero() {
+		return newPrevious
+	}
+	return originalPrevious
+}
+
 func msgcatMergeComments(
 	originalComments, newComments []string,
 	originalFilename, newFilename string,
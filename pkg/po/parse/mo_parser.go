@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
 
 	"github.com/Tom5521/gotext-tools/v2/internal/util"
 	"github.com/Tom5521/gotext-tools/v2/pkg/po"
@@ -245,6 +246,8 @@ func (m *MoParser) makeEntries(
 	msgIDStart, msgIDLen []u32,
 	msgStrStart, msgStrLen []i32,
 ) (entries po.Entries) {
+	msgids := make([][]byte, 0, header.Nstrings)
+	msgstrs := make([][]byte, 0, header.Nstrings)
 	for i := u32(0); i < header.Nstrings; i++ {
 		idStart := i64(msgIDStart[i])
 		idLen := msgIDLen[i]
@@ -283,12 +286,55 @@ func (m *MoParser) makeEntries(
 			)
 		}
 
-		entries = append(entries, makeEntry(msgIDData, msgStrData))
+		msgids = append(msgids, msgIDData)
+		msgstrs = append(msgstrs, msgStrData)
+	}
+
+	decode := m.charsetDecoder(msgids, msgstrs)
+	for i := range msgids {
+		entries = append(entries, makeEntry(decode(msgids[i]), decode(msgstrs[i])))
 	}
 
 	return
 }
 
+var moCharsetRegex = regexp.MustCompile(`(?m)^Content-Type:.*charset=([^\s;]+)`)
+
+// charsetDecoder returns a function that converts the strings of the MO
+// file to UTF-8, according to the charset declared in its header.
+func (m *MoParser) charsetDecoder(msgids, msgstrs [][]byte) func([]byte) []byte {
+	noop := func(b []byte) []byte { return b }
+
+	for i, msgid := range msgids {
+		if len(msgid) != 0 {
+			continue
+		}
+		matches := moCharsetRegex.FindSubmatch(msgstrs[i])
+		if matches == nil {
+			return noop
+		}
+		charset := string(matches[1])
+		if charset == "CHARSET" || util.IsUTF8Charset(charset) {
+			return noop
+		}
+		if _, ok := util.CharsetEncoding(charset); !ok {
+			m.error("unsupported charset %q", charset)
+			return noop
+		}
+
+		return func(b []byte) []byte {
+			decoded, err := util.DecodeCharset(b, charset)
+			if err != nil {
+				m.error("error decoding %s string: %w", charset, err)
+				return b
+			}
+			return decoded
+		}
+	}
+
+	return noop
+}
+
 // Is there a way to make this code more... clean? maybe...
 
 // makeEntry creates a single po.Entry from raw message ID and string data.
@@ -33,8 +33,9 @@ type PoConfig struct {
 	UseCustomObsoletePrefix bool
 	// CustomObsoletePrefix defines the custom marker for obsolete entries.
 	CustomObsoletePrefix rune
-	// markAllAsObsolete internally marks all parsed entries as obsolete.
-	markAllAsObsolete bool
+	// KeepSyntax controls whether to keep the original text of the entries
+	// in [po.File.Syntax], so they can be written back unmodified.
+	KeepSyntax bool
 }
 
 // RestoreLastCfg restores the configuration state prior to the last
@@ -70,13 +71,6 @@ func DefaultPoConfig(opts ...PoOption) PoConfig {
 // PoOption defines a function type for modifying PoConfig.
 type PoOption func(*PoConfig)
 
-// poWithMarkAllAsObsolete creates an option to mark all entries as obsolete.
-func poWithMarkAllAsObsolete(m bool) PoOption {
-	return func(pc *PoConfig) {
-		pc.markAllAsObsolete = m
-	}
-}
-
 // PoWithVerbose creates an option to enable verbose logging.
 func PoWithVerbose(v bool) PoOption {
 	return func(pc *PoConfig) {
@@ -138,3 +132,8 @@ func PoWithCleanDuplicates(cd bool) PoOption {
 func PoWithLogger(logger *log.Logger) PoOption {
 	return func(c *PoConfig) { c.Logger = logger }
 }
+
+// PoWithKeepSyntax creates an option to keep the original text of the entries.
+func PoWithKeepSyntax(k bool) PoOption {
+	return func(c *PoConfig) { c.KeepSyntax = k }
+}
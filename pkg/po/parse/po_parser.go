@@ -1,6 +1,7 @@
 package parse
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -8,11 +9,13 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/Tom5521/gotext-tools/v2/internal/slices"
 	"github.com/Tom5521/gotext-tools/v2/internal/util"
 	"github.com/Tom5521/gotext-tools/v2/pkg/po"
 
+	"github.com/alecthomas/participle/v2"
 	"github.com/alecthomas/participle/v2/lexer"
 )
 
@@ -73,16 +76,22 @@ func NewPoFromBytes(data []byte, name string, options ...PoOption) *PoParser {
 // error logs an error message and adds it to the parser's error collection.
 // If a logger is configured, it will also log the error.
 func (p *PoParser) error(format string, a ...any) {
-	var err error
-	format = "parse: " + format
-	if len(a) == 0 {
-		err = errors.New(format)
-	} else {
-		err = fmt.Errorf(format, a...)
+	p.errorAt(0, 0, "", fmt.Errorf(format, a...))
+}
+
+// errorAt adds a [po.ParseError] with the given position to the parser's error collection.
+func (p *PoParser) errorAt(line, column int, token string, reason error) {
+	err := &po.ParseError{
+		Filename: p.filename,
+		Line:     line,
+		Column:   column,
+		Token:    token,
+		Severity: po.SeverityError,
+		Reason:   reason,
 	}
 
 	if p.Config.Logger != nil {
-		p.Config.Logger.Println("ERROR: ", err)
+		p.Config.Logger.Println(err)
 	}
 
 	p.errors = append(p.errors, err)
@@ -91,19 +100,36 @@ func (p *PoParser) error(format string, a ...any) {
 // warn logs a warning message and adds it to the parser's warning collection.
 // If verbose logging is enabled, it will also log the warning.
 func (p *PoParser) warn(format string, a ...any) {
-	var err error
-	format = "warning: " + format
-	if len(a) == 0 {
-		err = errors.New(format)
-	} else {
-		err = fmt.Errorf(format, a...)
+	err := &po.ParseError{
+		Filename: p.filename,
+		Severity: po.SeverityWarning,
+		Reason:   fmt.Errorf(format, a...),
 	}
 
 	if p.Config.Logger != nil && p.Config.Verbose {
 		p.Config.Logger.Println(err)
 	}
 
-	p.errors = append(p.errors, err)
+	p.warns = append(p.warns, err)
+}
+
+// syntaxError adds the error returned by the participle parser, moving
+// its position lineOffset lines down.
+func (p *PoParser) syntaxError(err error, lineOffset int) {
+	var perr participle.Error
+	if !errors.As(err, &perr) {
+		p.errorAt(0, 0, "", err)
+		return
+	}
+
+	var token string
+	var uerr *participle.UnexpectedTokenError
+	if errors.As(err, &uerr) {
+		token = uerr.Unexpected.Value
+	}
+
+	pos := perr.Position()
+	p.errorAt(pos.Line+lineOffset, pos.Column, token, errors.New(perr.Message()))
 }
 
 // Error returns the first error encountered during parsing, if any.
@@ -130,101 +156,172 @@ var (
 	generalRegex   = regexp.MustCompile(`# *(.*)`)   // General translator comments
 	extractedRegex = regexp.MustCompile(`#\. *(.*)`) // Extracted comments
 	flagRegex      = regexp.MustCompile(`#, *(.*)`)  // Flag comments
-	obsoleteRegex  = regexp.MustCompile(`#~ *(.*)`)  // Obsolete entry markers
+	obsoleteRegex  = regexp.MustCompile(`^#~ *(.*)`) // Obsolete entry markers
 	previousRegex  = regexp.MustCompile(`#\| *(.*)`) // Previous message comments
+
+	charsetRegex = regexp.MustCompile(`"Content-Type:[^"]*charset=([^"\s;\\]+)`) // Header charset
 )
 
-// parseObsoleteEntries extracts and parses obsolete entries from the PO file.
-func (p *PoParser) parseObsoleteEntries(tokens []lexer.Token) po.Entries {
-	comp := obsoleteRegex
-	if p.Config.UseCustomObsoletePrefix {
-		var err error
-		comp, err = regexp.Compile(fmt.Sprintf(`#%s *(.*)`, string(p.Config.CustomObsoletePrefix)))
-		if err != nil {
-			p.warn(err.Error())
-			return nil
-		}
+// decodeData converts the PO file data to UTF-8 according to the
+// charset declared in its header, if any.
+func (p *PoParser) decodeData(data []byte) []byte {
+	matches := charsetRegex.FindSubmatch(data)
+	if matches == nil {
+		return data
+	}
+	charset := string(matches[1])
+	if charset == "CHARSET" || util.IsUTF8Charset(charset) {
+		return data
 	}
 
-	cleanedLines := make([]string, 0, len(tokens))
-	for _, token := range tokens {
-		if token.Type != util.PoSymbols["Comment"] {
-			continue
-		}
-		if !comp.MatchString(token.String()) {
-			continue
-		}
-
-		cleanedLines = append(cleanedLines,
-			comp.FindStringSubmatch(token.String())[1],
-		)
+	decoded, err := util.DecodeCharset(data, charset)
+	if err != nil {
+		p.warn("%s, reading the file as UTF-8", err)
+		return data
 	}
 
-	fullFile := strings.Join(cleanedLines, "\n")
+	return decoded
+}
 
-	parser := NewPoFromString(
-		fullFile,
-		"tmp",
-		PoWithConfig(p.Config),
-		poWithMarkAllAsObsolete(true),
-	)
+// obsoletePrefix returns the rune that follows "#" in the lines of obsolete entries.
+func (p *PoParser) obsoletePrefix() rune {
+	if p.Config.UseCustomObsoletePrefix {
+		return p.Config.CustomObsoletePrefix
+	}
+	return util.DefaultObsoletePrefix
+}
 
-	f := parser.Parse()
-	err := parser.Error()
-	if err != nil {
-		return nil
+// isObsolete reports whether the keywords and strings of an entry are in
+// obsolete lines. consistent is false if only some of them are.
+func isObsolete(tokens []lexer.Token) (obsolete, consistent bool) {
+	obsoleteLines := make(map[int]bool)
+	var inObsolete, inActive int
+	for _, t := range tokens {
+		switch t.Type {
+		case util.PoSymbols["Obsolete"]:
+			obsoleteLines[t.Pos.Line] = true
+		case util.PoSymbols["WS"], util.PoSymbols["Comment"], util.PoSymbols["ObsoletePrevious"]:
+		default:
+			if obsoleteLines[t.Pos.Line] {
+				inObsolete++
+			} else {
+				inActive++
+			}
+		}
 	}
 
-	return f.Entries
+	return inObsolete > 0, inObsolete == 0 || inActive == 0
 }
 
 // parseComments processes all comment tokens associated with an entry and
-// populates the corresponding fields in the Entry struct.
+// populates the corresponding fields in the Entry struct. The previous
+// message of obsolete entries ("#~|") is parsed like a "#|" comment.
 func parseComments(entry *po.Entry, tokens []lexer.Token) (err error) {
+	var previous []string
 	for _, t := range tokens {
-		if t.Type != util.PoSymbols["Comment"] {
-			continue
+		switch t.Type {
+		case util.PoSymbols["Comment"]:
+			// Lines with the default obsolete prefix are ignored when a custom one is used.
+			if obsoleteRegex.MatchString(t.String()) {
+				continue
+			}
+			parseComment(entry, &previous, t.String())
+		case util.PoSymbols["ObsoletePrevious"]:
+			// Remove the obsolete prefix, keeping the "#" and the "|".
+			comment := t.String()
+			_, size := utf8.DecodeRuneInString(comment[1:])
+			parseComment(entry, &previous, "#"+comment[1+size:])
 		}
-		if obsoleteRegex.MatchString(t.String()) {
+	}
+
+	if len(previous) > 0 {
+		entry.Previous, err = parsePrevious(previous)
+	}
+
+	return
+}
+
+// parseComment adds a comment line to the corresponding field of the entry.
+// The content of "#|" lines is appended to previous, to be parsed with
+// [parsePrevious] once all the comments of the entry were read.
+func parseComment(entry *po.Entry, previous *[]string, comment string) {
+	switch {
+	case locationRegex.MatchString(comment):
+		entry.Locations = append(entry.Locations,
+			po.ParseReferences(locationRegex.FindStringSubmatch(comment)[1])...,
+		)
+	case extractedRegex.MatchString(comment):
+		entry.ExtractedComments = append(entry.ExtractedComments,
+			extractedRegex.FindStringSubmatch(comment)[1],
+		)
+	case flagRegex.MatchString(comment):
+		entry.Flags = po.NormalizeFlags(append(entry.Flags,
+			flagRegex.FindStringSubmatch(comment)[1],
+		))
+	case previousRegex.MatchString(comment):
+		*previous = append(*previous,
+			previousRegex.FindStringSubmatch(comment)[1],
+		)
+	default:
+		entry.Comments = append(entry.Comments,
+			generalRegex.FindStringSubmatch(comment)[1],
+		)
+	}
+}
+
+// parsePrevious parses the content of the "#|" lines of an entry, which
+// hold the keywords and strings of its previous message. Lines starting
+// with a string continue the string of the last keyword.
+func parsePrevious(lines []string) (po.PreviousMessage, error) {
+	var prev po.PreviousMessage
+	var current *string
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
 			continue
 		}
-		switch {
-		case locationRegex.MatchString(t.String()):
-			matches := locationRegex.FindStringSubmatch(t.String())
-			parts := strings.SplitN(matches[1], ":", 2)
-			line := -1
-			if parts[1] != "" {
-				line, err = strconv.Atoi(parts[1])
-				if err != nil {
-					return fmt.Errorf("error parsing integer: %w", err)
-				}
-			}
 
-			loc := po.Location{
-				Line: line,
-				File: parts[0],
+		if !strings.HasPrefix(line, `"`) {
+			keyword, rest, _ := strings.Cut(line, " ")
+			switch keyword {
+			case "msgctxt":
+				current = &prev.Context
+			case "msgid":
+				current = &prev.ID
+			case "msgid_plural":
+				current = &prev.Plural
+			default:
+				return prev, fmt.Errorf("unknown keyword %q in previous message", keyword)
 			}
-			entry.Locations = append(entry.Locations, loc)
-		case extractedRegex.MatchString(t.String()):
-			entry.ExtractedComments = append(entry.ExtractedComments,
-				extractedRegex.FindStringSubmatch(t.String())[1],
-			)
-		case flagRegex.MatchString(t.String()):
-			entry.Flags = append(entry.Flags,
-				flagRegex.FindStringSubmatch(t.String())[1],
-			)
-		case previousRegex.MatchString(t.String()):
-			entry.Previous = append(entry.Previous,
-				previousRegex.FindStringSubmatch(t.String())[1],
-			)
-		default:
-			entry.Comments = append(entry.Comments,
-				generalRegex.FindStringSubmatch(t.String())[1],
-			)
+			line = strings.TrimSpace(rest)
 		}
+		if current == nil {
+			return prev, fmt.Errorf("string without keyword in previous message: %s", line)
+		}
+
+		str, err := unquotePoString(line)
+		if err != nil {
+			return prev, fmt.Errorf("invalid string in previous message: %w", err)
+		}
+		*current += str
+	}
+
+	return prev, nil
+}
+
+// unquotePoString removes the quotes of a PO string and resolves its escape sequences.
+func unquotePoString(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("%s is not a quoted string", s)
+	}
+	str, err := strconv.Unquote(s)
+	if err != nil {
+		// Sequences like \' are not valid in Go, keep the raw content.
+		return s[1 : len(s)-1], nil
 	}
 
-	return nil
+	return str, nil
 }
 
 // ParseWithOptions parses the PO file with temporary configuration options.
@@ -243,37 +340,40 @@ msgstr "---"`
 func (p *PoParser) Parse() *po.File {
 	var entries po.Entries
 	p.errors = nil
+	p.warns = nil
 
 	// NOTE: REAAALYY there isn't a better way to do this???
 	// bruh; limitations of the parser, but it was this, or the
 	// unnecessary heavy and slow thing of ANTLR.
 
 	// Add temporary marker to handle edge cases
-	p.data = p.originalData
+	p.data = append([]byte{}, p.decodeData(p.originalData)...)
 	p.data = append(p.data, []byte(safeTemplate)...)
 
 	// Parse the file using the participle parser
-	pFile, err := util.PoParser.ParseBytes(p.filename, p.data)
+	pFile, err := util.NewPoParser(p.obsoletePrefix()).ParseBytes(p.filename, p.data)
 	if err != nil {
-		p.error(err.Error())
-		return nil
-	}
-
-	// Remove the temporary marker entry
-	pFile.Entries = slices.Delete(pFile.Entries, len(pFile.Entries)-1, len(pFile.Entries))
-
-	// Optionally filter out all comments
-	if p.Config.IgnoreAllComments {
-		pFile.Tokens = slices.DeleteFunc(pFile.Tokens, func(t lexer.Token) bool {
-			return t.Type == util.PoSymbols["Comment"]
-		})
+		pFile = p.recoverEntries(err)
+	} else {
+		// Remove the temporary marker entry
+		pFile.Entries = slices.Delete(pFile.Entries, len(pFile.Entries)-1, len(pFile.Entries))
 	}
 
 	// Process each entry in the parsed file
 	for _, e := range pFile.Entries {
+		obsolete, consistent := isObsolete(e.Tokens)
+		if !consistent {
+			p.errorAt(e.Pos.Line, e.Pos.Column, "",
+				fmt.Errorf("inconsistent use of #%c in the lines of the entry", p.obsoletePrefix()))
+		}
+		if obsolete && !p.Config.ParseObsoletes {
+			continue
+		}
+
+		// Optionally filter out all comments
 		if p.Config.IgnoreAllComments {
 			e.Tokens = slices.DeleteFunc(e.Tokens, func(t lexer.Token) bool {
-				return t.Type == util.PoSymbols["Comment"]
+				return t.Type == util.PoSymbols["Comment"] || t.Type == util.PoSymbols["ObsoletePrevious"]
 			})
 		}
 
@@ -283,7 +383,7 @@ func (p *PoParser) Parse() *po.File {
 			ID:       strings.Join(e.ID, ""),
 			Str:      strings.Join(e.Str, ""),
 			Plural:   strings.Join(e.MsgidPlural, ""),
-			Obsolete: p.Config.markAllAsObsolete,
+			Obsolete: obsolete,
 		}
 
 		// Process plural forms
@@ -299,26 +399,19 @@ func (p *PoParser) Parse() *po.File {
 		// Parse comments associated with this entry
 		err = parseComments(&newEntry, e.Tokens)
 		if err != nil {
-			p.error(err.Error())
+			p.errorAt(e.Pos.Line, e.Pos.Column, "", err)
 		}
 
 		// Optionally filter out comments
 		if p.Config.IgnoreComments {
 			newEntry.Comments = nil
 			newEntry.ExtractedComments = nil
-			newEntry.Previous = nil
+			newEntry.Previous = po.PreviousMessage{}
 		}
 
 		entries = append(entries, newEntry)
 	}
 
-	// Process obsolete entries if configured to do so
-	if slices.ContainsFunc(pFile.Tokens, func(t lexer.Token) bool {
-		return t.Type == util.PoSymbols["Comment"] && obsoleteRegex.MatchString(t.String())
-	}) && p.Config.ParseObsoletes {
-		entries = append(entries, p.parseObsoleteEntries(pFile.Tokens)...)
-	}
-
 	// Apply post-processing options
 	if p.Config.SkipHeader {
 		i := entries.Index("", "")
@@ -333,8 +426,82 @@ func (p *PoParser) Parse() *po.File {
 		entries = entries.CleanDuplicates()
 	}
 
-	return &po.File{
+	file := &po.File{
 		Entries: entries,
 		Name:    p.filename,
 	}
+	if p.Config.KeepSyntax && len(p.errors) == 0 {
+		file.Syntax = p.parseSyntax()
+	}
+
+	return file
+}
+
+// parseSyntax splits the decoded data into the source text of each entry.
+func (p *PoParser) parseSyntax() *po.Syntax {
+	data := p.data[:len(p.data)-len(safeTemplate)]
+	s := NewPoScanner(bytes.NewReader(data), p.filename,
+		PoWithConfig(p.Config),
+		PoWithSkipHeader(false),
+		PoWithParseObsolete(true),
+	)
+	// The data was already decoded.
+	s.decoder = nil
+
+	var blocks []po.SyntaxBlock
+	for {
+		entry, err := s.Next()
+		if errors.Is(err, io.EOF) {
+			return po.NewSyntax(blocks, s.Text())
+		}
+		if err != nil {
+			p.warn("the original syntax can't be kept: %v", err)
+			return nil
+		}
+		blocks = append(blocks, po.NewSyntaxBlock(s.Text(), entry))
+	}
+}
+
+// recoverEntries parses the file again block by block, the blocks being
+// separated by blank lines, so an invalid entry doesn't hide the entries
+// and the problems of the rest of the file. err is the error of the
+// parse of the whole file, reported if no block fails.
+func (p *PoParser) recoverEntries(err error) *util.PoFile {
+	recovered := &util.PoFile{}
+	errorsCount := len(p.errors)
+
+	lines := strings.SplitAfter(string(p.data[:len(p.data)-len(safeTemplate)]), "\n")
+	start := 0
+	for i := 0; i <= len(lines); i++ {
+		if i < len(lines) && strings.TrimSpace(lines[i]) != "" {
+			continue
+		}
+		if start < i {
+			p.parseBlock(recovered, strings.Join(lines[start:i], ""), start)
+		}
+		start = i + 1
+	}
+
+	if len(p.errors) == errorsCount {
+		p.syntaxError(err, 0)
+	}
+
+	return recovered
+}
+
+// parseBlock parses a block of lines of the file that starts after
+// lineOffset lines, appending its entries to f.
+func (p *PoParser) parseBlock(f *util.PoFile, block string, lineOffset int) {
+	bFile, err := util.NewPoParser(p.obsoletePrefix()).ParseString(p.filename, block+"\n"+safeTemplate)
+	if err != nil {
+		p.syntaxError(err, lineOffset)
+		return
+	}
+	bFile.Entries = slices.Delete(bFile.Entries, len(bFile.Entries)-1, len(bFile.Entries))
+
+	for i := range bFile.Entries {
+		bFile.Entries[i].Pos.Line += lineOffset
+	}
+	f.Entries = append(f.Entries, bFile.Entries...)
+	f.Tokens = append(f.Tokens, bFile.Tokens...)
 }
@@ -1,6 +1,8 @@
 package parse_test
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/Tom5521/gotext-tools/v2/internal/util"
@@ -46,3 +48,301 @@ func TestPoParser(t *testing.T) {
 		t.Log(util.NamedDiff("parsed", "expected", parsed.Entries, input.Entries))
 	}
 }
+
+func TestPoParserFlags(t *testing.T) {
+	const input = `#, fuzzy, go-format
+#, no-wrap,  go-format, range: 0..10
+msgid "%d apple"
+msgstr "%d manzana"
+`
+
+	parser := parse.NewPoFromString(input, "test.po")
+	parsed := parser.Parse()
+	if parser.Error() != nil {
+		t.Fatal(parser.Error())
+	}
+
+	entry := parsed.Entries[0]
+	expected := []string{"fuzzy", "go-format", "no-wrap", "range: 0..10"}
+	if !util.Equal(entry.Flags, expected) {
+		t.Errorf("expected flags %q, got %q", expected, entry.Flags)
+	}
+	if !entry.IsFuzzy() || !entry.IsNoWrap() {
+		t.Error("expected a fuzzy and no-wrap entry")
+	}
+	if r, ok := entry.Range(); !ok || r != (po.FlagRange{Min: 0, Max: 10}) {
+		t.Errorf("unexpected range %v", r)
+	}
+
+	compiled := compile.NewPo(parsed, compile.PoWithOmitHeader(true)).ToString()
+	if !strings.Contains(compiled, "#, fuzzy, go-format, range: 0..10, no-wrap\n") {
+		t.Errorf("flags were not written canonically:\n%s", compiled)
+	}
+}
+
+func TestPoParserReferences(t *testing.T) {
+	var locations po.Locations
+	for i := 1; i <= 12; i++ {
+		locations = append(locations, po.Location{File: "internal/some/long/path.go", Line: i * 100})
+	}
+	locations = append(locations,
+		po.Location{File: "with space.go", Line: 1},
+		po.Location{File: "no-line.go", Line: -1},
+	)
+
+	input := &po.File{Entries: po.Entries{{ID: "Hello", Str: "Hola", Locations: locations}}}
+	compiled := compile.NewPo(input, compile.PoWithOmitHeader(true)).ToString()
+
+	for _, line := range strings.Split(compiled, "\n") {
+		if strings.HasPrefix(line, "#:") && len([]rune(line)) > 79 {
+			t.Errorf("reference line is too long: %q", line)
+		}
+	}
+	if strings.Count(compiled, "#:") >= len(locations) {
+		t.Errorf("references were not packed:\n%s", compiled)
+	}
+
+	parser := parse.NewPoFromString(compiled, "test.po")
+	parsed := parser.Parse()
+	if parser.Error() != nil {
+		t.Fatal(parser.Error())
+	}
+	if !util.Equal(parsed.Entries[0].Locations, locations) {
+		t.Log(util.NamedDiff("parsed", "expected", parsed.Entries[0].Locations, locations))
+		t.Error("locations differ after a round trip")
+	}
+}
+
+func TestPoParserPrevious(t *testing.T) {
+	const input = `#, fuzzy
+#| msgctxt "menu"
+#| msgid ""
+#| "Open the\n"
+#| "\"old\" file"
+#| msgid_plural "Open the files"
+msgctxt "menu"
+msgid "Open the file"
+msgid_plural "Open the files"
+msgstr[0] "Abre el archivo"
+msgstr[1] "Abre los archivos"
+`
+
+	parser := parse.NewPoFromString(input, "test.po")
+	parsed := parser.Parse()
+	if parser.Error() != nil {
+		t.Fatal(parser.Error())
+	}
+
+	expected := po.PreviousMessage{
+		Context: "menu",
+		ID:      "Open the\n\"old\" file",
+		Plural:  "Open the files",
+	}
+	if parsed.Entries[0].Previous != expected {
+		t.Fatalf("expected %#v, got %#v", expected, parsed.Entries[0].Previous)
+	}
+
+	compiled := compile.NewPo(parsed, compile.PoWithOmitHeader(true)).ToString()
+	if !strings.Contains(compiled, `#| msgid ""`+"\n"+`#| "Open the\n"`+"\n"+`#| "\"old\" file"`+"\n") {
+		t.Errorf("previous msgid was not written with continuation lines:\n%s", compiled)
+	}
+
+	reparsed := parse.NewPoFromString(compiled, "test.po").Parse()
+	if reparsed.Entries[0].Previous != expected {
+		t.Errorf("previous message differs after a round trip: %#v", reparsed.Entries[0].Previous)
+	}
+}
+
+func TestPoParserCharset(t *testing.T) {
+	input := &po.File{
+		Entries: po.Entries{
+			{Str: "Content-Type: text/plain; charset=UTF-8\nLanguage: ru\n"},
+			{ID: "File", Str: "Файл"},
+			{Context: "меню", ID: "Open", Str: "Открыть"},
+		},
+	}
+
+	for _, charset := range []string{"CP1251", "KOI8-R", "UTF-8"} {
+		data := compile.PoToBytes(input, compile.PoWithCharset(charset))
+		if charset != "UTF-8" && strings.Contains(string(data), "Файл") {
+			t.Errorf("%s: output was not encoded", charset)
+		}
+
+		parser := parse.NewPoFromBytes(data, "test.po")
+		parsed := parser.Parse()
+		if parser.Error() != nil {
+			t.Fatalf("%s: %v", charset, parser.Error())
+		}
+		if parsed.Header().Charset() != charset {
+			t.Errorf("%s: unexpected header charset %q", charset, parsed.Header().Charset())
+		}
+		if !util.Equal(parsed.Entries[1:], input.Entries[1:]) {
+			t.Errorf("%s: compiled and parsed differ", charset)
+			t.Log(util.NamedDiff("parsed", "expected", parsed.Entries[1:], input.Entries[1:]))
+		}
+	}
+
+	var buf strings.Builder
+	err := compile.PoToWriter(input, &buf, compile.PoWithCharset("ISO-8859-1"))
+	if err == nil {
+		t.Error("expected an error converting cyrillic text to ISO-8859-1")
+	}
+}
+
+func TestPoParserErrors(t *testing.T) {
+	const input = `msgid ""
+msgstr "Content-Type: text/plain; charset=X-UNKNOWN\n"
+
+msgid "one"
+msgstr "uno"
+
+msgid "two"
+msgstr[x] "dos"
+
+msgid "three"
+msgstr "tres"
+
+msgid "four"
+msgid "cuatro"
+`
+
+	parser := parse.NewPoFromString(input, "test.po")
+	parsed := parser.Parse()
+
+	errs := parser.Errors()
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	expected := []struct{ line, column int }{{8, 8}, {14, 1}}
+	for i, e := range expected {
+		var perr *po.ParseError
+		if !errors.As(errs[i], &perr) {
+			t.Fatalf("expected a ParseError, got %T", errs[i])
+		}
+		if perr.Filename != "test.po" || perr.Line != e.line || perr.Column != e.column ||
+			perr.Severity != po.SeverityError {
+			t.Errorf("unexpected error %#v", perr)
+		}
+	}
+
+	if parsed == nil || len(parsed.Entries) != 3 || parsed.Entries[2].ID != "three" {
+		t.Errorf("expected the valid entries to be parsed, got %v", parsed)
+	}
+
+	warns := parser.Warnings()
+	var perr *po.ParseError
+	if len(warns) != 1 || !errors.As(warns[0], &perr) || perr.Severity != po.SeverityWarning {
+		t.Errorf("expected a charset warning, got %v", warns)
+	}
+}
+
+func TestPoParserObsolete(t *testing.T) {
+	const input = `msgid "Open"
+msgstr "Abrir"
+
+# Translator comment.
+#. Extracted comment.
+#: main.go:10
+#, fuzzy, c-format
+#~| msgctxt "menu"
+#~| msgid "Close the %s file"
+#~ msgctxt "menu"
+#~ msgid "Close %s"
+#~ msgstr "Cerrar %s"
+
+#~ msgid "file"
+#~ msgid_plural "files"
+#~ msgstr[0] "archivo"
+#~ msgstr[1] ""
+#~ "archivos"
+
+msgid "Save"
+msgstr "Guardar"
+`
+
+	expected := po.Entries{
+		{ID: "Open", Str: "Abrir"},
+		{
+			Comments:          []string{"Translator comment."},
+			ExtractedComments: []string{"Extracted comment."},
+			Locations:         []po.Location{{File: "main.go", Line: 10}},
+			Flags:             []string{"fuzzy", "c-format"},
+			Previous:          po.PreviousMessage{Context: "menu", ID: "Close the %s file"},
+			Context:           "menu",
+			ID:                "Close %s",
+			Str:               "Cerrar %s",
+			Obsolete:          true,
+		},
+		{
+			ID:       "file",
+			Plural:   "files",
+			Plurals:  po.PluralEntries{{ID: 0, Str: "archivo"}, {ID: 1, Str: "archivos"}},
+			Obsolete: true,
+		},
+		{ID: "Save", Str: "Guardar"},
+	}
+
+	parser := parse.NewPoFromString(input, "test.po")
+	parsed := parser.Parse()
+	if err := parser.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if !util.Equal(parsed.Entries, expected) {
+		t.Error("unexpected obsolete entries")
+		t.Log(util.NamedDiff("parsed", "expected", parsed.Entries, expected))
+	}
+
+	compiled := compile.PoToString(parsed, compile.PoWithOmitHeader(true))
+	if !strings.Contains(compiled, "#~| msgid \"Close the %s file\"\n") {
+		t.Errorf("previous message of obsolete entry not written as #~|:\n%s", compiled)
+	}
+	if reparsed := parse.NewPoFromString(compiled, "test.po").Parse(); !util.Equal(reparsed.Entries, expected) {
+		t.Error("obsolete entries changed in a round trip")
+		t.Log(util.NamedDiff("reparsed", "expected", reparsed.Entries, expected))
+	}
+
+	parsed = parse.NewPoFromString(input, "test.po", parse.PoWithParseObsolete(false)).Parse()
+	if len(parsed.Entries) != 2 || parsed.Entries[1].ID != "Save" {
+		t.Errorf("unexpected entries without ParseObsoletes: %v", parsed.Entries)
+	}
+
+	custom := strings.ReplaceAll(input, "#~", "#!")
+	parsed = parse.NewPoFromString(custom, "test.po",
+		parse.PoWithUseCustomObsoletePrefix(true),
+		parse.PoWithCustomObsoletePrefix('!'),
+	).Parse()
+	if !util.Equal(parsed.Entries, expected) {
+		t.Error("unexpected obsolete entries with a custom prefix")
+		t.Log(util.NamedDiff("parsed", "expected", parsed.Entries, expected))
+	}
+}
+
+func TestPoParserObsoleteErrors(t *testing.T) {
+	const input = `#~ msgid "one"
+#~ msgstr[x] "uno"
+
+#~ msgid "two"
+msgstr "dos"
+
+#~ msgid "three"
+#~ msgstr "tres"
+`
+
+	parser := parse.NewPoFromString(input, "test.po")
+	parsed := parser.Parse()
+
+	errs := parser.Errors()
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	for i, line := range []int{2, 4} {
+		var perr *po.ParseError
+		if !errors.As(errs[i], &perr) || perr.Line != line {
+			t.Errorf("expected an error in line %d, got %v", line, errs[i])
+		}
+	}
+
+	if i := parsed.Index("three", ""); i == -1 || !parsed.Entries[i].Obsolete {
+		t.Errorf("expected the valid obsolete entry to be parsed, got %v", parsed.Entries)
+	}
+}
@@ -0,0 +1,392 @@
+package parse
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Tom5521/gotext-tools/v2/internal/util"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+	"golang.org/x/text/encoding"
+)
+
+// scannerPeekSize is the amount of data inspected at the start of the file
+// to find the charset declared in the header.
+const scannerPeekSize = 64 << 10
+
+// PoScanner reads the entries of a PO file one at a time, keeping in memory
+// only the entry being read, so it can walk files of any size.
+//
+// Unlike [PoParser], duplicated entries are not removed ([PoConfig.CleanDuplicates]
+// is ignored).
+type PoScanner struct {
+	Config PoConfig // Configuration for parsing behavior
+
+	reader   *bufio.Reader
+	decoder  *encoding.Decoder // Decoder of the charset of the file, nil for UTF-8.
+	charset  string            // Charset of the decoder.
+	filename string            // Name of the source file
+	line     int               // Number of the last line read
+	lastLine string            // Content of the line being processed
+	text     strings.Builder   // Source text of the entry being read
+	warns    []error           // Collection of non-critical warnings
+
+	pending    string // Line read but not consumed by the previous entry
+	hasPending bool
+
+	skippedHeader bool
+	err           error // Error that stops the scanner, like a read error
+}
+
+// NewPoScanner creates a new PoScanner that reads the PO file from r.
+// The file is decoded from the charset declared in its header.
+func NewPoScanner(r io.Reader, name string, options ...PoOption) *PoScanner {
+	s := &PoScanner{
+		Config:   DefaultPoConfig(options...),
+		reader:   bufio.NewReaderSize(r, scannerPeekSize),
+		filename: name,
+	}
+	s.detectCharset()
+
+	return s
+}
+
+// detectCharset looks for the charset in the start of the file.
+func (s *PoScanner) detectCharset() {
+	data, _ := s.reader.Peek(scannerPeekSize)
+	matches := charsetRegex.FindSubmatch(data)
+	if matches == nil {
+		return
+	}
+	charset := string(matches[1])
+	if charset == "CHARSET" || util.IsUTF8Charset(charset) {
+		return
+	}
+
+	enc, ok := util.CharsetEncoding(charset)
+	if !ok {
+		s.warn("unsupported charset %q, reading the file as UTF-8", charset)
+		return
+	}
+	s.decoder = enc.NewDecoder()
+	s.charset = charset
+}
+
+func (s *PoScanner) warn(format string, a ...any) {
+	err := &po.ParseError{
+		Filename: s.filename,
+		Line:     s.line,
+		Severity: po.SeverityWarning,
+		Reason:   fmt.Errorf(format, a...),
+	}
+
+	if s.Config.Logger != nil && s.Config.Verbose {
+		s.Config.Logger.Println(err)
+	}
+
+	s.warns = append(s.warns, err)
+}
+
+// Warnings returns the non-critical warnings found so far.
+func (s *PoScanner) Warnings() []error {
+	return s.warns
+}
+
+// errorf returns a [po.ParseError] in the current line, at the column of token if it isn't empty.
+func (s *PoScanner) errorf(token, format string, a ...any) error {
+	err := &po.ParseError{
+		Filename: s.filename,
+		Line:     s.line,
+		Token:    token,
+		Severity: po.SeverityError,
+		Reason:   fmt.Errorf(format, a...),
+	}
+	if token != "" {
+		err.Column = strings.Index(s.lastLine, token) + 1
+	}
+
+	return err
+}
+
+// readLine returns the next line of the file, including its line break.
+func (s *PoScanner) readLine() (string, error) {
+	if s.hasPending {
+		s.hasPending = false
+		return s.pending, nil
+	}
+
+	data, err := s.reader.ReadBytes('\n')
+	if err != nil && (err != io.EOF || len(data) == 0) {
+		return "", err
+	}
+	s.line++
+
+	if s.decoder != nil {
+		// The decoders replace the invalid bytes with U+FFFD instead of
+		// failing, so those lines are reported too.
+		decoded, derr := s.decoder.Bytes(data)
+		switch {
+		case derr != nil:
+			s.warn("the line can't be decoded from %s, reading it as is: %v", s.charset, derr)
+		case bytes.ContainsRune(decoded, utf8.RuneError):
+			s.warn("the line has characters that aren't valid in %s", s.charset)
+			data = decoded
+		default:
+			data = decoded
+		}
+	}
+
+	return string(data), nil
+}
+
+func (s *PoScanner) unreadLine(line string) {
+	s.pending = line
+	s.hasPending = true
+}
+
+// Text returns the source text read for the entry returned by the last call
+// to [PoScanner.Next]: the blank lines and comments before it and the lines
+// of its keywords and strings, with their line breaks. After [io.EOF] it
+// returns the text that follows the last entry.
+//
+// The text is decoded to UTF-8 if the file uses a different charset.
+func (s *PoScanner) Text() string {
+	return s.text.String()
+}
+
+// scanState holds the entry being read.
+type scanState struct {
+	entry    po.Entry
+	previous []string
+	current  *string // String that continuation lines are appended to.
+	hasID    bool
+	hasStr   bool
+	hasLine  bool // Whether a keyword or string line was read.
+}
+
+// Next returns the next entry of the file, or [io.EOF] when there are no more.
+//
+// Syntax errors are reported with the number of the line; the entry that
+// contains it is discarded, and the following call continues with the next
+// line. Read errors are returned by every following call.
+func (s *PoScanner) Next() (po.Entry, error) {
+	s.text.Reset()
+	for {
+		entry, err := s.next()
+		if err != nil {
+			return po.Entry{}, err
+		}
+
+		if entry.Obsolete && !s.Config.ParseObsoletes {
+			continue
+		}
+		if entry.IsHeader() && !entry.Obsolete && s.Config.SkipHeader && !s.skippedHeader {
+			s.skippedHeader = true
+			continue
+		}
+
+		return entry, nil
+	}
+}
+
+func (s *PoScanner) next() (po.Entry, error) {
+	if s.err != nil {
+		return po.Entry{}, s.err
+	}
+
+	var state scanState
+	for {
+		line, err := s.readLine()
+		s.lastLine = line
+		if err != nil {
+			if err != io.EOF {
+				s.err = fmt.Errorf("parse: error reading %s: %w", s.filename, err)
+				return po.Entry{}, s.err
+			}
+			if state.hasStr {
+				return s.finish(state)
+			}
+			if state.hasID {
+				return po.Entry{}, s.errorf("", "unexpected end of file, missing msgstr")
+			}
+			return po.Entry{}, io.EOF
+		}
+
+		content, obsolete := s.cutObsoletePrefix(strings.TrimSpace(line))
+
+		// A blank line, a comment or a new message after a msgstr starts the next entry.
+		startsEntry := content == "" && !obsolete ||
+			content != "" && (content[0] == '#' || isEntryStart(content))
+		if state.hasStr && startsEntry {
+			s.unreadLine(line)
+			return s.finish(state)
+		}
+
+		s.text.WriteString(line)
+		if content == "" {
+			continue
+		}
+
+		if content[0] == '#' {
+			if !s.Config.IgnoreAllComments {
+				parseComment(&state.entry, &state.previous, content)
+			}
+			continue
+		}
+
+		if err = s.scanLine(&state, content, obsolete); err != nil {
+			s.skipEntry()
+			return po.Entry{}, err
+		}
+	}
+}
+
+// cutObsoletePrefix removes the "#~" prefix of the lines of obsolete entries.
+// The previous message of obsolete entries ("#~|") is returned as a "#|" comment.
+func (s *PoScanner) cutObsoletePrefix(line string) (string, bool) {
+	prefix := s.obsoletePrefix()
+	if !strings.HasPrefix(line, prefix) {
+		return line, false
+	}
+
+	line = strings.TrimSpace(strings.TrimPrefix(line, prefix))
+	if strings.HasPrefix(line, "|") {
+		line = "#" + line
+	}
+
+	return line, true
+}
+
+// obsoletePrefix returns the prefix of the lines of obsolete entries.
+func (s *PoScanner) obsoletePrefix() string {
+	if s.Config.UseCustomObsoletePrefix {
+		return "#" + string(s.Config.CustomObsoletePrefix)
+	}
+	return "#~"
+}
+
+func isEntryStart(line string) bool {
+	keyword, _ := cutKeyword(line)
+	return keyword == "msgctxt" || keyword == "msgid"
+}
+
+// cutKeyword splits a line into its keyword and the rest of the line.
+func cutKeyword(line string) (keyword, rest string) {
+	i := strings.IndexAny(line, " \t\"")
+	if i == -1 {
+		return line, ""
+	}
+	return line[:i], strings.TrimSpace(line[i:])
+}
+
+// scanLine processes a keyword or string line of an entry.
+func (s *PoScanner) scanLine(state *scanState, line string, obsolete bool) error {
+	if state.hasLine && obsolete != state.entry.Obsolete {
+		return s.errorf("", "inconsistent use of %s in the lines of the entry", s.obsoletePrefix())
+	}
+	state.hasLine = true
+	state.entry.Obsolete = obsolete
+
+	if line[0] == '"' {
+		if state.current == nil {
+			return s.errorf(line, "string without keyword")
+		}
+		str, err := unquotePoString(line)
+		if err != nil {
+			return s.errorf(line, "%w", err)
+		}
+		*state.current += str
+		return nil
+	}
+
+	keyword, rest := cutKeyword(line)
+	switch {
+	case keyword == "msgctxt":
+		if state.hasID {
+			return s.errorf(keyword, "msgctxt after msgid")
+		}
+		state.current = &state.entry.Context
+	case keyword == "msgid":
+		if state.hasID {
+			return s.errorf(keyword, "msgid without msgstr")
+		}
+		state.hasID = true
+		state.current = &state.entry.ID
+	case keyword == "msgid_plural":
+		if !state.hasID || state.hasStr {
+			return s.errorf(keyword, "unexpected msgid_plural")
+		}
+		state.current = &state.entry.Plural
+	case keyword == "msgstr":
+		if !state.hasID {
+			return s.errorf(keyword, "msgstr without msgid")
+		}
+		state.hasStr = true
+		state.current = &state.entry.Str
+	case strings.HasPrefix(keyword, "msgstr["):
+		if !state.hasID {
+			return s.errorf(keyword, "msgstr without msgid")
+		}
+		index, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(keyword, "msgstr["), "]"))
+		if err != nil || !strings.HasSuffix(keyword, "]") {
+			return s.errorf(keyword, "invalid plural index")
+		}
+		state.hasStr = true
+		state.entry.Plurals = append(state.entry.Plurals, po.PluralEntry{ID: index})
+		state.current = &state.entry.Plurals[len(state.entry.Plurals)-1].Str
+	default:
+		return s.errorf(keyword, "unknown keyword %q", keyword)
+	}
+
+	str, err := unquotePoString(rest)
+	if err != nil {
+		return s.errorf(rest, "%w", err)
+	}
+	*state.current += str
+
+	return nil
+}
+
+// skipEntry discards the lines of the entry that contains a syntax error,
+// up to the next blank line.
+func (s *PoScanner) skipEntry() {
+	for {
+		line, err := s.readLine()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				s.err = fmt.Errorf("parse: error reading %s: %w", s.filename, err)
+			}
+			return
+		}
+		if strings.TrimSpace(line) == "" {
+			s.unreadLine(line)
+			return
+		}
+		s.text.WriteString(line)
+	}
+}
+
+// finish applies the comments and configuration to the entry that was read.
+func (s *PoScanner) finish(state scanState) (po.Entry, error) {
+	entry := state.entry
+	if len(state.previous) > 0 {
+		var err error
+		entry.Previous, err = parsePrevious(state.previous)
+		if err != nil {
+			return po.Entry{}, s.errorf("", "%w", err)
+		}
+	}
+
+	if s.Config.IgnoreComments {
+		entry.Comments = nil
+		entry.ExtractedComments = nil
+		entry.Previous = po.PreviousMessage{}
+	}
+
+	return entry, nil
+}
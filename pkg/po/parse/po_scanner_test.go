@@ -0,0 +1,132 @@
+package parse_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/v2/internal/util"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po/compile"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po/parse"
+)
+
+func scanAll(t *testing.T, s *parse.PoScanner) po.Entries {
+	t.Helper()
+
+	var entries po.Entries
+	for {
+		entry, err := s.Next()
+		if errors.Is(err, io.EOF) {
+			return entries
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+}
+
+func TestPoScanner(t *testing.T) {
+	input := &po.File{
+		Entries: po.Entries{
+			{Str: "Content-Type: text/plain; charset=UTF-8\nLanguage: es\n"},
+			{
+				Flags:             []string{"fuzzy", "go-format"},
+				Comments:          []string{"Hello World"},
+				ExtractedComments: []string{"TRANSLATORS: greeting"},
+				Previous:          po.PreviousMessage{ID: "Hi"},
+				Locations:         po.Locations{{File: "main.go", Line: 10}, {File: "a b.go", Line: 3}},
+				ID:                "Hello\n\"%s\"", Str: "Hola\n\"%s\"",
+			},
+			{Context: "CTX", ID: "MEOW", Str: "MIAU"},
+			{
+				ID:      "Apple",
+				Plural:  "Apples",
+				Plurals: po.PluralEntries{{ID: 0, Str: "Manzana"}, {ID: 1, Str: "Manzanas"}},
+			},
+			{ID: "Untranslated"},
+			{ID: "MyObsoleteEntry", Str: "Obsoleta", Obsolete: true},
+		},
+	}
+	data := compile.PoToString(input, compile.PoWithWordWrap(true))
+
+	parser := parse.NewPoFromString(data, "test.po")
+	expected := parser.Parse()
+	if parser.Error() != nil {
+		t.Fatal(parser.Error())
+	}
+
+	got := scanAll(t, parse.NewPoScanner(strings.NewReader(data), "test.po"))
+	if !util.Equal(got, expected.Entries) {
+		t.Error("scanned and parsed entries differ")
+		t.Log(util.NamedDiff("scanned", "parsed", got, expected.Entries))
+	}
+
+	got = scanAll(t, parse.NewPoScanner(strings.NewReader(data), "test.po",
+		parse.PoWithSkipHeader(true), parse.PoWithParseObsolete(false)))
+	if !util.Equal(got, input.Entries[1:5]) {
+		t.Error("unexpected entries with SkipHeader and without ParseObsoletes")
+		t.Log(util.NamedDiff("scanned", "expected", got, input.Entries[1:5]))
+	}
+}
+
+func TestPoScannerCharset(t *testing.T) {
+	input := po.Entries{
+		{Str: "Content-Type: text/plain; charset=UTF-8\n"},
+		{ID: "File", Str: "Файл"},
+	}
+	data := compile.PoToBytes(input, compile.PoWithCharset("KOI8-R"))
+
+	got := scanAll(t, parse.NewPoScanner(strings.NewReader(string(data)), "test.po"))
+	if len(got) != 2 || got[1].Str != "Файл" {
+		t.Errorf("unexpected entries %v", got)
+	}
+}
+
+func TestPoScannerInvalidCharset(t *testing.T) {
+	// 0x81 isn't a character of Shift_JIS.
+	data := "msgid \"\"\nmsgstr \"\"\n\"Content-Type: text/plain; charset=Shift_JIS\\n\"\n\n" +
+		"msgid \"File\"\nmsgstr \"\x81\"\n"
+
+	s := parse.NewPoScanner(strings.NewReader(data), "test.po")
+	scanAll(t, s)
+
+	warns := s.Warnings()
+	if len(warns) != 1 {
+		t.Fatalf("expected 1 warning, got %v", warns)
+	}
+	var perr *po.ParseError
+	if !errors.As(warns[0], &perr) || perr.Severity != po.SeverityWarning || perr.Line != 6 {
+		t.Errorf("unexpected warning %v", warns[0])
+	}
+}
+
+func TestPoScannerErrors(t *testing.T) {
+	const input = `msgid "one"
+msgstr "uno"
+
+msgid "two"
+msgstr "dos" trailing
+
+msgid "three"
+msgstr "tres"
+`
+
+	s := parse.NewPoScanner(strings.NewReader(input), "test.po")
+	entry, err := s.Next()
+	if err != nil || entry.ID != "one" {
+		t.Fatalf("unexpected result: %v, %v", entry, err)
+	}
+	if _, err = s.Next(); err == nil || !strings.Contains(err.Error(), "test.po:5") {
+		t.Fatalf("expected an error in line 5, got %v", err)
+	}
+	entry, err = s.Next()
+	if err != nil || entry.ID != "three" {
+		t.Fatalf("expected to continue after the error: %v, %v", entry, err)
+	}
+	if _, err = s.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
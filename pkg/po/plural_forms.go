@@ -0,0 +1,315 @@
+package po
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// PluralFunc returns the index of the plural form that must be used for n.
+type PluralFunc func(n uint64) int
+
+// pluralNode is a compiled sub-expression of a Plural-Forms formula.
+type pluralNode func(n uint64) uint64
+
+type pluralTokenKind int
+
+const (
+	pluralTokenEOF pluralTokenKind = iota
+	pluralTokenN
+	pluralTokenNumber
+	pluralTokenOperator
+	pluralTokenLParen
+	pluralTokenRParen
+	pluralTokenQuestion
+	pluralTokenColon
+)
+
+type pluralToken struct {
+	Kind   pluralTokenKind
+	Value  string
+	Number uint64
+	Column int // 1-based column of the first character of the token.
+}
+
+// pluralOperators lists the binary operators grouped by precedence,
+// from the lowest to the highest, like the gettext C grammar does.
+var pluralOperators = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", ">", "<=", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+// CompilePluralForms compiles the plural expression of a Plural-Forms header
+// (the part after "plural=") into a function that can be evaluated for any n.
+//
+// The grammar supported is the C subset used by gettext: the variable n,
+// unsigned integer literals, parentheses, the unary operator '!', the binary
+// operators '*', '/', '%', '+', '-', '<', '>', '<=', '>=', '==', '!=', '&&',
+// '||' and the ternary operator '?:'.
+//
+// The returned function does not limit the result to the number of plurals,
+// use [Header.PluralFunc] for that.
+func CompilePluralForms(expr string) (PluralFunc, error) {
+	p := &pluralParser{expr: expr}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+
+	node, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.Kind != pluralTokenEOF {
+		return nil, p.errorf(tok, "unexpected %q", tok.Value)
+	}
+
+	return func(n uint64) int { return int(node(n)) }, nil
+}
+
+type pluralParser struct {
+	expr   string
+	tokens []pluralToken
+	pos    int
+}
+
+func (p *pluralParser) errorf(tok pluralToken, format string, a ...any) error {
+	return &PluralFormsError{
+		Expr:   p.expr,
+		Column: tok.Column,
+		Reason: fmt.Sprintf(format, a...),
+	}
+}
+
+func (p *pluralParser) tokenize() error {
+	expr := p.expr
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		column := i + 1
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == 'n':
+			p.tokens = append(p.tokens, pluralToken{Kind: pluralTokenN, Value: "n", Column: column})
+			i++
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(expr) && expr[i] >= '0' && expr[i] <= '9' {
+				i++
+			}
+			number, err := strconv.ParseUint(expr[start:i], 10, 64)
+			if err != nil {
+				return &PluralFormsError{Expr: expr, Column: column, Reason: "number out of range"}
+			}
+			p.tokens = append(p.tokens, pluralToken{
+				Kind:   pluralTokenNumber,
+				Value:  expr[start:i],
+				Number: number,
+				Column: column,
+			})
+		case c == '(':
+			p.tokens = append(p.tokens, pluralToken{Kind: pluralTokenLParen, Value: "(", Column: column})
+			i++
+		case c == ')':
+			p.tokens = append(p.tokens, pluralToken{Kind: pluralTokenRParen, Value: ")", Column: column})
+			i++
+		case c == '?':
+			p.tokens = append(p.tokens, pluralToken{Kind: pluralTokenQuestion, Value: "?", Column: column})
+			i++
+		case c == ':':
+			p.tokens = append(p.tokens, pluralToken{Kind: pluralTokenColon, Value: ":", Column: column})
+			i++
+		default:
+			op := pluralOperatorAt(expr[i:])
+			if op == "" {
+				return &PluralFormsError{
+					Expr:   expr,
+					Column: column,
+					Reason: fmt.Sprintf("unexpected character %q", c),
+				}
+			}
+			p.tokens = append(p.tokens, pluralToken{Kind: pluralTokenOperator, Value: op, Column: column})
+			i += len(op)
+		}
+	}
+
+	p.tokens = append(p.tokens, pluralToken{Kind: pluralTokenEOF, Value: "end of expression", Column: len(expr) + 1})
+
+	return nil
+}
+
+// pluralOperatorAt returns the operator at the beginning of s, or an empty string.
+func pluralOperatorAt(s string) string {
+	if len(s) >= 2 {
+		switch s[:2] {
+		case "||", "&&", "==", "!=", "<=", ">=":
+			return s[:2]
+		}
+	}
+	switch s[0] {
+	case '!', '<', '>', '+', '-', '*', '/', '%':
+		return s[:1]
+	}
+	return ""
+}
+
+func (p *pluralParser) peek() pluralToken {
+	return p.tokens[p.pos]
+}
+
+func (p *pluralParser) next() pluralToken {
+	tok := p.tokens[p.pos]
+	if tok.Kind != pluralTokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// parseTernary parses "cond ? a : b", which is right associative.
+func (p *pluralParser) parseTernary() (pluralNode, error) {
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().Kind != pluralTokenQuestion {
+		return cond, nil
+	}
+	p.next()
+
+	ifTrue, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.next(); tok.Kind != pluralTokenColon {
+		return nil, p.errorf(tok, "expected ':' but found %q", tok.Value)
+	}
+	ifFalse, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(n uint64) uint64 {
+		if cond(n) != 0 {
+			return ifTrue(n)
+		}
+		return ifFalse(n)
+	}, nil
+}
+
+// parseBinary parses the left associative binary operators of the given
+// precedence level and all the levels above it.
+func (p *pluralParser) parseBinary(level int) (pluralNode, error) {
+	if level == len(pluralOperators) {
+		return p.parseUnary()
+	}
+
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok := p.peek()
+		if tok.Kind != pluralTokenOperator || !containsOperator(pluralOperators[level], tok.Value) {
+			return left, nil
+		}
+		p.next()
+
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = pluralBinaryNode(tok.Value, left, right)
+	}
+}
+
+func containsOperator(ops []string, op string) bool {
+	for _, o := range ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *pluralParser) parseUnary() (pluralNode, error) {
+	tok := p.next()
+	switch tok.Kind {
+	case pluralTokenN:
+		return func(n uint64) uint64 { return n }, nil
+	case pluralTokenNumber:
+		value := tok.Number
+		return func(uint64) uint64 { return value }, nil
+	case pluralTokenLParen:
+		node, err := p.parseTernary()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.Kind != pluralTokenRParen {
+			return nil, p.errorf(closing, "expected ')' but found %q", closing.Value)
+		}
+		return node, nil
+	case pluralTokenOperator:
+		if tok.Value == "!" {
+			operand, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			return func(n uint64) uint64 { return pluralBool(operand(n) == 0) }, nil
+		}
+	}
+
+	return nil, p.errorf(tok, "unexpected %q", tok.Value)
+}
+
+func pluralBool(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func pluralBinaryNode(op string, left, right pluralNode) pluralNode {
+	switch op {
+	case "||":
+		return func(n uint64) uint64 { return pluralBool(left(n) != 0 || right(n) != 0) }
+	case "&&":
+		return func(n uint64) uint64 { return pluralBool(left(n) != 0 && right(n) != 0) }
+	case "==":
+		return func(n uint64) uint64 { return pluralBool(left(n) == right(n)) }
+	case "!=":
+		return func(n uint64) uint64 { return pluralBool(left(n) != right(n)) }
+	case "<":
+		return func(n uint64) uint64 { return pluralBool(left(n) < right(n)) }
+	case ">":
+		return func(n uint64) uint64 { return pluralBool(left(n) > right(n)) }
+	case "<=":
+		return func(n uint64) uint64 { return pluralBool(left(n) <= right(n)) }
+	case ">=":
+		return func(n uint64) uint64 { return pluralBool(left(n) >= right(n)) }
+	case "+":
+		return func(n uint64) uint64 { return left(n) + right(n) }
+	case "-":
+		return func(n uint64) uint64 { return left(n) - right(n) }
+	case "*":
+		return func(n uint64) uint64 { return left(n) * right(n) }
+	case "/":
+		// A division by zero has no meaningful result, the
+		// first plural form is used instead of panicking.
+		return func(n uint64) uint64 {
+			if r := right(n); r != 0 {
+				return left(n) / r
+			}
+			return 0
+		}
+	default: // "%"
+		return func(n uint64) uint64 {
+			if r := right(n); r != 0 {
+				return left(n) % r
+			}
+			return 0
+		}
+	}
+}
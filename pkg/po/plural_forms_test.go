@@ -0,0 +1,94 @@
+package po_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+)
+
+func TestCompilePluralForms(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		expected map[uint64]int
+	}{
+		{"Germanic", "(n != 1)", map[uint64]int{0: 1, 1: 0, 2: 1, 100: 1}},
+		{"French", "(n > 1)", map[uint64]int{0: 0, 1: 0, 2: 1}},
+		{"Japanese", "0", map[uint64]int{0: 0, 1: 0, 50: 0}},
+		{
+			"Polish",
+			"(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2)",
+			map[uint64]int{1: 0, 2: 1, 4: 1, 5: 2, 12: 2, 22: 1, 25: 2, 112: 2, 122: 1},
+		},
+		{
+			"Arabic",
+			"(n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5)",
+			map[uint64]int{0: 0, 1: 1, 2: 2, 3: 3, 10: 3, 11: 4, 99: 4, 100: 5, 102: 5, 103: 3},
+		},
+		{"Arithmetic", "n+1-2*3/3", map[uint64]int{2: 1, 5: 4}},
+		{"Not", "!(n == 1)", map[uint64]int{1: 0, 2: 1}},
+		{"DivisionByZero", "n / 0", map[uint64]int{7: 0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f, err := po.CompilePluralForms(test.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for n, expected := range test.expected {
+				if got := f(n); got != expected {
+					t.Errorf("n=%d: expected %d, got %d", n, expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCompilePluralFormsErrors(t *testing.T) {
+	tests := []struct {
+		expr   string
+		column int
+	}{
+		{"n ==", 5},
+		{"(n != 1", 8},
+		{"n ? 1", 6},
+		{"n $ 1", 3},
+		{"n 1", 3},
+		{"", 1},
+	}
+
+	for _, test := range tests {
+		_, err := po.CompilePluralForms(test.expr)
+		var perr *po.PluralFormsError
+		if !errors.As(err, &perr) {
+			t.Errorf("%q: expected a *po.PluralFormsError, got %v", test.expr, err)
+			continue
+		}
+		if perr.Column != test.column {
+			t.Errorf("%q: expected column %d, got %d (%v)", test.expr, test.column, perr.Column, err)
+		}
+	}
+}
+
+func TestHeaderPluralFunc(t *testing.T) {
+	h := po.Header{}
+	h.Set("Plural-Forms", "nplurals=2; plural=n;")
+
+	f, err := h.PluralFunc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f(1) != 1 || f(5) != 0 {
+		t.Error("indexes out of nplurals must fall back to 0")
+	}
+
+	f, err = po.Header{}.PluralFunc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f(1) != 0 || f(2) != 1 {
+		t.Error("the default plural rule must be (n != 1)")
+	}
+}
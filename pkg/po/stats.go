@@ -0,0 +1,131 @@
+package po
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Tom5521/gotext-tools/v2/internal/slices"
+)
+
+// Stats holds the number of messages of a catalog in each translation state,
+// and the size of their source strings.
+//
+// The header is not counted. Obsolete entries are only counted in Obsolete,
+// so Translated + Fuzzy + Untranslated is the number of active messages.
+type Stats struct {
+	Translated   int
+	Fuzzy        int
+	Untranslated int
+	Obsolete     int
+
+	// Words and characters of the msgid and msgid_plural of the active messages.
+	SourceWords int
+	SourceChars int
+
+	// Source words of the messages in each state.
+	TranslatedWords   int
+	FuzzyWords        int
+	UntranslatedWords int
+}
+
+// Total returns the number of active (non obsolete) messages.
+func (s Stats) Total() int {
+	return s.Translated + s.Fuzzy + s.Untranslated
+}
+
+// Completeness returns the percentage of active messages that are translated.
+// A catalog without messages is complete.
+func (s Stats) Completeness() float64 {
+	if s.Total() == 0 {
+		return 100
+	}
+	return float64(s.Translated) * 100 / float64(s.Total())
+}
+
+// String returns the statistics in the format used by msgfmt --statistics:
+// "N translated messages, M fuzzy translations, K untranslated messages."
+// Fuzzy and untranslated counts are omitted when they're zero.
+func (s Stats) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d %s", s.Translated, pluralize(s.Translated, "translated message", "translated messages"))
+	if s.Fuzzy > 0 {
+		fmt.Fprintf(&b, ", %d %s", s.Fuzzy, pluralize(s.Fuzzy, "fuzzy translation", "fuzzy translations"))
+	}
+	if s.Untranslated > 0 {
+		fmt.Fprintf(&b, ", %d %s", s.Untranslated,
+			pluralize(s.Untranslated, "untranslated message", "untranslated messages"))
+	}
+	b.WriteByte('.')
+
+	return b.String()
+}
+
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+// IsTranslated reports whether the entry has a translation, that is,
+// a non empty msgstr or, for plural entries, non empty msgstr[i] for every form.
+// Fuzzy entries can also be translated.
+func (e Entry) IsTranslated() bool {
+	if !e.IsPlural() {
+		return e.Str != ""
+	}
+	if len(e.Plurals) == 0 {
+		return false
+	}
+	for _, pe := range e.Plurals {
+		if pe.Str == "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasTranslation reports whether any string of the translation is non empty.
+func (e Entry) hasTranslation() bool {
+	return e.Str != "" || slices.ContainsFunc(e.Plurals, func(pe PluralEntry) bool { return pe.Str != "" })
+}
+
+// Stats counts the translated, fuzzy, untranslated and obsolete entries.
+// Like msgfmt, entries without any translation are untranslated even if
+// they're fuzzy, and plural entries are only translated if all their forms
+// are (see [Entry.IsTranslated]).
+func (e Entries) Stats() Stats {
+	var s Stats
+	for _, entry := range e {
+		if entry.IsHeader() {
+			continue
+		}
+		if entry.Obsolete {
+			s.Obsolete++
+			continue
+		}
+
+		words := len(strings.Fields(entry.ID)) + len(strings.Fields(entry.Plural))
+		s.SourceWords += words
+		s.SourceChars += utf8.RuneCountInString(entry.ID) + utf8.RuneCountInString(entry.Plural)
+
+		switch {
+		case !entry.hasTranslation():
+			s.Untranslated++
+			s.UntranslatedWords += words
+		case entry.IsFuzzy():
+			s.Fuzzy++
+			s.FuzzyWords += words
+		case entry.IsTranslated():
+			s.Translated++
+			s.TranslatedWords += words
+		default:
+			s.Untranslated++
+			s.UntranslatedWords += words
+		}
+	}
+
+	return s
+}
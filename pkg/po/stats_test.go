@@ -0,0 +1,65 @@
+package po_test
+
+import (
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+)
+
+func TestStats(t *testing.T) {
+	entries := po.Entries{
+		{Str: "Language: es\n"},
+		{ID: "Open file", Str: "Abrir archivo"},
+		{ID: "Save", Str: "Guardar", Flags: []string{"fuzzy"}},
+		{ID: "Close all", Flags: []string{"fuzzy"}},
+		{ID: "Quit"},
+		{
+			ID:     "%d file",
+			Plural: "%d files",
+			Plurals: po.PluralEntries{
+				{ID: 0, Str: "%d archivo"},
+				{ID: 1, Str: "%d archivos"},
+			},
+		},
+		{
+			ID:     "%d día",
+			Plural: "%d days",
+			Plurals: po.PluralEntries{
+				{ID: 0, Str: "%d día"},
+				{ID: 1, Str: ""},
+			},
+		},
+		{ID: "Old", Str: "Viejo", Obsolete: true},
+	}
+
+	expected := po.Stats{
+		Translated:        2,
+		Fuzzy:             1,
+		Untranslated:      3,
+		Obsolete:          1,
+		SourceWords:       14,
+		SourceChars:       54,
+		TranslatedWords:   6,
+		FuzzyWords:        1,
+		UntranslatedWords: 7,
+	}
+
+	s := entries.Stats()
+	if s != expected {
+		t.Errorf("expected %#v, got %#v", expected, s)
+	}
+	if s.Total() != 6 {
+		t.Errorf("expected 6 messages, got %d", s.Total())
+	}
+	if c := s.Completeness(); c < 33.3 || c > 33.4 {
+		t.Errorf("unexpected completeness %f", c)
+	}
+
+	const str = "2 translated messages, 1 fuzzy translation, 3 untranslated messages."
+	if s.String() != str {
+		t.Errorf("expected %q, got %q", str, s.String())
+	}
+	if got := (po.Stats{Translated: 1}).String(); got != "1 translated message." {
+		t.Errorf("unexpected string %q", got)
+	}
+}
@@ -0,0 +1,96 @@
+package po
+
+import "strings"
+
+// Syntax is the original text of a PO file split in the blocks of its entries.
+//
+// The parser keeps it when asked to (see parse.PoWithKeepSyntax) so the
+// compiler can write the entries that weren't modified exactly as they
+// were read, and re-render only the ones that changed.
+type Syntax struct {
+	Blocks  []SyntaxBlock
+	Trailer string // Text after the last entry, like blank lines or trailing comments.
+
+	// Index of the blocks by their key, built by NewSyntax for indexed.
+	index   map[string]int
+	indexed []SyntaxBlock
+}
+
+// NewSyntax returns the syntax of a file with the blocks indexed for
+// [Syntax.Lookup].
+func NewSyntax(blocks []SyntaxBlock, trailer string) *Syntax {
+	s := &Syntax{
+		Blocks:  blocks,
+		Trailer: trailer,
+		index:   make(map[string]int, len(blocks)),
+		indexed: blocks,
+	}
+	for i, b := range blocks {
+		key := syntaxKey(b.Entry)
+		if _, ok := s.index[key]; !ok {
+			s.index[key] = i
+		}
+	}
+
+	return s
+}
+
+// SyntaxBlock is the source text of an entry and the entry parsed from it.
+type SyntaxBlock struct {
+	Leading string // Blank lines before the entry.
+	Text    string // Comments, keywords and strings of the entry.
+	Entry   Entry  // The entry as it was parsed from Text.
+}
+
+// NewSyntaxBlock splits the source text of an entry into its leading blank
+// lines and the rest of its text.
+func NewSyntaxBlock(text string, entry Entry) SyntaxBlock {
+	var leading int
+	for leading < len(text) {
+		end := strings.IndexByte(text[leading:], '\n')
+		if end == -1 || strings.TrimSpace(text[leading:leading+end]) != "" {
+			break
+		}
+		leading += end + 1
+	}
+
+	return SyntaxBlock{
+		Leading: text[:leading],
+		Text:    text[leading:],
+		Entry:   entry,
+	}
+}
+
+func syntaxKey(e Entry) string {
+	if e.Obsolete {
+		return "~" + e.UnifiedID()
+	}
+	return " " + e.UnifiedID()
+}
+
+// Lookup returns the block of the entry with the same context, ID and
+// obsolete state as e. If several blocks match, the first one is returned.
+//
+// The index built by NewSyntax is only used while Blocks is the same slice,
+// otherwise the blocks are searched one by one.
+func (s *Syntax) Lookup(e Entry) (SyntaxBlock, bool) {
+	key := syntaxKey(e)
+	if s.index != nil && sameSlice(s.Blocks, s.indexed) {
+		i, ok := s.index[key]
+		if !ok {
+			return SyntaxBlock{}, false
+		}
+		return s.Blocks[i], true
+	}
+
+	for _, b := range s.Blocks {
+		if syntaxKey(b.Entry) == key {
+			return b, true
+		}
+	}
+	return SyntaxBlock{}, false
+}
+
+func sameSlice(a, b []SyntaxBlock) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
@@ -0,0 +1,43 @@
+package po_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+)
+
+func TestSyntaxLookup(t *testing.T) {
+	syntax := po.NewSyntax([]po.SyntaxBlock{
+		po.NewSyntaxBlock("msgid \"Hello\"\nmsgstr \"Hola\"\n", po.Entry{ID: "Hello", Str: "Hola"}),
+		po.NewSyntaxBlock("\n#~ msgid \"Hello\"\n#~ msgstr \"Viejo\"\n", po.Entry{ID: "Hello", Obsolete: true}),
+	}, "")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if b, ok := syntax.Lookup(po.Entry{ID: "Hello"}); !ok || b.Entry.Str != "Hola" {
+				t.Errorf("unexpected block %+v", b)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if b, ok := syntax.Lookup(po.Entry{ID: "Hello", Obsolete: true}); !ok || b.Leading != "\n" {
+		t.Errorf("unexpected obsolete block %+v", b)
+	}
+	if _, ok := syntax.Lookup(po.Entry{ID: "Bye"}); ok {
+		t.Error("found a block of a missing entry")
+	}
+
+	// The index isn't used once the blocks are replaced.
+	syntax.Blocks = []po.SyntaxBlock{po.NewSyntaxBlock("msgid \"Bye\"\nmsgstr \"\"\n", po.Entry{ID: "Bye"})}
+	if _, ok := syntax.Lookup(po.Entry{ID: "Hello"}); ok {
+		t.Error("found a block that was removed")
+	}
+	if _, ok := syntax.Lookup(po.Entry{ID: "Bye"}); !ok {
+		t.Error("the block of a new entry wasn't found")
+	}
+}
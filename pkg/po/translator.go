@@ -0,0 +1,161 @@
+package po
+
+import (
+	"strings"
+)
+
+// Translator provides runtime lookup of translations from a catalog.
+//
+// It is built once from a [File] (obtained from a PO or MO file) and
+// is safe for concurrent use, since it is never modified after creation.
+type Translator struct {
+	header       Header
+	plural       PluralFunc
+	translations map[string][]string
+}
+
+// NewTranslator builds a Translator from the entries of f.
+//
+// Fuzzy, obsolete and untranslated entries are skipped, so lookups of
+// their IDs fall back to the original strings. An error is returned if
+// the Plural-Forms field of the header cannot be compiled.
+func NewTranslator(f *File) (*Translator, error) {
+	return NewTranslatorFromEntries(f.Entries)
+}
+
+// NewTranslatorFromEntries builds a Translator from entries.
+// See [NewTranslator] for details.
+func NewTranslatorFromEntries(entries Entries) (*Translator, error) {
+	header := entries.Header()
+	plural, err := header.PluralFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Translator{
+		header:       header,
+		plural:       plural,
+		translations: make(map[string][]string, len(entries)),
+	}
+
+	for _, e := range entries {
+		if e.IsHeader() || e.IsFuzzy() || e.Obsolete {
+			continue
+		}
+		strs := translationStrings(e)
+		if strs == nil {
+			continue
+		}
+		t.translations[e.UnifiedID()] = strs
+	}
+
+	return t, nil
+}
+
+// translationStrings returns the translated strings of e indexed by plural form,
+// or nil if the entry has no translation at all.
+func translationStrings(e Entry) []string {
+	if len(e.Plurals) == 0 {
+		if e.Str == "" {
+			return nil
+		}
+		return []string{e.Str}
+	}
+
+	var size int
+	for _, pe := range e.Plurals {
+		if pe.ID >= size {
+			size = pe.ID + 1
+		}
+	}
+
+	var translated bool
+	strs := make([]string, size)
+	for _, pe := range e.Plurals {
+		if pe.ID < 0 {
+			continue
+		}
+		strs[pe.ID] = pe.Str
+		if pe.Str != "" {
+			translated = true
+		}
+	}
+	if !translated {
+		return nil
+	}
+
+	return strs
+}
+
+// Header returns the header of the catalog.
+func (t *Translator) Header() Header {
+	return t.header
+}
+
+// Language returns the value of the Language header field.
+func (t *Translator) Language() string {
+	return strings.TrimSpace(t.header.Load("Language"))
+}
+
+// Len returns the number of translated messages in the catalog.
+func (t *Translator) Len() int {
+	return len(t.translations)
+}
+
+// Get returns the translation of id, or id itself if there is none.
+func (t *Translator) Get(id string) string {
+	return t.GetC(id, "")
+}
+
+// GetC returns the translation of id in the given context,
+// or id itself if there is none.
+func (t *Translator) GetC(id, context string) string {
+	strs, ok := t.lookup(id, context)
+	if !ok || strs[0] == "" {
+		return id
+	}
+	return strs[0]
+}
+
+// GetN returns the plural form of the translation of id that corresponds to n.
+// If there is no translation, it returns id when n is 1 and plural otherwise.
+func (t *Translator) GetN(id, plural string, n int) string {
+	return t.GetNC(id, plural, n, "")
+}
+
+// GetNC is like [Translator.GetN] but looks up the message in the given context.
+func (t *Translator) GetNC(id, plural string, n int, context string) string {
+	// Negative values wrap around like the unsigned long of ngettext(3).
+	un := uint64(n)
+
+	strs, ok := t.lookup(id, context)
+	if !ok {
+		return fallbackPlural(id, plural, un)
+	}
+
+	i := t.plural(un)
+	if i >= len(strs) {
+		i = 0
+	}
+	if strs[i] == "" {
+		return fallbackPlural(id, plural, un)
+	}
+
+	return strs[i]
+}
+
+func (t *Translator) lookup(id, context string) ([]string, bool) {
+	uid := id
+	if context != "" {
+		uid = context + "\x04" + id
+	}
+	strs, ok := t.translations[uid]
+	return strs, ok
+}
+
+func fallbackPlural(id, plural string, n uint64) string {
+	if n == 1 {
+		return id
+	}
+	return plural
+}
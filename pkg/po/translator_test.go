@@ -0,0 +1,105 @@
+package po_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po/compile"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po/parse"
+)
+
+func TestTranslator(t *testing.T) {
+	header := po.DefaultHeaderConfig(
+		po.HeaderWithNplurals(3),
+		po.HeaderWithPlural(
+			"(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2)",
+		),
+	).ToHeader()
+
+	file := po.NewFile("pl.po",
+		header.ToEntry(),
+		po.Entry{ID: "Hello", Str: "Cześć"},
+		po.Entry{ID: "Open", Context: "menu", Str: "Otwórz"},
+		po.Entry{ID: "Fuzzy", Str: "Rozmyte", Flags: []string{"fuzzy"}},
+		po.Entry{ID: "Old", Str: "Stare", Obsolete: true},
+		po.Entry{ID: "Untranslated"},
+		po.Entry{
+			ID:     "%d file",
+			Plural: "%d files",
+			Plurals: po.PluralEntries{
+				{ID: 0, Str: "%d plik"},
+				{ID: 1, Str: "%d pliki"},
+				{ID: 2, Str: "%d plików"},
+			},
+		},
+	)
+
+	mo, err := parse.MoFromBytes(compile.MoToBytes(file), "pl.mo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sources := []struct {
+		name string
+		file *po.File
+	}{
+		{"Po", file},
+		{"Mo", mo},
+	}
+
+	for _, source := range sources {
+		t.Run(source.name, func(t *testing.T) {
+			tr, err := po.NewTranslator(source.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tests := []struct {
+				got, expected string
+			}{
+				{tr.Get("Hello"), "Cześć"},
+				{tr.Get("Open"), "Open"},
+				{tr.GetC("Open", "menu"), "Otwórz"},
+				{tr.Get("Fuzzy"), "Fuzzy"},
+				{tr.Get("Old"), "Old"},
+				{tr.Get("Untranslated"), "Untranslated"},
+				{tr.GetN("%d file", "%d files", 1), "%d plik"},
+				{tr.GetN("%d file", "%d files", 3), "%d pliki"},
+				{tr.GetN("%d file", "%d files", 5), "%d plików"},
+				{tr.GetN("%d file", "%d files", 22), "%d pliki"},
+				{tr.GetN("%d dir", "%d dirs", 1), "%d dir"},
+				{tr.GetN("%d dir", "%d dirs", 2), "%d dirs"},
+				{tr.GetNC("%d file", "%d files", 2, "ctx"), "%d files"},
+			}
+
+			for i, test := range tests {
+				if test.got != test.expected {
+					t.Errorf("%d: expected %q, got %q", i, test.expected, test.got)
+				}
+			}
+		})
+	}
+}
+
+func TestTranslatorConcurrentReads(t *testing.T) {
+	tr, err := po.NewTranslatorFromEntries(po.Entries{{ID: "a", Str: "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				if tr.Get("a") != "b" {
+					t.Error("unexpected translation")
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
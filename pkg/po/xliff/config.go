@@ -0,0 +1,90 @@
+package xliff
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+)
+
+// DefaultSourceLang is the language of the source messages if the
+// configuration doesn't set one.
+const DefaultSourceLang = "en"
+
+// Config holds the configuration used to write XLIFF documents.
+type Config struct {
+	// Version of the XLIFF document, [Version12] or [Version20].
+	Version Version
+	// SourceLang is the language of the msgids.
+	SourceLang string
+	// TargetLang is the language of the translations, the Language
+	// of the header is used if empty.
+	TargetLang string
+	// Original is the name of the translated file, the base name of
+	// the PO file is used if empty.
+	Original string
+}
+
+// DefaultConfig returns the default configuration, optionally modified by options.
+func DefaultConfig(opts ...Option) Config {
+	c := Config{
+		Version:    Version12,
+		SourceLang: DefaultSourceLang,
+	}
+	c.ApplyOptions(opts...)
+
+	return c
+}
+
+// ApplyOptions applies the options to the configuration.
+func (c *Config) ApplyOptions(opts ...Option) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+func (c Config) targetLang(entries po.Entries) string {
+	if c.TargetLang != "" {
+		return c.TargetLang
+	}
+	header := entries.Header()
+	return strings.TrimSpace(header.Load("Language"))
+}
+
+func (c Config) original(f *po.File) string {
+	switch {
+	case c.Original != "":
+		return c.Original
+	case f.Name != "" && f.Name != "-":
+		return filepath.Base(f.Name)
+	}
+	return "messages.po"
+}
+
+// Option modifies a Config.
+type Option func(c *Config)
+
+// WithConfig returns an Option that replaces the configuration.
+func WithConfig(config Config) Option {
+	return func(c *Config) { *c = config }
+}
+
+// WithVersion returns an Option that sets the version of the XLIFF document.
+func WithVersion(v Version) Option {
+	return func(c *Config) { c.Version = v }
+}
+
+// WithSourceLang returns an Option that sets the language of the msgids.
+func WithSourceLang(lang string) Option {
+	return func(c *Config) { c.SourceLang = lang }
+}
+
+// WithTargetLang returns an Option that sets the language of the translations.
+func WithTargetLang(lang string) Option {
+	return func(c *Config) { c.TargetLang = lang }
+}
+
+// WithOriginal returns an Option that sets the name of the translated file.
+func WithOriginal(name string) Option {
+	return func(c *Config) { c.Original = name }
+}
@@ -0,0 +1,28 @@
+package xliff
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrNotXLIFF = errors.New("the document isn't a XLIFF document")
+
+// UnsupportedVersionError is a XLIFF version that can't be read or written.
+type UnsupportedVersionError struct {
+	Version string
+}
+
+func (e *UnsupportedVersionError) Error() string {
+	return fmt.Sprintf("unsupported XLIFF version %q", e.Version)
+}
+
+// InvalidCharError is a character of an entry that XML 1.0 can't represent,
+// like most control characters.
+type InvalidCharError struct {
+	ID   string // msgid of the entry.
+	Char rune
+}
+
+func (e *InvalidCharError) Error() string {
+	return fmt.Sprintf("entry %q: the character %U can't be written in XML 1.0", e.ID, e.Char)
+}
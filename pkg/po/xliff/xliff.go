@@ -0,0 +1,404 @@
+// Package xliff reads and writes PO entries as XLIFF 1.2 and 2.0 documents,
+// the format accepted by most translation vendors and CAT tools.
+//
+// Every entry is written as a translation unit whose source is the msgid
+// and whose target is the translation. Plural entries are written as a
+// group with a unit per plural form. The rest of the fields of the entries
+// are kept so that reading the document back loses no data:
+//
+//   - Fuzzy translations have a "needs review" state.
+//   - Translator and extracted comments are notes.
+//   - Locations are context groups (metadata groups in XLIFF 2.0).
+//   - The msgctxt, flags, previous messages and obsolete state are kept
+//     in an extra context group (or metadata group).
+//
+// The header is written as a unit with an empty source that must not be translated.
+package xliff
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Tom5521/gotext-tools/v2/internal/slices"
+	"github.com/Tom5521/gotext-tools/v2/internal/util"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+)
+
+// Version is a version of the XLIFF standard.
+type Version string
+
+const (
+	Version12 Version = "1.2"
+	Version20 Version = "2.0"
+)
+
+func file[T po.EntriesOrFile](i T) *po.File {
+	f := new(po.File)
+	switch v := any(i).(type) {
+	case *po.File:
+		f = v
+	case po.Entries:
+		f.Entries = v
+	case po.File:
+		*f = v
+	}
+
+	return f
+}
+
+// ToWriter writes the entries as a XLIFF document. It returns an
+// [InvalidCharError] without writing anything if an entry has a character
+// that XML 1.0 can't represent.
+func ToWriter[T po.EntriesOrFile](f T, w io.Writer, opts ...Option) error {
+	config := DefaultConfig(opts...)
+	if err := checkChars(file(f).Entries); err != nil {
+		return err
+	}
+
+	var doc any
+	switch config.Version {
+	case Version12:
+		doc = newDocument12(file(f), config)
+	case Version20:
+		doc = newDocument20(file(f), config)
+	default:
+		return &UnsupportedVersionError{Version: string(config.Version)}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+// checkChars returns an error for the first string of the entries that
+// encoding/xml would write with a replacement character.
+func checkChars(entries po.Entries) error {
+	for _, e := range entries {
+		strs := []string{e.ID, e.Context, e.Plural, e.Str, e.Previous.ID, e.Previous.Context, e.Previous.Plural}
+		strs = append(strs, e.Flags...)
+		strs = append(strs, e.Comments...)
+		strs = append(strs, e.ExtractedComments...)
+		for _, pe := range e.Plurals {
+			strs = append(strs, pe.Str)
+		}
+		for _, l := range e.Locations {
+			strs = append(strs, l.File)
+		}
+
+		for _, str := range strs {
+			if r, ok := util.InvalidXMLChar(str); ok {
+				return &InvalidCharError{ID: e.ID, Char: r}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ToBytes returns the entries as a XLIFF document.
+func ToBytes[T po.EntriesOrFile](f T, opts ...Option) ([]byte, error) {
+	var buf bytes.Buffer
+	err := ToWriter(f, &buf, opts...)
+	return buf.Bytes(), err
+}
+
+// ToFile writes the entries to a XLIFF file.
+func ToFile[T po.EntriesOrFile](f T, path string, opts ...Option) error {
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if err = ToWriter(f, out, opts...); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// Parse reads the entries of a XLIFF file, see [ParseBytes].
+func Parse(path string) (*po.File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBytes(data, path)
+}
+
+// ParseReader reads the entries of a XLIFF document, see [ParseBytes].
+func ParseReader(r io.Reader, name string) (*po.File, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBytes(data, name)
+}
+
+// ParseBytes reads the entries of a XLIFF document of any 1.x or 2.x
+// version. The entries of all the files of the document are returned.
+//
+// Units that weren't written by this package are read as entries too,
+// and units whose target needs a review become fuzzy.
+func ParseBytes(data []byte, name string) (*po.File, error) {
+	version, err := documentVersion(data)
+	if err == nil {
+		var entries po.Entries
+		switch {
+		case strings.HasPrefix(version, "1."):
+			entries, err = parse12(data)
+		case strings.HasPrefix(version, "2."):
+			entries, err = parse20(data)
+		default:
+			err = &UnsupportedVersionError{Version: version}
+		}
+		if err == nil {
+			return po.NewFile(name, entries...), nil
+		}
+	}
+
+	return nil, &po.InvalidFileError{Filename: name, Reason: err}
+}
+
+// documentVersion returns the version of the root element of a XLIFF document.
+func documentVersion(data []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return "", ErrNotXLIFF
+		}
+		if err != nil {
+			return "", err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local != "xliff" {
+			return "", ErrNotXLIFF
+		}
+		for _, attr := range start.Attr {
+			if attr.Name.Local == "version" {
+				return attr.Value, nil
+			}
+		}
+		return "", &UnsupportedVersionError{}
+	}
+}
+
+// text is the content of a <source> or <target>. The text of its inline
+// elements, like <g> and <ph>, is part of it.
+type text string
+
+func (t *text) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var b strings.Builder
+	for depth := 1; depth > 0; {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch tok := token.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			b.Write(tok)
+		}
+	}
+	*t = text(b.String())
+
+	return nil
+}
+
+// Names of the groups and properties used to keep the fields of the entries.
+const (
+	groupEntry    = "po-entry"
+	groupLocation = "po-reference"
+
+	propContext         = "x-po-msgctxt"
+	propFlags           = "x-po-flags"
+	propPreviousContext = "x-po-previous-msgctxt"
+	propPreviousID      = "x-po-previous-msgid"
+	propPreviousPlural  = "x-po-previous-msgid-plural"
+	propObsolete        = "x-po-obsolete"
+	propSourceFile      = "sourcefile"
+	propLineNumber      = "linenumber"
+
+	noteTranslator = "translator"
+	noteDeveloper  = "developer"
+)
+
+// prop is a property of an entry, written as a <context> or a <meta>.
+type prop struct {
+	Type, Value string
+}
+
+func entryProps(e po.Entry) []prop {
+	var props []prop
+	add := func(typ, value string) {
+		if value != "" {
+			props = append(props, prop{typ, value})
+		}
+	}
+
+	add(propContext, e.Context)
+	add(propFlags, strings.Join(e.Flags, ", "))
+	add(propPreviousContext, e.Previous.Context)
+	add(propPreviousID, e.Previous.ID)
+	add(propPreviousPlural, e.Previous.Plural)
+	if e.Obsolete {
+		add(propObsolete, "yes")
+	}
+
+	return props
+}
+
+func applyEntryProps(e *po.Entry, props []prop) {
+	for _, p := range props {
+		switch p.Type {
+		case propContext:
+			e.Context = p.Value
+		case propFlags:
+			for _, flag := range strings.Split(p.Value, ",") {
+				if flag = strings.TrimSpace(flag); flag != "" {
+					e.Flags = append(e.Flags, flag)
+				}
+			}
+		case propPreviousContext:
+			e.Previous.Context = p.Value
+		case propPreviousID:
+			e.Previous.ID = p.Value
+		case propPreviousPlural:
+			e.Previous.Plural = p.Value
+		case propObsolete:
+			e.Obsolete = p.Value == "yes"
+		}
+	}
+}
+
+func locationProps(l po.Location) []prop {
+	props := []prop{{propSourceFile, l.File}}
+	if l.Line >= 0 {
+		props = append(props, prop{propLineNumber, strconv.Itoa(l.Line)})
+	}
+	return props
+}
+
+func locationOf(props []prop) po.Location {
+	l := po.Location{Line: -1}
+	for _, p := range props {
+		switch p.Type {
+		case propSourceFile:
+			l.File = p.Value
+		case propLineNumber:
+			if line, err := strconv.Atoi(p.Value); err == nil {
+				l.Line = line
+			}
+		}
+	}
+	return l
+}
+
+// note is a note of a unit and the kind of comment it holds.
+type note struct {
+	Category, Text string
+}
+
+func entryNotes(e po.Entry) []note {
+	var notes []note
+	if len(e.Comments) > 0 {
+		notes = append(notes, note{noteTranslator, strings.Join(e.Comments, "\n")})
+	}
+	if len(e.ExtractedComments) > 0 {
+		notes = append(notes, note{noteDeveloper, strings.Join(e.ExtractedComments, "\n")})
+	}
+	return notes
+}
+
+// applyNote adds a note to the comments of e. The notes of the developer
+// are extracted comments, any other note is a translator comment.
+func applyNote(e *po.Entry, n note) {
+	lines := strings.Split(n.Text, "\n")
+	if n.Category == noteDeveloper {
+		e.ExtractedComments = append(e.ExtractedComments, lines...)
+		return
+	}
+	e.Comments = append(e.Comments, lines...)
+}
+
+// applyFuzzy marks e as fuzzy if it has a translation that needs a review.
+// Without translations the fuzzy flag, if any, is kept.
+func applyFuzzy(e *po.Entry, hasTarget, fuzzy bool) {
+	switch {
+	case !hasTarget:
+	case fuzzy:
+		e.AddFlag(po.FlagFuzzy)
+	default:
+		e.RemoveFlag(po.FlagFuzzy)
+	}
+}
+
+// pluralForm is the unit of a plural form of an entry.
+type pluralForm struct {
+	ID        int
+	Source    string
+	Target    string
+	HasTarget bool
+}
+
+// pluralForms returns the units of the plural forms of e. There are at
+// least two, so that the msgid_plural is kept even if the language has a
+// single plural form.
+func pluralForms(e po.Entry) []pluralForm {
+	plurals := slices.Clone(e.Plurals).Sort()
+	n := len(plurals)
+	if n < 2 {
+		n = 2
+	}
+
+	forms := make([]pluralForm, n)
+	for i := range forms {
+		forms[i] = pluralForm{ID: i, Source: e.Plural}
+		if i == 0 {
+			forms[i].Source = e.ID
+		}
+		if i < len(plurals) {
+			forms[i].ID = plurals[i].ID
+			forms[i].Target = plurals[i].Str
+			forms[i].HasTarget = true
+		}
+	}
+
+	return forms
+}
+
+// formID returns the number at the end of the ID of the unit of a plural
+// form, like 1 for "3[1]" or "3-1", or else its position.
+func formID(id string, position int) int {
+	id = strings.TrimSuffix(id, "]")
+	i := len(id)
+	for i > 0 && id[i-1] >= '0' && id[i-1] <= '9' {
+		i--
+	}
+	if i == 0 || i == len(id) {
+		return position
+	}
+	n, err := strconv.Atoi(id[i:])
+	if err != nil {
+		return position
+	}
+	return n
+}
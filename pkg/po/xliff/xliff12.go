@@ -0,0 +1,256 @@
+package xliff
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+)
+
+const (
+	namespace12 = "urn:oasis:names:tc:xliff:document:1.2"
+	// restypePlurals is the restype of the groups of the plural forms of an entry.
+	restypePlurals = "x-gettext-plurals"
+
+	stateTranslated12  = "translated"
+	stateNew12         = "new"
+	stateNeedsReview12 = "needs-review-translation"
+)
+
+type document12 struct {
+	XMLName xml.Name `xml:"xliff"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Version string   `xml:"version,attr"`
+	Files   []file12 `xml:"file"`
+}
+
+type file12 struct {
+	Original   string `xml:"original,attr"`
+	SourceLang string `xml:"source-language,attr"`
+	TargetLang string `xml:"target-language,attr,omitempty"`
+	DataType   string `xml:"datatype,attr"`
+	Body       body12 `xml:"body"`
+}
+
+type body12 struct {
+	Units []unit12 `xml:",any"`
+}
+
+// unit12 is a <trans-unit> or a <group>, depending on its XMLName.
+type unit12 struct {
+	XMLName       xml.Name
+	ID            string           `xml:"id,attr,omitempty"`
+	Restype       string           `xml:"restype,attr,omitempty"`
+	Translate     string           `xml:"translate,attr,omitempty"`
+	Space         string           `xml:"http://www.w3.org/XML/1998/namespace space,attr,omitempty"`
+	Source        *text            `xml:"source"`
+	Target        *target12        `xml:"target"`
+	ContextGroups []contextGroup12 `xml:"context-group"`
+	Notes         []note12         `xml:"note"`
+	Units         []unit12         `xml:",any"`
+}
+
+type target12 struct {
+	State string `xml:"state,attr,omitempty"`
+	Text  text   `xml:",chardata"`
+}
+
+func (t *target12) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "state" {
+			t.State = attr.Value
+		}
+	}
+	return t.Text.UnmarshalXML(d, start)
+}
+
+type contextGroup12 struct {
+	Name     string      `xml:"name,attr,omitempty"`
+	Purpose  string      `xml:"purpose,attr,omitempty"`
+	Contexts []context12 `xml:"context"`
+}
+
+type context12 struct {
+	Type  string `xml:"context-type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type note12 struct {
+	From string `xml:"from,attr,omitempty"`
+	Text string `xml:",chardata"`
+}
+
+func newDocument12(f *po.File, config Config) document12 {
+	file := file12{
+		Original:   config.original(f),
+		SourceLang: config.SourceLang,
+		TargetLang: config.targetLang(f.Entries),
+		DataType:   "po",
+	}
+	for i, e := range f.Entries {
+		file.Body.Units = append(file.Body.Units, newUnit12(strconv.Itoa(i+1), e))
+	}
+
+	return document12{Xmlns: namespace12, Version: string(Version12), Files: []file12{file}}
+}
+
+func state12(e po.Entry, translation string) string {
+	switch {
+	case e.IsFuzzy():
+		return stateNeedsReview12
+	case translation == "":
+		return stateNew12
+	}
+	return stateTranslated12
+}
+
+func newUnit12(id string, e po.Entry) unit12 {
+	u := unit12{ID: id, Space: "preserve"}
+	if e.IsHeader() {
+		u.Translate = "no"
+	}
+	if props := entryProps(e); len(props) > 0 {
+		u.ContextGroups = append(u.ContextGroups, newContextGroup12(groupEntry, "information", props))
+	}
+	for _, l := range e.Locations {
+		u.ContextGroups = append(u.ContextGroups, newContextGroup12(groupLocation, "location", locationProps(l)))
+	}
+	for _, n := range entryNotes(e) {
+		u.Notes = append(u.Notes, note12{From: n.Category, Text: n.Text})
+	}
+
+	if !e.IsPlural() {
+		u.XMLName.Local = "trans-unit"
+		source := text(e.ID)
+		u.Source = &source
+		if e.Str != "" {
+			u.Target = &target12{State: state12(e, e.Str), Text: text(e.Str)}
+		}
+		return u
+	}
+
+	u.XMLName.Local = "group"
+	u.Restype = restypePlurals
+	for _, form := range pluralForms(e) {
+		source := text(form.Source)
+		pu := unit12{
+			XMLName: xml.Name{Local: "trans-unit"},
+			ID:      fmt.Sprintf("%s[%d]", id, form.ID),
+			Source:  &source,
+		}
+		if form.HasTarget {
+			pu.Target = &target12{State: state12(e, form.Target), Text: text(form.Target)}
+		}
+		u.Units = append(u.Units, pu)
+	}
+
+	return u
+}
+
+func newContextGroup12(name, purpose string, props []prop) contextGroup12 {
+	group := contextGroup12{Name: name, Purpose: purpose}
+	for _, p := range props {
+		group.Contexts = append(group.Contexts, context12{Type: p.Type, Value: p.Value})
+	}
+	return group
+}
+
+func parse12(data []byte) (po.Entries, error) {
+	var doc document12
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	var entries po.Entries
+	for _, f := range doc.Files {
+		entries = appendEntries12(entries, f.Body.Units)
+	}
+	return entries, nil
+}
+
+// appendEntries12 appends the entries of units to entries. The units of
+// groups that aren't plural forms are read as if they weren't grouped.
+func appendEntries12(entries po.Entries, units []unit12) po.Entries {
+	for _, u := range units {
+		switch u.XMLName.Local {
+		case "trans-unit":
+			entries = append(entries, u.entry())
+		case "group":
+			if u.Restype == restypePlurals {
+				entries = append(entries, u.pluralEntry())
+			} else {
+				entries = appendEntries12(entries, u.Units)
+			}
+		}
+	}
+	return entries
+}
+
+// apply reads the context groups and notes of the unit into e.
+func (u unit12) apply(e *po.Entry) {
+	for _, group := range u.ContextGroups {
+		var props []prop
+		for _, c := range group.Contexts {
+			props = append(props, prop{c.Type, c.Value})
+		}
+		if group.Purpose == "location" || group.Name == groupLocation {
+			e.Locations = append(e.Locations, locationOf(props))
+			continue
+		}
+		applyEntryProps(e, props)
+	}
+	for _, n := range u.Notes {
+		applyNote(e, note{n.From, n.Text})
+	}
+}
+
+func (t *target12) needsReview() bool {
+	return t != nil && strings.HasPrefix(t.State, "needs-review")
+}
+
+func (u unit12) entry() po.Entry {
+	var e po.Entry
+	if u.Source != nil {
+		e.ID = string(*u.Source)
+	}
+	if u.Target != nil {
+		e.Str = string(u.Target.Text)
+	}
+	u.apply(&e)
+	applyFuzzy(&e, u.Target != nil, u.Target.needsReview())
+
+	return e
+}
+
+func (u unit12) pluralEntry() po.Entry {
+	var e po.Entry
+	var hasTarget, fuzzy bool
+	var forms int
+	for _, pu := range u.Units {
+		if pu.XMLName.Local != "trans-unit" {
+			continue
+		}
+		var source string
+		if pu.Source != nil {
+			source = string(*pu.Source)
+		}
+		switch forms {
+		case 0:
+			e.ID = source
+		case 1:
+			e.Plural = source
+		}
+		if pu.Target != nil {
+			hasTarget = true
+			fuzzy = fuzzy || pu.Target.needsReview()
+			e.Plurals = append(e.Plurals, po.PluralEntry{ID: formID(pu.ID, forms), Str: string(pu.Target.Text)})
+		}
+		forms++
+	}
+	u.apply(&e)
+	applyFuzzy(&e, hasTarget, fuzzy)
+
+	return e
+}
@@ -0,0 +1,273 @@
+package xliff
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+)
+
+const (
+	namespace20         = "urn:oasis:names:tc:xliff:document:2.0"
+	namespaceMetadata20 = "urn:oasis:names:tc:xliff:metadata:2.0"
+	// typePlurals is the type of the groups of the plural forms of an entry.
+	typePlurals = "gettext:plurals"
+
+	stateInitial20    = "initial"
+	stateTranslated20 = "translated"
+	// subStateNeedsReview20 is the subState of the fuzzy translations.
+	subStateNeedsReview20 = "gettext:needs-review"
+)
+
+type document20 struct {
+	XMLName xml.Name `xml:"xliff"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Version string   `xml:"version,attr"`
+	SrcLang string   `xml:"srcLang,attr"`
+	TrgLang string   `xml:"trgLang,attr,omitempty"`
+	Files   []file20 `xml:"file"`
+}
+
+type file20 struct {
+	ID       string   `xml:"id,attr"`
+	Original string   `xml:"original,attr,omitempty"`
+	Units    []unit20 `xml:",any"`
+}
+
+// unit20 is a <unit> or a <group>, depending on its XMLName.
+type unit20 struct {
+	XMLName   xml.Name
+	ID        string      `xml:"id,attr,omitempty"`
+	Type      string      `xml:"type,attr,omitempty"`
+	Translate string      `xml:"translate,attr,omitempty"`
+	Space     string      `xml:"http://www.w3.org/XML/1998/namespace space,attr,omitempty"`
+	Metadata  *metadata20 `xml:"urn:oasis:names:tc:xliff:metadata:2.0 metadata"`
+	Notes     *notes20    `xml:"notes"`
+	Segments  []segment20 `xml:"segment"`
+	Units     []unit20    `xml:",any"`
+}
+
+type metadata20 struct {
+	Groups []metaGroup20 `xml:"metaGroup"`
+}
+
+type metaGroup20 struct {
+	Category string   `xml:"category,attr,omitempty"`
+	Metas    []meta20 `xml:"meta"`
+}
+
+type meta20 struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type notes20 struct {
+	Notes []note20 `xml:"note"`
+}
+
+type note20 struct {
+	Category string `xml:"category,attr,omitempty"`
+	Text     string `xml:",chardata"`
+}
+
+type segment20 struct {
+	State    string `xml:"state,attr,omitempty"`
+	SubState string `xml:"subState,attr,omitempty"`
+	Source   text   `xml:"source"`
+	Target   *text  `xml:"target"`
+}
+
+func newDocument20(f *po.File, config Config) document20 {
+	file := file20{ID: "f1", Original: config.original(f)}
+	for i, e := range f.Entries {
+		file.Units = append(file.Units, newUnit20(strconv.Itoa(i+1), e))
+	}
+
+	return document20{
+		Xmlns:   namespace20,
+		Version: string(Version20),
+		SrcLang: config.SourceLang,
+		TrgLang: config.targetLang(f.Entries),
+		Files:   []file20{file},
+	}
+}
+
+func newSegment20(e po.Entry, source, target string, hasTarget bool) segment20 {
+	s := segment20{Source: text(source)}
+	if !hasTarget {
+		return s
+	}
+
+	t := text(target)
+	s.Target = &t
+	switch {
+	case e.IsFuzzy():
+		s.State, s.SubState = stateInitial20, subStateNeedsReview20
+	case target == "":
+		s.State = stateInitial20
+	default:
+		s.State = stateTranslated20
+	}
+	return s
+}
+
+func newUnit20(id string, e po.Entry) unit20 {
+	u := unit20{ID: id, Space: "preserve"}
+	if e.IsHeader() {
+		u.Translate = "no"
+	}
+
+	var metadata metadata20
+	if props := entryProps(e); len(props) > 0 {
+		metadata.Groups = append(metadata.Groups, newMetaGroup20(groupEntry, props))
+	}
+	for _, l := range e.Locations {
+		metadata.Groups = append(metadata.Groups, newMetaGroup20(groupLocation, locationProps(l)))
+	}
+	if len(metadata.Groups) > 0 {
+		u.Metadata = &metadata
+	}
+	if notes := entryNotes(e); len(notes) > 0 {
+		u.Notes = &notes20{}
+		for _, n := range notes {
+			u.Notes.Notes = append(u.Notes.Notes, note20{Category: n.Category, Text: n.Text})
+		}
+	}
+
+	if !e.IsPlural() {
+		u.XMLName.Local = "unit"
+		u.Segments = []segment20{newSegment20(e, e.ID, e.Str, e.Str != "")}
+		return u
+	}
+
+	u.XMLName.Local = "group"
+	u.Type = typePlurals
+	for _, form := range pluralForms(e) {
+		u.Units = append(u.Units, unit20{
+			XMLName:  xml.Name{Local: "unit"},
+			ID:       fmt.Sprintf("%s-%d", id, form.ID),
+			Segments: []segment20{newSegment20(e, form.Source, form.Target, form.HasTarget)},
+		})
+	}
+
+	return u
+}
+
+func newMetaGroup20(category string, props []prop) metaGroup20 {
+	group := metaGroup20{Category: category}
+	for _, p := range props {
+		group.Metas = append(group.Metas, meta20{Type: p.Type, Value: p.Value})
+	}
+	return group
+}
+
+func parse20(data []byte) (po.Entries, error) {
+	var doc document20
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	var entries po.Entries
+	for _, f := range doc.Files {
+		entries = appendEntries20(entries, f.Units)
+	}
+	return entries, nil
+}
+
+// appendEntries20 appends the entries of units to entries. The units of
+// groups that aren't plural forms are read as if they weren't grouped.
+func appendEntries20(entries po.Entries, units []unit20) po.Entries {
+	for _, u := range units {
+		switch u.XMLName.Local {
+		case "unit":
+			entries = append(entries, u.entry())
+		case "group":
+			if u.Type == typePlurals {
+				entries = append(entries, u.pluralEntry())
+			} else {
+				entries = appendEntries20(entries, u.Units)
+			}
+		}
+	}
+	return entries
+}
+
+// apply reads the metadata and notes of the unit into e.
+func (u unit20) apply(e *po.Entry) {
+	if u.Metadata != nil {
+		for _, group := range u.Metadata.Groups {
+			var props []prop
+			for _, m := range group.Metas {
+				props = append(props, prop{m.Type, m.Value})
+			}
+			if group.Category == groupLocation {
+				e.Locations = append(e.Locations, locationOf(props))
+				continue
+			}
+			applyEntryProps(e, props)
+		}
+	}
+	if u.Notes != nil {
+		for _, n := range u.Notes.Notes {
+			applyNote(e, note{n.Category, n.Text})
+		}
+	}
+}
+
+// content returns the source and target of the segments of the unit, and
+// whether the target needs a review: its subState says so, or it's an
+// initial translation.
+func (u unit20) content() (source, target string, hasTarget, fuzzy bool) {
+	var s, t strings.Builder
+	for _, seg := range u.Segments {
+		s.WriteString(string(seg.Source))
+		if seg.Target == nil {
+			continue
+		}
+		hasTarget = true
+		t.WriteString(string(*seg.Target))
+		fuzzy = fuzzy || strings.HasSuffix(seg.SubState, ":needs-review") ||
+			(seg.State == "" || seg.State == stateInitial20) && *seg.Target != ""
+	}
+	return s.String(), t.String(), hasTarget, fuzzy
+}
+
+func (u unit20) entry() po.Entry {
+	var e po.Entry
+	source, target, hasTarget, fuzzy := u.content()
+	e.ID, e.Str = source, target
+	u.apply(&e)
+	applyFuzzy(&e, hasTarget, fuzzy)
+
+	return e
+}
+
+func (u unit20) pluralEntry() po.Entry {
+	var e po.Entry
+	var hasTarget, fuzzy bool
+	var forms int
+	for _, pu := range u.Units {
+		if pu.XMLName.Local != "unit" {
+			continue
+		}
+		source, target, formHasTarget, formFuzzy := pu.content()
+		switch forms {
+		case 0:
+			e.ID = source
+		case 1:
+			e.Plural = source
+		}
+		if formHasTarget {
+			hasTarget = true
+			fuzzy = fuzzy || formFuzzy
+			e.Plurals = append(e.Plurals, po.PluralEntry{ID: formID(pu.ID, forms), Str: target})
+		}
+		forms++
+	}
+	u.apply(&e)
+	applyFuzzy(&e, hasTarget, fuzzy)
+
+	return e
+}
@@ -0,0 +1,187 @@
+package xliff_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po/xliff"
+)
+
+var input = po.Entries{
+	{
+		Comments: []string{"SOME DESCRIPTIVE TITLE.", ""},
+		Flags:    []string{po.FlagFuzzy},
+		Str:      "Project-Id-Version: app 1.0\nLanguage: es\nPlural-Forms: nplurals=2; plural=(n != 1);\n",
+	},
+	{
+		Comments:          []string{"Translator comment"},
+		ExtractedComments: []string{"Shown in the toolbar", "Keep it short"},
+		Flags:             []string{"c-format", "no-wrap"},
+		Locations:         po.Locations{{File: "main.go", Line: 12}, {File: "my file.go", Line: -1}},
+		ID:                "Open %s\n",
+		Str:               "Abrir %s\n",
+	},
+	{Context: "menu", ID: "Quit", Str: "Salir"},
+	{
+		Flags:    []string{po.FlagFuzzy},
+		Previous: po.PreviousMessage{Context: "old", ID: "Save the file"},
+		ID:       "Save the files",
+		Str:      "Guardar el archivo",
+	},
+	{ID: "Untranslated\t<tag> & \"quotes\""},
+	{Flags: []string{po.FlagFuzzy}, ID: "Untranslated fuzzy"},
+	{
+		ID:      "%d file",
+		Plural:  "%d files",
+		Plurals: po.PluralEntries{{ID: 0, Str: "%d archivo"}, {ID: 1, Str: "%d archivos"}},
+	},
+	{
+		Flags:   []string{po.FlagFuzzy},
+		ID:      "%d dir",
+		Plural:  "%d dirs",
+		Plurals: po.PluralEntries{{ID: 0, Str: ""}, {ID: 1, Str: ""}},
+	},
+	{ID: "%d item", Plural: "%d items", Plurals: po.PluralEntries{{ID: 0, Str: "%d elemento"}}},
+	{Obsolete: true, ID: "Gone", Str: "Ido"},
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, version := range []xliff.Version{xliff.Version12, xliff.Version20} {
+		t.Run(string(version), func(t *testing.T) {
+			data, err := xliff.ToBytes(input, xliff.WithVersion(version))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), `version="`+string(version)+`"`) {
+				t.Errorf("the document has not the version %s:\n%s", version, data)
+			}
+
+			file, err := xliff.ParseBytes(data, "test.xlf")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(file.Entries) != len(input) {
+				t.Fatalf("expected %d entries, got %d:\n%s", len(input), len(file.Entries), data)
+			}
+			for i := range input {
+				if !reflect.DeepEqual(file.Entries[i], input[i]) {
+					t.Errorf("entry %d: expected %#v, got %#v", i, input[i], file.Entries[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParse12(t *testing.T) {
+	const doc = `<?xml version="1.0" encoding="UTF-8"?>
+<xliff version="1.2" xmlns="urn:oasis:names:tc:xliff:document:1.2">
+  <file original="app.po" source-language="en" target-language="fr" datatype="po">
+    <body>
+      <group id="menus">
+        <trans-unit id="1">
+          <source>Open <g id="1">file</g></source>
+          <target state="needs-review-translation">Ouvrir <g id="1">fichier</g></target>
+          <note>Reviewed by the vendor</note>
+        </trans-unit>
+      </group>
+      <trans-unit id="2">
+        <source>Close</source>
+        <target state="final">Fermer</target>
+        <context-group name="po-entry" purpose="information">
+          <context context-type="x-po-flags">fuzzy</context>
+        </context-group>
+      </trans-unit>
+    </body>
+  </file>
+</xliff>`
+
+	file, err := xliff.ParseBytes([]byte(doc), "app.xlf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := po.Entries{
+		{Comments: []string{"Reviewed by the vendor"}, Flags: []string{po.FlagFuzzy}, ID: "Open file", Str: "Ouvrir fichier"},
+		{ID: "Close", Str: "Fermer"},
+	}
+	if !reflect.DeepEqual(file.Entries, expected) {
+		t.Errorf("expected %#v, got %#v", expected, file.Entries)
+	}
+}
+
+func TestParse20(t *testing.T) {
+	const doc = `<?xml version="1.0" encoding="UTF-8"?>
+<xliff version="2.0" xmlns="urn:oasis:names:tc:xliff:document:2.0" srcLang="en" trgLang="de">
+  <file id="f1">
+    <unit id="1">
+      <segment state="reviewed"><source>Open</source><target>Öffnen</target></segment>
+    </unit>
+    <unit id="2">
+      <segment state="initial"><source>Close</source><target>Schließen</target></segment>
+    </unit>
+    <unit id="3">
+      <segment><source>Quit</source></segment>
+    </unit>
+  </file>
+</xliff>`
+
+	file, err := xliff.ParseBytes([]byte(doc), "app.xlf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := po.Entries{
+		{ID: "Open", Str: "Öffnen"},
+		{Flags: []string{po.FlagFuzzy}, ID: "Close", Str: "Schließen"},
+		{ID: "Quit"},
+	}
+	if !reflect.DeepEqual(file.Entries, expected) {
+		t.Errorf("expected %#v, got %#v", expected, file.Entries)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		doc      string
+		expected error
+	}{
+		{`<tmx version="1.4"></tmx>`, xliff.ErrNotXLIFF},
+		{`<xliff version="3.0"></xliff>`, &xliff.UnsupportedVersionError{}},
+	}
+
+	for _, test := range tests {
+		_, err := xliff.ParseBytes([]byte(test.doc), "test.xlf")
+		var versionErr *xliff.UnsupportedVersionError
+		if errors.As(test.expected, &versionErr) && !errors.As(err, &versionErr) ||
+			errors.Is(test.expected, xliff.ErrNotXLIFF) && !errors.Is(err, xliff.ErrNotXLIFF) {
+			t.Errorf("%s: expected %v, got %v", test.doc, test.expected, err)
+		}
+	}
+
+	if _, err := xliff.ToBytes(input, xliff.WithVersion("3.0")); err == nil {
+		t.Error("expected an error writing an unsupported version")
+	}
+}
+
+func TestInvalidChars(t *testing.T) {
+	for _, entry := range []po.Entry{
+		{ID: "Bell", Str: "Campana\x01"},
+		{ID: "Comment", Str: "Comentario", Comments: []string{"form\ffeed"}},
+		{ID: "Invalid UTF-8", Plural: "\xff", Plurals: po.PluralEntries{{ID: 0, Str: "x"}}},
+	} {
+		var b strings.Builder
+		err := xliff.ToWriter(po.Entries{entry}, &b)
+		var charErr *xliff.InvalidCharError
+		if !errors.As(err, &charErr) || charErr.ID != entry.ID {
+			t.Errorf("%s: expected an InvalidCharError, got %v", entry.ID, err)
+		}
+		if b.Len() != 0 {
+			t.Errorf("%s: the document was partially written", entry.ID)
+		}
+	}
+
+	if _, err := xliff.ToBytes(po.Entries{{ID: "Replacement", Str: "\uFFFD \U0001F600\t"}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
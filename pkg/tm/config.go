@@ -0,0 +1,62 @@
+package tm
+
+import "github.com/Tom5521/gotext-tools/v2/pkg/po"
+
+// DefaultSourceLang is the language of the source messages if the
+// configuration doesn't set one.
+const DefaultSourceLang = "en"
+
+// Config holds the configuration of a [Memory].
+type Config struct {
+	// SourceLang is the language of the source messages of the units
+	// added without one, like the ones of PO and MO files.
+	SourceLang string
+	// TargetLang is the language of the translations of the PO and MO
+	// files added without one whose header has no Language.
+	TargetLang string
+	// Matcher measures the similarity of the messages of the fuzzy
+	// lookups, [po.FstrcmpMatcher] is used if nil.
+	Matcher po.Matcher
+}
+
+// DefaultConfig returns the default configuration, optionally modified by options.
+func DefaultConfig(opts ...Option) Config {
+	c := Config{
+		SourceLang: DefaultSourceLang,
+		Matcher:    po.FstrcmpMatcher{},
+	}
+	c.ApplyOptions(opts...)
+
+	return c
+}
+
+// ApplyOptions applies the options to the configuration.
+func (c *Config) ApplyOptions(opts ...Option) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// Option modifies a Config.
+type Option func(c *Config)
+
+// WithConfig returns an Option that replaces the configuration.
+func WithConfig(config Config) Option {
+	return func(c *Config) { *c = config }
+}
+
+// WithSourceLang returns an Option that sets the language of the source messages.
+func WithSourceLang(lang string) Option {
+	return func(c *Config) { c.SourceLang = lang }
+}
+
+// WithTargetLang returns an Option that sets the language of the translations
+// of the files without a Language header field.
+func WithTargetLang(lang string) Option {
+	return func(c *Config) { c.TargetLang = lang }
+}
+
+// WithMatcher returns an Option that sets the Matcher of the fuzzy lookups.
+func WithMatcher(m po.Matcher) Option {
+	return func(c *Config) { c.Matcher = m }
+}
@@ -0,0 +1,50 @@
+package tm
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNoLanguage = errors.New("the language of the translations is unknown")
+	ErrNoSegments = errors.New("the translation unit has no variants")
+)
+
+// FileError is an error reading or writing the file of a memory.
+type FileError struct {
+	Path   string
+	Reason error
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("translation memory %s: %v", e.Path, e.Reason)
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Reason
+}
+
+// UnitError is an invalid translation unit of a TMX file.
+type UnitError struct {
+	Index  int // Position of the unit in the body of the file.
+	Reason error
+}
+
+func (e *UnitError) Error() string {
+	return fmt.Sprintf("translation unit %d: %v", e.Index, e.Reason)
+}
+
+func (e *UnitError) Unwrap() error {
+	return e.Reason
+}
+
+// InvalidCharError is a character of a unit that XML 1.0 can't represent,
+// like most control characters.
+type InvalidCharError struct {
+	Source string // Source message of the unit.
+	Char   rune
+}
+
+func (e *InvalidCharError) Error() string {
+	return fmt.Sprintf("unit %q: the character %U can't be written in XML 1.0", e.Source, e.Char)
+}
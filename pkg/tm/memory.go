@@ -0,0 +1,365 @@
+package tm
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Tom5521/gotext-tools/v2/internal/slices"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+	"github.com/Tom5521/gotext-tools/v2/pkg/po/parse"
+)
+
+// Unit is the translation of a source message to a language.
+type Unit struct {
+	SourceLang   string
+	TargetLang   string
+	Context      string
+	Source       string
+	SourcePlural string // msgid_plural of plural messages.
+	// Target is the translation, or the first plural form of plural messages.
+	Target string
+	// Plurals are the translations of all the plural forms of plural messages.
+	Plurals []string
+
+	// Metadata.
+
+	Note    string            // Comments of the translator.
+	Origin  string            // File the translation was taken from.
+	Created time.Time         // Creation date, zero if unknown.
+	Changed time.Time         // Date of the last change, zero if unknown.
+	Props   map[string]string // Other properties, like the ones of TMX files.
+}
+
+// IsPlural reports whether the unit translates a plural message.
+func (u Unit) IsPlural() bool {
+	return u.SourcePlural != "" || len(u.Plurals) > 0
+}
+
+// Entry returns the unit as a translated PO entry.
+func (u Unit) Entry() po.Entry {
+	e := po.Entry{
+		Context: u.Context,
+		ID:      u.Source,
+		Plural:  u.SourcePlural,
+	}
+	if u.Note != "" {
+		e.Comments = strings.Split(u.Note, "\n")
+	}
+	if !u.IsPlural() {
+		e.Str = u.Target
+		return e
+	}
+	for i, str := range u.Plurals {
+		e.Plurals = append(e.Plurals, po.PluralEntry{ID: i, Str: str})
+	}
+
+	return e
+}
+
+// sameTranslation reports whether u and o translate the same message to
+// the same language in the same way.
+func (u Unit) sameTranslation(o Unit) bool {
+	return normalizeLang(u.TargetLang) == normalizeLang(o.TargetLang) &&
+		u.Context == o.Context && u.Source == o.Source && u.SourcePlural == o.SourcePlural &&
+		u.Target == o.Target && slices.Equal(u.Plurals, o.Plurals)
+}
+
+// Match is a unit found by a lookup.
+type Match struct {
+	Unit       Unit
+	Similarity int // Similarity of the source messages, from 0 to 100.
+}
+
+// Memory is a translation memory: the translations of source messages to
+// any number of languages, which can be looked up by exact or similar
+// source messages. It can be built from PO, MO and TMX files.
+//
+// A Memory must not be used concurrently.
+type Memory struct {
+	Config Config
+
+	units   []Unit
+	exact   map[string][]int      // Units by language and unified ID of their source.
+	indexes map[string]*langIndex // Indexes of the fuzzy lookups by language.
+}
+
+// langIndex holds the units of a language for the fuzzy lookups.
+type langIndex struct {
+	units   []int
+	entries po.Entries
+	fuzzy   map[int]*po.FuzzyIndex // By threshold.
+}
+
+// New returns an empty Memory.
+func New(opts ...Option) *Memory {
+	return &Memory{
+		Config:  DefaultConfig(opts...),
+		exact:   make(map[string][]int),
+		indexes: make(map[string]*langIndex),
+	}
+}
+
+// normalizeLang returns the language code in the form used to compare them:
+// lower case and with '_' as separator, so "pt-BR" and "pt_br" are the same.
+func normalizeLang(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "-", "_"))
+}
+
+// baseLang returns the language of a code without its territory, like "pt" for "pt_BR".
+func baseLang(lang string) string {
+	lang = normalizeLang(lang)
+	if i := strings.IndexAny(lang, "_@."); i != -1 {
+		return lang[:i]
+	}
+	return lang
+}
+
+func exactKey(lang, context, source string) string {
+	return normalizeLang(lang) + "\x00" + po.Entry{ID: source, Context: context}.UnifiedID()
+}
+
+// Len returns the number of units.
+func (m *Memory) Len() int {
+	return len(m.units)
+}
+
+// Units returns a copy of the units, in the order they were added.
+func (m *Memory) Units() []Unit {
+	return slices.Clone(m.units)
+}
+
+// Languages returns the target languages of the units, in the order they were added.
+func (m *Memory) Languages() []string {
+	var langs []string
+	seen := make(map[string]bool)
+	for _, u := range m.units {
+		if lang := normalizeLang(u.TargetLang); !seen[lang] {
+			seen[lang] = true
+			langs = append(langs, u.TargetLang)
+		}
+	}
+	return langs
+}
+
+// Add adds units to the memory. Units without SourceLang get the one of
+// the configuration. A unit with the same translation as an existing one
+// replaces its metadata if it changed later, instead of being added again.
+func (m *Memory) Add(units ...Unit) {
+	for _, u := range units {
+		if u.SourceLang == "" {
+			u.SourceLang = m.Config.SourceLang
+		}
+		if u.IsPlural() && len(u.Plurals) > 0 {
+			u.Target = u.Plurals[0]
+		}
+
+		key := exactKey(u.TargetLang, u.Context, u.Source)
+		i := slices.IndexFunc(m.exact[key], func(i int) bool { return m.units[i].sameTranslation(u) })
+		if i != -1 {
+			if existing := &m.units[m.exact[key][i]]; !u.Changed.Before(existing.Changed) {
+				if u.Created.IsZero() || !existing.Created.IsZero() && existing.Created.Before(u.Created) {
+					u.Created = existing.Created
+				}
+				*existing = u
+			}
+			continue
+		}
+
+		m.exact[key] = append(m.exact[key], len(m.units))
+		m.units = append(m.units, u)
+		delete(m.indexes, normalizeLang(u.TargetLang))
+	}
+}
+
+// AddEntries adds the translated messages of entries as units translated
+// to lang, or to the Language of their header if lang is empty, or else to
+// the TargetLang of the configuration. Fuzzy and
+// untranslated messages are skipped, and the PO-Revision-Date of the header
+// is used as the date of the change of the units.
+// It returns the number of units added.
+func (m *Memory) AddEntries(entries po.Entries, lang, origin string) (int, error) {
+	header := entries.Header()
+	if lang == "" {
+		lang = strings.TrimSpace(header.Load("Language"))
+	}
+	if lang == "" {
+		lang = m.Config.TargetLang
+	}
+	if lang == "" {
+		return 0, ErrNoLanguage
+	}
+	changed, _ := header.PORevisionTime()
+
+	var units []Unit
+	for _, e := range entries {
+		if e.IsHeader() || e.IsFuzzy() || !e.IsTranslated() {
+			continue
+		}
+		u := Unit{
+			TargetLang:   lang,
+			Context:      e.Context,
+			Source:       e.ID,
+			SourcePlural: e.Plural,
+			Target:       e.Str,
+			Note:         strings.Join(e.Comments, "\n"),
+			Origin:       origin,
+			Changed:      changed,
+		}
+		if e.IsPlural() {
+			plurals := slices.Clone(e.Plurals).Sort()
+			for _, pe := range plurals {
+				u.Plurals = append(u.Plurals, pe.Str)
+			}
+		}
+		units = append(units, u)
+	}
+	m.Add(units...)
+
+	return len(units), nil
+}
+
+// AddFile adds the translations of a file to the memory: TMX files are read
+// with [Memory.ReadTMX], MO files (".mo" and ".gmo") and PO files (any other
+// extension) with [Memory.AddEntries], in the Language of their header or
+// the TargetLang of the configuration.
+func (m *Memory) AddFile(path string) error {
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tmx":
+		err = m.ReadTMXFile(path)
+	case ".mo", ".gmo":
+		var file *po.File
+		if file, err = parse.Mo(path); err == nil {
+			_, err = m.AddEntries(file.Entries, "", path)
+		}
+	default:
+		var file *po.File
+		if file, err = parse.Po(path); err == nil {
+			_, err = m.AddEntries(file.Entries, "", path)
+		}
+	}
+	if err != nil {
+		return &FileError{Path: path, Reason: err}
+	}
+
+	return nil
+}
+
+// AddFiles adds the translations of the files, see [Memory.AddFile].
+func (m *Memory) AddFiles(paths ...string) error {
+	for _, path := range paths {
+		if err := m.AddFile(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookupLanguages returns the normalized languages looked up for lang:
+// lang itself if the memory has translations to it, or else the ones
+// with the same base language. An empty lang looks up all the languages.
+func (m *Memory) lookupLanguages(lang string) []string {
+	var all []string
+	for _, l := range m.Languages() {
+		all = append(all, normalizeLang(l))
+	}
+	if lang == "" {
+		return all
+	}
+	if slices.Contains(all, normalizeLang(lang)) {
+		return []string{normalizeLang(lang)}
+	}
+
+	var langs []string
+	for _, l := range all {
+		if baseLang(l) == baseLang(lang) {
+			langs = append(langs, l)
+		}
+	}
+	return langs
+}
+
+func (m *Memory) index(lang string) *langIndex {
+	if idx, ok := m.indexes[lang]; ok {
+		return idx
+	}
+
+	idx := &langIndex{fuzzy: make(map[int]*po.FuzzyIndex)}
+	for i, u := range m.units {
+		if normalizeLang(u.TargetLang) == lang {
+			idx.units = append(idx.units, i)
+			idx.entries = append(idx.entries, po.Entry{ID: u.Source, Context: u.Context})
+		}
+	}
+	m.indexes[lang] = idx
+
+	return idx
+}
+
+func (m *Memory) matcher() po.Matcher {
+	if m.Config.Matcher == nil {
+		return po.FstrcmpMatcher{}
+	}
+	return m.Config.Matcher
+}
+
+// Lookup returns the units translated to lang whose source message is
+// similar to the message of e, with a similarity of at least threshold,
+// from the most to the least similar. Equally similar units are sorted
+// from the most to the least recently changed.
+//
+// If the memory has no translations to lang, the ones to languages with
+// the same base language are used, like "pt_BR" for "pt". An empty lang
+// looks up all the languages. A threshold of 100 only finds the same message.
+func (m *Memory) Lookup(e po.Entry, lang string, threshold int) []Match {
+	var matches []Match
+	for _, l := range m.lookupLanguages(lang) {
+		if threshold >= 100 {
+			for _, i := range m.exact[l+"\x00"+e.UnifiedID()] {
+				matches = append(matches, Match{Unit: m.units[i], Similarity: 100})
+			}
+			continue
+		}
+
+		idx := m.index(l)
+		fuzzy, ok := idx.fuzzy[threshold]
+		if !ok {
+			fuzzy = po.NewFuzzyIndex(idx.entries, m.matcher(), threshold)
+			idx.fuzzy[threshold] = fuzzy
+		}
+		for _, match := range fuzzy.Search(e) {
+			matches = append(matches, Match{Unit: m.units[idx.units[match.Index]], Similarity: match.Similarity})
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b Match) int {
+		switch {
+		case a.Similarity != b.Similarity:
+			return b.Similarity - a.Similarity
+		case a.Unit.Changed.After(b.Unit.Changed):
+			return -1
+		case b.Unit.Changed.After(a.Unit.Changed):
+			return 1
+		}
+		return 0
+	})
+
+	return matches
+}
+
+// Suggest returns the translation to lang of the most similar message to
+// e, see [Memory.Lookup]. Only the units that are plural if e is plural,
+// and singular otherwise, are suggested.
+//
+// It implements [po.TranslationMemory].
+func (m *Memory) Suggest(e po.Entry, lang string, threshold int) (po.Entry, int, bool) {
+	for _, match := range m.Lookup(e, lang, threshold) {
+		if match.Unit.IsPlural() == (e.Plural != "") {
+			return match.Unit.Entry(), match.Similarity, true
+		}
+	}
+	return po.Entry{}, 0, false
+}
+
+var _ po.TranslationMemory = (*Memory)(nil)
@@ -0,0 +1,166 @@
+package tm_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+	"github.com/Tom5521/gotext-tools/v2/pkg/tm"
+)
+
+func newMemory(t *testing.T) *tm.Memory {
+	t.Helper()
+
+	m := tm.New()
+	entries := po.Entries{
+		{Str: "Language: es\nPO-Revision-Date: 2023-01-02 03:04+0000\n"},
+		{ID: "Open the file", Str: "Abrir el archivo"},
+		{ID: "Open", Context: "menu", Str: "Abrir"},
+		{ID: "Close", Flags: []string{po.FlagFuzzy}, Str: "Cerrar"},
+		{ID: "Quit"},
+		{
+			ID:     "%d file",
+			Plural: "%d files",
+			Plurals: po.PluralEntries{
+				{ID: 1, Str: "%d archivos"},
+				{ID: 0, Str: "%d archivo"},
+			},
+		},
+	}
+	n, err := m.AddEntries(entries, "", "es.po")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3 units, got %d", n)
+	}
+	if _, err = m.AddEntries(po.Entries{{ID: "Open the file", Str: "Abrir o arquivo"}}, "pt_BR", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	return m
+}
+
+func TestAddEntries(t *testing.T) {
+	m := newMemory(t)
+	if m.Len() != 4 {
+		t.Fatalf("expected 4 units, got %d", m.Len())
+	}
+
+	u := m.Units()[0]
+	if u.TargetLang != "es" || u.SourceLang != tm.DefaultSourceLang || u.Origin != "es.po" {
+		t.Errorf("unexpected languages or origin: %+v", u)
+	}
+	if expected := time.Date(2023, 1, 2, 3, 4, 0, 0, time.UTC); !u.Changed.Equal(expected) {
+		t.Errorf("expected %v as the date of the change, got %v", expected, u.Changed)
+	}
+	if plural := m.Units()[2]; plural.Target != "%d archivo" || len(plural.Plurals) != 2 ||
+		plural.Plurals[1] != "%d archivos" {
+		t.Errorf("unexpected plural unit: %+v", plural)
+	}
+
+	if _, err := m.AddEntries(po.Entries{{ID: "Open", Str: "Abrir"}}, "", ""); !errors.Is(err, tm.ErrNoLanguage) {
+		t.Errorf("expected %v, got %v", tm.ErrNoLanguage, err)
+	}
+	fallback := tm.New(tm.WithTargetLang("es"))
+	if n, err := fallback.AddEntries(po.Entries{{ID: "Open", Str: "Abrir"}}, "", ""); err != nil || n != 1 ||
+		fallback.Units()[0].TargetLang != "es" {
+		t.Errorf("the language of the configuration wasn't used: %v %+v", err, fallback.Units())
+	}
+
+	// The same translation isn't added again, but updates the metadata.
+	later := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.Add(tm.Unit{TargetLang: "es", Source: "Open the file", Target: "Abrir el archivo", Changed: later})
+	if m.Len() != 4 {
+		t.Errorf("a repeated translation was added: %d units", m.Len())
+	}
+	if u = m.Units()[0]; !u.Changed.Equal(later) {
+		t.Errorf("the date of the change wasn't updated: %v", u.Changed)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	m := newMemory(t)
+
+	tests := []struct {
+		name      string
+		entry     po.Entry
+		lang      string
+		threshold int
+		expected  []string
+	}{
+		{"Exact", po.Entry{ID: "Open the file"}, "es", 100, []string{"Abrir el archivo"}},
+		{"Context", po.Entry{ID: "Open"}, "es", 100, nil},
+		{"Fuzzy", po.Entry{ID: "Open the files"}, "es", 60, []string{"Abrir el archivo"}},
+		{"Territory", po.Entry{ID: "Open the file"}, "es_AR", 100, []string{"Abrir el archivo"}},
+		{"Base language", po.Entry{ID: "Open the file"}, "pt", 100, []string{"Abrir o arquivo"}},
+		{"All languages", po.Entry{ID: "Open the file"}, "", 100, []string{"Abrir el archivo", "Abrir o arquivo"}},
+		{"Unknown language", po.Entry{ID: "Open the file"}, "fr", 60, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matches := m.Lookup(test.entry, test.lang, test.threshold)
+			var got []string
+			for _, match := range matches {
+				got = append(got, match.Unit.Target)
+				if match.Similarity < test.threshold {
+					t.Errorf("%q has a similarity lower than the threshold: %d", match.Unit.Source, match.Similarity)
+				}
+			}
+			if len(got) != len(test.expected) {
+				t.Fatalf("expected %q, got %q", test.expected, got)
+			}
+			for i := range got {
+				if got[i] != test.expected[i] {
+					t.Errorf("expected %q, got %q", test.expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	m := newMemory(t)
+
+	e, similarity, ok := m.Suggest(po.Entry{ID: "%d files", Plural: "%d filess"}, "es", 60)
+	if !ok || similarity == 100 || len(e.Plurals) != 2 || e.Plurals[0].Str != "%d archivo" {
+		t.Errorf("unexpected plural suggestion %v with similarity %d", e, similarity)
+	}
+	// A singular message doesn't get the translation of a plural one.
+	if e, _, ok = m.Suggest(po.Entry{ID: "%d file"}, "es", 100); ok {
+		t.Errorf("unexpected suggestion for a singular message: %v", e)
+	}
+}
+
+func TestPretranslate(t *testing.T) {
+	m := newMemory(t)
+	entries := po.Entries{
+		{Str: "Language: es\n"},
+		{ID: "Open the file"},
+		{ID: "Open the files"},
+		{ID: "Save"},
+		{ID: "Close", Str: "Cerrar"},
+	}
+
+	report := m.Pretranslate(entries, tm.PretranslateConfig{Previous: true})
+	if report.Totals != (po.MergeTotals{Exact: 1, Fuzzy: 1, New: 1}) {
+		t.Errorf("unexpected totals: %s", report.Totals)
+	}
+	if entries[1].Str != "Abrir el archivo" || entries[1].IsFuzzy() {
+		t.Errorf("unexpected exact translation: %v", entries[1])
+	}
+	if entries[2].Str != "Abrir el archivo" || !entries[2].IsFuzzy() || entries[2].Previous.ID != "Open the file" {
+		t.Errorf("unexpected fuzzy translation: %v", entries[2])
+	}
+	if entries[3].Str != "" || entries[4].Str != "Cerrar" {
+		t.Errorf("unexpected translations: %v", entries[3:])
+	}
+
+	// Without a language any language could be suggested.
+	entries = po.Entries{{ID: "Open the file"}}
+	if report = m.Pretranslate(entries, tm.PretranslateConfig{}); report.Totals.New != 1 || entries[0].Str != "" {
+		t.Errorf("a message was translated without knowing the language: %v", entries)
+	}
+}
@@ -0,0 +1,75 @@
+package tm
+
+import (
+	"strings"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/po"
+)
+
+// PretranslateConfig holds the options of [Memory.Pretranslate].
+type PretranslateConfig struct {
+	// Language of the translations, the Language of the header is used if
+	// empty. No message is translated if both are empty.
+	Language string
+	// Threshold is the minimum similarity of the translations, from 1 to 100.
+	// [po.DefaultFuzzyThreshold] is used if zero, and 100 only uses the
+	// translations of the same messages.
+	Threshold int
+	// Previous records the message a fuzzy translation comes from in [po.Entry.Previous].
+	Previous bool
+}
+
+// Pretranslate translates the untranslated messages of entries with the
+// translation of the most similar message of the memory. The translations
+// of different messages are marked as fuzzy. Obsolete messages are skipped.
+//
+// The report lists the translated messages as [po.MergeExact] and
+// [po.MergeFuzzy], and the rest of the untranslated ones as [po.MergeNew].
+func (m *Memory) Pretranslate(entries po.Entries, config PretranslateConfig) po.MergeReport {
+	lang := config.Language
+	if lang == "" {
+		header := entries.Header()
+		lang = strings.TrimSpace(header.Load("Language"))
+	}
+	threshold := config.Threshold
+	if threshold == 0 {
+		threshold = po.DefaultFuzzyThreshold
+	}
+
+	var report po.MergeReport
+	for i := range entries {
+		e := &entries[i]
+		if e.IsHeader() || e.Obsolete || e.IsTranslated() {
+			continue
+		}
+
+		var suggestion po.Entry
+		var similarity int
+		var ok bool
+		if lang != "" {
+			suggestion, similarity, ok = m.Suggest(*e, lang, threshold)
+		}
+		if !ok {
+			report.Add(po.MergeResult{Outcome: po.MergeNew, Context: e.Context, ID: e.ID})
+			continue
+		}
+
+		e.Str, e.Plurals = suggestion.Str, suggestion.Plurals
+		result := po.MergeResult{Outcome: po.MergeExact, Context: e.Context, ID: e.ID, Memory: true}
+		if similarity < 100 {
+			e.AddFlag(po.FlagFuzzy)
+			previous := po.PreviousMessage{Context: suggestion.Context, ID: suggestion.ID, Plural: suggestion.Plural}
+			if config.Previous {
+				e.Previous = previous
+			}
+			result.Outcome = po.MergeFuzzy
+			result.Matched = &previous
+			result.Similarity = similarity
+		} else {
+			e.RemoveFlag(po.FlagFuzzy)
+		}
+		report.Add(result)
+	}
+
+	return report
+}
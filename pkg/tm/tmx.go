@@ -0,0 +1,401 @@
+package tm
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tom5521/gotext-tools/v2/internal/slices"
+	"github.com/Tom5521/gotext-tools/v2/internal/util"
+)
+
+// TMX properties used to keep the gettext specific fields of the units.
+// The translations of the plural forms after the first one are kept in
+// the "x-plural-N" properties of their variant.
+const (
+	PropContext      = "x-context"
+	PropSourcePlural = "x-msgid-plural"
+	PropOrigin       = "x-origin"
+	propPluralPrefix = "x-plural-"
+)
+
+// tmxDateLayout is the format of the dates of TMX files.
+const tmxDateLayout = "20060102T150405Z"
+
+// tmxAllLanguages is the srclang of the documents whose units can have any source language.
+const tmxAllLanguages = "*all*"
+
+type tmxDocument struct {
+	XMLName xml.Name  `xml:"tmx"`
+	Version string    `xml:"version,attr"`
+	Header  tmxHeader `xml:"header"`
+	Units   []tmxUnit `xml:"body>tu"`
+}
+
+type tmxHeader struct {
+	CreationTool        string `xml:"creationtool,attr"`
+	CreationToolVersion string `xml:"creationtoolversion,attr"`
+	SegType             string `xml:"segtype,attr"`
+	DataFormat          string `xml:"o-tmf,attr"`
+	AdminLang           string `xml:"adminlang,attr"`
+	SrcLang             string `xml:"srclang,attr"`
+	DataType            string `xml:"datatype,attr"`
+	CreationDate        string `xml:"creationdate,attr,omitempty"`
+}
+
+type tmxUnit struct {
+	SrcLang      string       `xml:"srclang,attr,omitempty"`
+	CreationDate string       `xml:"creationdate,attr,omitempty"`
+	ChangeDate   string       `xml:"changedate,attr,omitempty"`
+	Notes        []string     `xml:"note"`
+	Props        []tmxProp    `xml:"prop"`
+	Variants     []tmxVariant `xml:"tuv"`
+}
+
+type tmxProp struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type tmxVariant struct {
+	Lang string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	// LegacyLang is the "lang" attribute used by TMX 1.1 instead of "xml:lang".
+	LegacyLang   string    `xml:"lang,attr,omitempty"`
+	CreationDate string    `xml:"creationdate,attr,omitempty"`
+	ChangeDate   string    `xml:"changedate,attr,omitempty"`
+	Notes        []string  `xml:"note"`
+	Props        []tmxProp `xml:"prop"`
+	Seg          tmxSeg    `xml:"seg"`
+}
+
+func (v tmxVariant) lang() string {
+	if v.Lang != "" {
+		return v.Lang
+	}
+	return v.LegacyLang
+}
+
+// tmxSeg is the text of a segment. The content of its inline elements,
+// like the native codes of <ph> and <bpt>, is part of the text.
+type tmxSeg string
+
+func (s *tmxSeg) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var b strings.Builder
+	for depth := 1; depth > 0; {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			b.Write(t)
+		}
+	}
+	*s = tmxSeg(b.String())
+
+	return nil
+}
+
+func (s tmxSeg) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(string(s), start)
+}
+
+// tmxLang returns a language code as a BCP 47 tag, like "pt-BR" for "pt_BR".
+func tmxLang(lang string) string {
+	return strings.ReplaceAll(lang, "_", "-")
+}
+
+func parseTMXDate(date string) time.Time {
+	t, err := time.Parse(tmxDateLayout, date)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+func formatTMXDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(tmxDateLayout)
+}
+
+// ReadTMX adds the translation units of a TMX document to the memory.
+//
+// Every variant of a unit, except the one in the source language, is
+// added as a [Unit]. The source language is the srclang of the unit, or of
+// the header; if it's "*all*" or no variant has it, the first one is
+// used. The origin is used for the units without an "x-origin" property.
+func (m *Memory) ReadTMX(r io.Reader, origin string) error {
+	var doc tmxDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return err
+	}
+
+	var units []Unit
+	for i, tu := range doc.Units {
+		unitUnits, err := tmxUnitToUnits(tu, doc.Header.SrcLang, origin)
+		if err != nil {
+			return &UnitError{Index: i, Reason: err}
+		}
+		units = append(units, unitUnits...)
+	}
+	m.Add(units...)
+
+	return nil
+}
+
+func tmxUnitToUnits(tu tmxUnit, srcLang, origin string) ([]Unit, error) {
+	if len(tu.Variants) == 0 {
+		return nil, ErrNoSegments
+	}
+	if tu.SrcLang != "" {
+		srcLang = tu.SrcLang
+	}
+
+	source := 0
+	if srcLang != tmxAllLanguages {
+		for i, v := range tu.Variants {
+			if normalizeLang(v.lang()) == normalizeLang(srcLang) {
+				source = i
+				break
+			}
+		}
+	}
+	src := tu.Variants[source]
+
+	props := make(map[string]string)
+	for _, p := range tu.Props {
+		props[p.Type] = p.Value
+	}
+
+	var units []Unit
+	for i, v := range tu.Variants {
+		if i == source {
+			continue
+		}
+
+		u := Unit{
+			SourceLang:   src.lang(),
+			TargetLang:   v.lang(),
+			Context:      props[PropContext],
+			Source:       string(src.Seg),
+			SourcePlural: props[PropSourcePlural],
+			Target:       string(v.Seg),
+			Note:         strings.Join(append(slices.Clone(tu.Notes), v.Notes...), "\n"),
+			Origin:       origin,
+			Created:      parseTMXDate(tu.CreationDate),
+			Changed:      parseTMXDate(tu.ChangeDate),
+		}
+		if v.CreationDate != "" {
+			u.Created = parseTMXDate(v.CreationDate)
+		}
+		if v.ChangeDate != "" {
+			u.Changed = parseTMXDate(v.ChangeDate)
+		}
+
+		variantProps := make(map[string]string, len(props)+len(v.Props))
+		for key, value := range props {
+			variantProps[key] = value
+		}
+		for _, p := range v.Props {
+			variantProps[p.Type] = p.Value
+		}
+		if value, ok := variantProps[PropOrigin]; ok {
+			u.Origin = value
+		}
+		if u.SourcePlural != "" {
+			u.Plurals = append(u.Plurals, u.Target)
+			for n := 1; ; n++ {
+				value, ok := variantProps[propPluralPrefix+strconv.Itoa(n)]
+				if !ok {
+					break
+				}
+				u.Plurals = append(u.Plurals, value)
+			}
+		}
+
+		for key, value := range variantProps {
+			if key == PropContext || key == PropSourcePlural || key == PropOrigin ||
+				strings.HasPrefix(key, propPluralPrefix) {
+				continue
+			}
+			if u.Props == nil {
+				u.Props = make(map[string]string)
+			}
+			u.Props[key] = value
+		}
+		units = append(units, u)
+	}
+
+	return units, nil
+}
+
+// ReadTMXFile adds the translation units of a TMX file to the memory, see [Memory.ReadTMX].
+func (m *Memory) ReadTMXFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return m.ReadTMX(f, path)
+}
+
+// WriteTMX writes the memory as a TMX 1.4b document.
+//
+// The units that translate the same message from the same language are
+// written as the variants of the same translation unit. The metadata of
+// each unit is written in its variant.
+//
+// It returns an [InvalidCharError] without writing anything if a unit has
+// a character that XML 1.0 can't represent.
+func (m *Memory) WriteTMX(w io.Writer) error {
+	for _, u := range m.units {
+		if err := u.checkChars(); err != nil {
+			return err
+		}
+	}
+
+	doc := tmxDocument{
+		Version: "1.4",
+		Header: tmxHeader{
+			CreationTool:        "gotext-tools",
+			CreationToolVersion: "2",
+			SegType:             "sentence",
+			DataFormat:          "PO",
+			AdminLang:           tmxLang(m.Config.SourceLang),
+			SrcLang:             tmxLang(m.Config.SourceLang),
+			DataType:            "plaintext",
+		},
+	}
+
+	// Translation units by source message, and the languages they have.
+	type group struct {
+		units []int
+		langs []map[string]bool
+	}
+	groups := make(map[string]*group)
+	for _, u := range m.units {
+		key := normalizeLang(u.SourceLang) + "\x00" + exactKey("", u.Context, u.Source) + "\x00" + u.SourcePlural
+		g, ok := groups[key]
+		if !ok {
+			g = &group{}
+			groups[key] = g
+		}
+
+		lang := normalizeLang(u.TargetLang)
+		i := 0
+		for i < len(g.units) && g.langs[i][lang] {
+			i++
+		}
+		if i == len(g.units) {
+			g.units = append(g.units, len(doc.Units))
+			g.langs = append(g.langs, map[string]bool{})
+			doc.Units = append(doc.Units, newTMXUnit(u, m.Config.SourceLang))
+		}
+		g.langs[i][lang] = true
+
+		tu := &doc.Units[g.units[i]]
+		tu.Variants = append(tu.Variants, newTMXVariant(u))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+// checkChars returns an error for the first string of u that encoding/xml
+// would write with a replacement character.
+func (u Unit) checkChars() error {
+	strs := []string{u.SourceLang, u.TargetLang, u.Context, u.Source, u.SourcePlural, u.Target, u.Note, u.Origin}
+	strs = append(strs, u.Plurals...)
+	for k, v := range u.Props {
+		strs = append(strs, k, v)
+	}
+
+	for _, str := range strs {
+		if r, ok := util.InvalidXMLChar(str); ok {
+			return &InvalidCharError{Source: u.Source, Char: r}
+		}
+	}
+	return nil
+}
+
+// newTMXUnit returns a translation unit with the variant of the source message of u.
+func newTMXUnit(u Unit, srcLang string) tmxUnit {
+	tu := tmxUnit{
+		Variants: []tmxVariant{{Lang: tmxLang(u.SourceLang), Seg: tmxSeg(u.Source)}},
+	}
+	if normalizeLang(u.SourceLang) != normalizeLang(srcLang) {
+		tu.SrcLang = tmxLang(u.SourceLang)
+	}
+	if u.Context != "" {
+		tu.Props = append(tu.Props, tmxProp{Type: PropContext, Value: u.Context})
+	}
+	if u.SourcePlural != "" {
+		tu.Props = append(tu.Props, tmxProp{Type: PropSourcePlural, Value: u.SourcePlural})
+	}
+
+	return tu
+}
+
+func newTMXVariant(u Unit) tmxVariant {
+	v := tmxVariant{
+		Lang:         tmxLang(u.TargetLang),
+		CreationDate: formatTMXDate(u.Created),
+		ChangeDate:   formatTMXDate(u.Changed),
+		Seg:          tmxSeg(u.Target),
+	}
+	if u.Note != "" {
+		v.Notes = []string{u.Note}
+	}
+	if u.Origin != "" {
+		v.Props = append(v.Props, tmxProp{Type: PropOrigin, Value: u.Origin})
+	}
+	for n := 1; n < len(u.Plurals); n++ {
+		v.Props = append(v.Props, tmxProp{Type: fmt.Sprintf("%s%d", propPluralPrefix, n), Value: u.Plurals[n]})
+	}
+
+	keys := make([]string, 0, len(u.Props))
+	for key := range u.Props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		v.Props = append(v.Props, tmxProp{Type: key, Value: u.Props[key]})
+	}
+
+	return v
+}
+
+// WriteTMXFile writes the memory to a TMX file, see [Memory.WriteTMX].
+func (m *Memory) WriteTMXFile(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err = m.WriteTMX(f); err != nil {
+		return err
+	}
+	return f.Close()
+}
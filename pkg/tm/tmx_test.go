@@ -0,0 +1,145 @@
+package tm_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tom5521/gotext-tools/v2/pkg/tm"
+)
+
+func TestTMXRoundTrip(t *testing.T) {
+	created := time.Date(2022, 5, 6, 7, 8, 9, 0, time.UTC)
+	changed := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	units := []tm.Unit{
+		{
+			SourceLang: "en",
+			TargetLang: "es",
+			Context:    "menu",
+			Source:     "Open",
+			Target:     "Abrir",
+			Note:       "Verb",
+			Origin:     "es.po",
+			Created:    created,
+			Changed:    changed,
+			Props:      map[string]string{"x-project": "app"},
+		},
+		{SourceLang: "en", TargetLang: "pt_BR", Context: "menu", Source: "Open", Target: "Abrir"},
+		{
+			SourceLang:   "en",
+			TargetLang:   "es",
+			Source:       "%d file",
+			SourcePlural: "%d files",
+			Target:       "%d archivo",
+			Plurals:      []string{"%d archivo", "%d archivos"},
+		},
+	}
+
+	m := tm.New()
+	m.Add(units...)
+
+	var buf bytes.Buffer
+	if err := m.WriteTMX(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `xml:lang="pt-BR"`) {
+		t.Errorf("the variants must have a xml:lang attribute:\n%s", buf.String())
+	}
+	if n := strings.Count(buf.String(), "</tu>"); n != 2 {
+		t.Errorf("expected 2 translation units, got %d:\n%s", n, buf.String())
+	}
+
+	read := tm.New()
+	if err := read.ReadTMX(&buf, ""); err != nil {
+		t.Fatal(err)
+	}
+	got := read.Units()
+	if len(got) != len(units) {
+		t.Fatalf("expected %d units, got %d", len(units), len(got))
+	}
+	units[1].TargetLang = "pt-BR"
+	for i := range units {
+		if !got[i].Created.Equal(units[i].Created) || !got[i].Changed.Equal(units[i].Changed) {
+			t.Errorf("unit %d: expected the dates %v and %v, got %v and %v",
+				i, units[i].Created, units[i].Changed, got[i].Created, got[i].Changed)
+		}
+		got[i].Created, got[i].Changed = units[i].Created, units[i].Changed
+		if !reflect.DeepEqual(got[i], units[i]) {
+			t.Errorf("unit %d: expected %+v, got %+v", i, units[i], got[i])
+		}
+	}
+}
+
+func TestWriteTMXInvalidChars(t *testing.T) {
+	for _, u := range []tm.Unit{
+		{TargetLang: "es", Source: "Ring\a bell", Target: "Tocar la campana"},
+		{TargetLang: "es", Source: "Open", Target: "Abrir", Props: map[string]string{"x-note": "\x01"}},
+		{TargetLang: "es", Source: "Invalid UTF-8", Plurals: []string{"\xff"}},
+	} {
+		m := tm.New()
+		m.Add(u)
+
+		var buf bytes.Buffer
+		err := m.WriteTMX(&buf)
+		var charErr *tm.InvalidCharError
+		if !errors.As(err, &charErr) || charErr.Source != u.Source {
+			t.Errorf("%q: expected an InvalidCharError, got %v", u.Source, err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("%q: the document was partially written", u.Source)
+		}
+	}
+}
+
+func TestReadTMX(t *testing.T) {
+	const input = `<?xml version="1.0" encoding="UTF-8"?>
+<tmx version="1.4">
+  <header creationtool="test" creationtoolversion="1" segtype="sentence"
+    o-tmf="test" adminlang="en" srclang="*all*" datatype="plaintext"/>
+  <body>
+    <tu changedate="20230102T030405Z">
+      <note>Shown in the toolbar</note>
+      <tuv xml:lang="de"><seg>Datei <ph x="1">%s</ph> öffnen</seg></tuv>
+      <tuv xml:lang="fr"><seg>Ouvrir le fichier <ph x="1">%s</ph></seg></tuv>
+    </tu>
+    <tu srclang="en">
+      <tuv lang="fr"><seg>Fermer</seg></tuv>
+      <tuv lang="en"><seg>Close</seg></tuv>
+    </tu>
+  </body>
+</tmx>`
+
+	m := tm.New()
+	if err := m.ReadTMX(strings.NewReader(input), "input.tmx"); err != nil {
+		t.Fatal(err)
+	}
+
+	units := m.Units()
+	if len(units) != 2 {
+		t.Fatalf("expected 2 units, got %+v", units)
+	}
+	expected := tm.Unit{
+		SourceLang: "de",
+		TargetLang: "fr",
+		Source:     "Datei %s öffnen",
+		Target:     "Ouvrir le fichier %s",
+		Note:       "Shown in the toolbar",
+		Origin:     "input.tmx",
+		Changed:    time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(units[0], expected) {
+		t.Errorf("expected %+v, got %+v", expected, units[0])
+	}
+	if units[1].SourceLang != "en" || units[1].Source != "Close" || units[1].Target != "Fermer" {
+		t.Errorf("unexpected unit: %+v", units[1])
+	}
+
+	err := m.ReadTMX(strings.NewReader(`<tmx version="1.4"><body><tu></tu></body></tmx>`), "")
+	var unitErr *tm.UnitError
+	if !errors.As(err, &unitErr) || !errors.Is(err, tm.ErrNoSegments) {
+		t.Errorf("expected a %v UnitError, got %v", tm.ErrNoSegments, err)
+	}
+}